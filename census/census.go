@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +33,17 @@ type Namespaces struct {
 type Namespace struct {
 	Name string   `json:"name"`
 	Keys []string `json:"keys"`
+	URI  string   `json:"uri,omitempty"` // Remote storage URI of a published census
+	// Transferred is set once the census ownership has been transferred, so
+	// only Keys can manage it, and not the owner of its name prefix
+	Transferred bool `json:"transferred,omitempty"`
+	// Imported is set on the census imported from the remote storage, which
+	// can be removed once no process uses them
+	Imported bool `json:"imported,omitempty"`
+	// Private is set until the census is published, so a census whose claims
+	// were being imported is not public once loaded again. Older versions did
+	// not keep it, since every census was public once loaded.
+	Private bool `json:"private,omitempty"`
 }
 
 // Manager is the type representing the census manager component
@@ -46,6 +56,17 @@ type Manager struct {
 	TreesMu sync.RWMutex
 	Trees   map[string]censustree.Tree // MkTrees map of merkle trees indexed by censusId
 
+	// TreeIdleTimeout is the time a loaded tree can be unused before being
+	// closed, 0 disables it. MaxLoadedTrees is the maximum number of loaded
+	// trees, the least recently used are closed first, 0 means no limit.
+	// Closed trees are loaded again on demand.
+	TreeIdleTimeout time.Duration
+	MaxLoadedTrees  int
+	treesInUse      map[string]int
+	// treesDeleted are the trees of the namespaces removed while in use,
+	// which are destroyed on their last ReleaseTree
+	treesDeleted map[string]bool
+
 	RemoteStorage data.Storage // e.g. IPFS
 
 	importQueue     chan censusImport
@@ -54,6 +75,8 @@ type Manager struct {
 	failedQueue     map[string]string
 	compressor
 	newTreeFunc func(name, storage string) (censustree.Tree, error)
+	keyAudit    keyAudit
+	rootHistory rootHistory
 }
 
 // Data helps satisfy an ethevents interface.
@@ -65,6 +88,8 @@ func (m *Manager) Init(storageDir, rootKey string, newTreeImpl func(name, storag
 	nsConfig := fmt.Sprintf("%s/namespaces.json", storageDir)
 	m.StorageDir = storageDir
 	m.Trees = make(map[string]censustree.Tree)
+	m.treesInUse = make(map[string]int)
+	m.treesDeleted = make(map[string]bool)
 	m.failedQueue = make(map[string]string)
 	if newTreeImpl == nil {
 		return fmt.Errorf("missing census tree implementation")
@@ -81,6 +106,7 @@ func (m *Manager) Init(storageDir, rootKey string, newTreeImpl func(name, storag
 		go m.importQueueDaemon()
 	}
 	go m.importFailedQueueDaemon()
+	go m.unloadDaemon()
 
 	log.Infof("loading namespaces and keys from %s", nsConfig)
 	if _, err := os.Stat(nsConfig); os.IsNotExist(err) {
@@ -111,11 +137,8 @@ func (m *Manager) Init(storageDir, rootKey string, newTreeImpl func(name, storag
 	} else if rootKey != "" {
 		log.Infof("current root key %s", rootKey)
 	}
-	for _, v := range m.Census.Namespaces {
-		if _, err := m.LoadTree(v.Name); err != nil {
-			log.Warnf("census %s cannot be loaded: (%v)", v.Name, err)
-		}
-	}
+	// the trees are loaded on demand
+	log.Infof("%d census available", len(m.Census.Namespaces))
 	return nil
 }
 
@@ -131,15 +154,45 @@ func (m *Manager) LoadTree(name string) (censustree.Tree, error) {
 	}
 	log.Infof("load merkle tree %s", name)
 	m.Trees[name] = tr
-	m.Trees[name].Publish()
+	for _, ns := range m.Census.Namespaces {
+		if ns.Name == name && !ns.Private {
+			tr.Publish()
+			break
+		}
+	}
 	return tr, nil
 }
 
+// PublishTree makes the census name public, and keeps it public once loaded
+// again
+func (m *Manager) PublishTree(name string) error {
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	for i := range m.Census.Namespaces {
+		if m.Census.Namespaces[i].Name == name {
+			if tr, ok := m.Trees[name]; ok {
+				tr.Publish()
+			}
+			m.Census.Namespaces[i].Private = false
+			return m.save()
+		}
+	}
+	return fmt.Errorf("namespace %s does not exist", name)
+}
+
 // UnloadTree closes the database containing the merkle tree
 // Not thread safe
 func (m *Manager) UnloadTree(name string) {
+	tr, ok := m.Trees[name]
+	if !ok {
+		return
+	}
 	log.Debugf("unload merkle tree %s", name)
+	tr.UnPublish()
 	delete(m.Trees, name)
+	if err := tr.Close(); err != nil {
+		log.Warnf("cannot close census %s: (%v)", name, err)
+	}
 }
 
 // Exists returns true if a given census exists on disk
@@ -156,26 +209,34 @@ func (m *Manager) Exists(name string) bool {
 }
 
 // AddNamespace adds a new merkletree identified by a censusId (name), and
-// returns the new tree.
+// returns the new tree, which is not public until PublishTree is called.
 func (m *Manager) AddNamespace(name string, pubKeys []string) (censustree.Tree, error) {
 	m.TreesMu.Lock()
 	defer m.TreesMu.Unlock()
 	if m.Exists(name) {
 		return nil, ErrNamespaceExist
 	}
+	if m.treesDeleted[name] {
+		return nil, fmt.Errorf("namespace %s is being removed", name)
+	}
 	tr, err := m.newTreeFunc(name, m.StorageDir)
 	if err != nil {
 		return nil, err
 	}
 	m.Trees[name] = tr
 	m.Census.Namespaces = append(m.Census.Namespaces, Namespace{
-		Name: name,
-		Keys: pubKeys,
+		Name:    name,
+		Keys:    pubKeys,
+		Private: true,
 	})
+	if err := m.appendRoot(name, tr.Root()); err != nil {
+		log.Warnf("cannot save the root history of census %s: (%v)", name, err)
+	}
 	return tr, m.save()
 }
 
-// DelNamespace removes a merkletree namespace
+// DelNamespace removes a merkletree namespace and its tree from disk. If the
+// tree is in use, it is destroyed once its last ReleaseTree is called.
 func (m *Manager) DelNamespace(name string) error {
 	if len(name) == 0 {
 		return fmt.Errorf("no valid namespace provided")
@@ -185,10 +246,12 @@ func (m *Manager) DelNamespace(name string) error {
 	if !m.Exists(name) {
 		return nil
 	}
-	// TODO(mvdan): re-implement with the prefixed database
-	// if err := os.RemoveAll(m.StorageDir + "/" + name); err != nil {
-	// 	return fmt.Errorf("cannot remove census: (%s)", err)
-	// }
+	if m.treesInUse[name] > 0 {
+		m.Trees[name].UnPublish()
+		m.treesDeleted[name] = true
+	} else if err := m.destroyTree(name); err != nil {
+		return err
+	}
 
 	for i, ns := range m.Census.Namespaces {
 		if ns.Name == name {
@@ -200,6 +263,27 @@ func (m *Manager) DelNamespace(name string) error {
 	return m.save()
 }
 
+// destroyTree removes the tree of the namespace name and its root history
+// from disk. Not thread safe.
+func (m *Manager) destroyTree(name string) error {
+	tr, ok := m.Trees[name]
+	if !ok {
+		var err error
+		if tr, err = m.newTreeFunc(name, m.StorageDir); err != nil {
+			return fmt.Errorf("cannot open census: (%s)", err)
+		}
+	}
+	tr.UnPublish()
+	delete(m.Trees, name)
+	if err := tr.Destroy(); err != nil {
+		return fmt.Errorf("cannot remove census: (%s)", err)
+	}
+	if err := m.delRoots(name); err != nil {
+		log.Warnf("cannot remove the root history of census %s: (%v)", name, err)
+	}
+	return nil
+}
+
 func (m *Manager) save() error {
 	log.Debug("saving namespaces")
 	nsConfig := fmt.Sprintf("%s/namespaces.json", m.StorageDir)
@@ -213,10 +297,10 @@ func (m *Manager) save() error {
 // Count returns the number of local created, external imported and loaded/active census
 func (m *Manager) Count() (local, imported, loaded int) {
 	for _, n := range m.Census.Namespaces {
-		if strings.Contains(n.Name, "/") {
-			local++
-		} else {
+		if n.Imported {
 			imported++
+		} else {
+			local++
 		}
 	}
 	loaded = len(m.Trees)
@@ -2,10 +2,62 @@ package census
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"go.vocdoni.io/dvote/censustree/gravitontree"
+	"go.vocdoni.io/dvote/metrics"
+	"go.vocdoni.io/dvote/types"
 )
 
+// memStorage is a remote storage which keeps the published files in memory
+type memStorage struct {
+	lock  sync.Mutex
+	files map[string][]byte
+}
+
+func (s *memStorage) Init(d *types.DataStore) error { return nil }
+
+func (s *memStorage) Publish(ctx context.Context, o []byte) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.files == nil {
+		s.files = make(map[string][]byte)
+	}
+	id := fmt.Sprintf("file%d", len(s.files))
+	s.files[id] = o
+	return id, nil
+}
+
+func (s *memStorage) Retrieve(ctx context.Context, id string) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	o, ok := s.files[id]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", id)
+	}
+	return o, nil
+}
+
+func (s *memStorage) Pin(ctx context.Context, path string) error   { return nil }
+func (s *memStorage) Unpin(ctx context.Context, path string) error { return nil }
+func (s *memStorage) ListPins(ctx context.Context) (map[string]string, error) {
+	return nil, nil
+}
+func (s *memStorage) URIprefix() string                                           { return "mem://" }
+func (s *memStorage) Stats(ctx context.Context) (string, error)                   { return "", nil }
+func (s *memStorage) CollectMetrics(ctx context.Context, ma *metrics.Agent) error { return nil }
+func (s *memStorage) Stop() error                                                 { return nil }
+
 func TestCompressor(t *testing.T) {
 	t.Parallel()
 
@@ -36,3 +88,434 @@ func TestCompressor(t *testing.T) {
 		}
 	}
 }
+
+func TestDeltaDump(t *testing.T) {
+	base, err := gravitontree.NewTree("base", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := gravitontree.NewTree("current", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("number %d", i))
+		if err := base.Add(key, key); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Add(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// remove, update and add some claims
+	for i := 0; i < 10; i++ {
+		if err := tr.Delete([]byte(fmt.Sprintf("number %d", i))); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Update([]byte(fmt.Sprintf("number %d", 10+i)), []byte("new value")); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Add([]byte(fmt.Sprintf("number %d", 100+i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dump := types.CensusDump{RootHash: tr.Root(), BaseRoot: base.Root()}
+	if dump.Added, dump.Removed, err = deltaDump(base, tr); err != nil {
+		t.Fatal(err)
+	}
+	if len(dump.Added) != 20 || len(dump.Removed) != 20 {
+		t.Fatalf("wrong delta size, %d added and %d removed", len(dump.Added), len(dump.Removed))
+	}
+	if err := applyDelta(base, &dump); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(base.Root(), dump.RootHash) {
+		t.Errorf("root after applying the delta is different (%x != %x)", base.Root(), dump.RootHash)
+	}
+}
+
+func TestPublishDelta(t *testing.T) {
+	storage := &memStorage{}
+	m := Manager{RemoteStorage: storage}
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := m.AddNamespace("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PublishTree("test"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	addClaims := func(from, to int) {
+		for i := from; i < to; i++ {
+			resp := m.Handler(ctx, &types.MetaRequest{Method: "addClaim", CensusID: "test",
+				CensusKey: []byte(fmt.Sprintf("number %d", i)), Digested: true}, true, "", ethcommon.Address{})
+			if !resp.Ok {
+				t.Fatal(resp.Message)
+			}
+		}
+	}
+	addClaims(0, 50)
+	base := m.Handler(ctx, &types.MetaRequest{Method: "publish", CensusID: "test"},
+		true, "", ethcommon.Address{})
+	if !base.Ok {
+		t.Fatal(base.Message)
+	}
+	addClaims(50, 60)
+	resp := m.Handler(ctx, &types.MetaRequest{Method: "publish", CensusID: "test", BaseRoot: base.Root},
+		true, "", ethcommon.Address{})
+	if !resp.Ok {
+		t.Fatal(resp.Message)
+	}
+	raw, err := storage.Retrieve(ctx, resp.URI[len(storage.URIprefix()):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump types.CensusDump
+	if err := json.Unmarshal(m.decompressBytes(raw), &dump); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dump.BaseRoot, base.Root) || dump.BaseURI != base.URI {
+		t.Fatalf("delta dump has base %x at %s, expected %x at %s", dump.BaseRoot, dump.BaseURI, base.Root, base.URI)
+	}
+	if len(dump.Added) != 10 || len(dump.Removed) != 0 || len(dump.Data) != 0 {
+		t.Fatalf("wrong delta dump, %d claims added and %d removed", len(dump.Added), len(dump.Removed))
+	}
+
+	// the rootHash of publish is still the root of the snapshot to publish
+	snapshot := m.Handler(ctx, &types.MetaRequest{Method: "publish", CensusID: "test", RootHash: base.Root},
+		true, "", ethcommon.Address{})
+	if !snapshot.Ok || !bytes.Equal(snapshot.Root, base.Root) {
+		t.Fatalf("published root %x, expected the snapshot %x: %s", snapshot.Root, base.Root, snapshot.Message)
+	}
+
+	// another node imports the delta, retrieving its base census
+	var m2 Manager
+	m2.RemoteStorage = storage
+	if err := m2.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	if tr, err = m2.AddNamespace("imported", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m2.PublishTree("imported"); err != nil {
+		t.Fatal(err)
+	}
+	imported := m2.Handler(ctx, &types.MetaRequest{Method: "importRemote", CensusID: "imported", URI: resp.URI},
+		true, "", ethcommon.Address{})
+	if !imported.Ok {
+		t.Fatal(imported.Message)
+	}
+	if tr, err = m2.AcquireTree("imported"); err != nil {
+		t.Fatal(err)
+	}
+	defer m2.ReleaseTree("imported")
+	if !bytes.Equal(tr.Root(), resp.Root) {
+		t.Errorf("root of the imported census is %x, expected %x", tr.Root(), resp.Root)
+	}
+}
+
+func TestRootHistory(t *testing.T) {
+	var m Manager
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := m.AddNamespace("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PublishTree("test"); err != nil {
+		t.Fatal(err)
+	}
+	var ctx context.Context
+	keys := [][]byte{[]byte("number 1"), []byte("number 2")}
+	for _, key := range keys {
+		resp := m.Handler(ctx, &types.MetaRequest{Method: "addClaim", CensusID: "test",
+			CensusKey: key, Digested: true}, true, "", ethcommon.Address{})
+		if !resp.Ok {
+			t.Fatal(resp.Message)
+		}
+	}
+	resp := m.Handler(ctx, &types.MetaRequest{Method: "getRootHistory", CensusID: "test"},
+		false, "", ethcommon.Address{})
+	if !resp.Ok {
+		t.Fatal(resp.Message)
+	}
+	if len(resp.RootHistory) != 3 {
+		t.Fatalf("expected 3 roots, got %d", len(resp.RootHistory))
+	}
+	if !bytes.Equal(resp.RootHistory[2].Root, tr.Root()) {
+		t.Errorf("last root is not the current one")
+	}
+
+	// the first claim was already in the census with the second root
+	root := resp.RootHistory[1].Root
+	resp = m.Handler(ctx, &types.MetaRequest{Method: "genProof", CensusID: "test",
+		CensusKey: keys[0], RootHash: root, Digested: true}, false, "", ethcommon.Address{})
+	if !resp.Ok || len(resp.Siblings) == 0 {
+		t.Fatalf("cannot generate proof for an old root: %s", resp.Message)
+	}
+	if !bytes.Equal(resp.Root, root) {
+		t.Errorf("proof root is %x, expected %x", resp.Root, root)
+	}
+	valid, err := gravitontree.CheckProof(keys[0], nil, root, resp.Siblings)
+	if err != nil || !valid {
+		t.Errorf("proof for an old root is not valid (%v)", err)
+	}
+}
+
+func TestRootHistoryStorage(t *testing.T) {
+	dir := t.TempDir()
+	var m Manager
+	if err := m.Init(dir, "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	// recording roots does not rewrite namespaces.json
+	tr, err := m.AddNamespace("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nsConfig := filepath.Join(dir, "namespaces.json")
+	nsJSON, err := ioutil.ReadFile(nsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := tr.Add([]byte(fmt.Sprintf("number %d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+		m.recordRoot("test", tr.Root())
+	}
+	m.recordRoot("test", tr.Root())
+	if current, err := ioutil.ReadFile(nsConfig); err != nil || !bytes.Equal(current, nsJSON) {
+		t.Errorf("namespaces.json was rewritten (%v)", err)
+	}
+
+	// the history is kept when the manager is started again
+	var m2 Manager
+	if err := m2.Init(dir, "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	roots, err := m2.RootHistory("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 4 || !bytes.Equal(roots[3].Root, tr.Root()) {
+		t.Fatalf("wrong root history after a restart, %d roots", len(roots))
+	}
+	if err := m.DelNamespace("test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.historyFile("test")); !os.IsNotExist(err) {
+		t.Errorf("the root history of a removed census was kept (%v)", err)
+	}
+}
+
+func TestPublishChunks(t *testing.T) {
+	storage := &memStorage{}
+	m := Manager{RemoteStorage: storage}
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.AddNamespace("test", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PublishTree("test"); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := m.AcquireTree("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.ReleaseTree("test")
+	for i := 0; i < 250; i++ {
+		key := []byte(fmt.Sprintf("number %d", i))
+		if err := tr.Add(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	manifest, err := m.publishChunks(ctx, tr, tr.Root(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(manifest.Chunks))
+	}
+	tr2, err := gravitontree.NewTree("test2", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.importChunks(ctx, tr2, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tr.Root(), tr2.Root()) {
+		t.Errorf("roots are different after importing the chunks")
+	}
+
+	// a modified chunk must be rejected
+	tr3, err := gravitontree.NewTree("test3", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strings.TrimPrefix(manifest.Chunks[2].URI, storage.URIprefix())
+	storage.files[id] = m.compressBytes([]byte("modified"))
+	if err := m.importChunks(ctx, tr3, manifest); err == nil {
+		t.Errorf("modified chunk was imported")
+	}
+
+	// the census is only published in chunks if the request is chunked
+	for _, chunked := range []bool{false, true} {
+		resp := m.Handler(ctx, &types.MetaRequest{Method: "publish", CensusID: "test", Chunked: chunked},
+			true, "", ethcommon.Address{})
+		if !resp.Ok {
+			t.Fatal(resp.Message)
+		}
+		raw, err := storage.Retrieve(ctx, strings.TrimPrefix(resp.URI, storage.URIprefix()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dump types.CensusDump
+		if err := json.Unmarshal(m.decompressBytes(raw), &dump); err != nil {
+			t.Fatal(err)
+		}
+		if chunked && (len(dump.Chunks) != 1 || len(dump.Data) != 0) {
+			t.Errorf("chunked publish has %d chunks and %d bytes of data", len(dump.Chunks), len(dump.Data))
+		} else if !chunked && (len(dump.Chunks) != 0 || len(dump.Data) == 0) {
+			t.Errorf("publish has %d chunks and %d bytes of data", len(dump.Chunks), len(dump.Data))
+		}
+	}
+}
+
+func TestUnloadTrees(t *testing.T) {
+	m := Manager{MaxLoadedTrees: 1}
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	var roots [][]byte
+	for _, name := range []string{"test1", "test2"} {
+		tr, err := m.AddNamespace(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Add([]byte(name), nil); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, tr.Root())
+	}
+	// test1 is in use, so test2 is unloaded to keep a single tree loaded
+	if _, err := m.AcquireTree("test1"); err != nil {
+		t.Fatal(err)
+	}
+	m.TreesMu.Lock()
+	m.unloadTrees()
+	_, loaded1 := m.Trees["test1"]
+	_, loaded2 := m.Trees["test2"]
+	m.TreesMu.Unlock()
+	if !loaded1 || loaded2 {
+		t.Fatalf("wrong loaded trees, test1 %t and test2 %t", loaded1, loaded2)
+	}
+	m.ReleaseTree("test1")
+
+	// test2 is loaded again on demand, and test1 is unloaded
+	tr, err := m.AcquireTree("test2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.ReleaseTree("test2")
+	if !bytes.Equal(tr.Root(), roots[1]) {
+		t.Errorf("root of the loaded again tree is different")
+	}
+	m.TreesMu.RLock()
+	defer m.TreesMu.RUnlock()
+	if _, ok := m.Trees["test1"]; ok || len(m.Trees) != 1 {
+		t.Errorf("test1 was not unloaded, %d trees loaded", len(m.Trees))
+	}
+}
+
+func TestDelNamespaceInUse(t *testing.T) {
+	var m Manager
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.AddNamespace("test", nil); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := m.AcquireTree("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Add([]byte("number 1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	// the tree is still usable until released
+	if err := m.DelNamespace("test"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Exists("test") {
+		t.Fatalf("namespace not removed")
+	}
+	if _, err := m.AcquireTree("test"); err == nil {
+		t.Fatalf("removed namespace acquired")
+	}
+	if err := tr.Add([]byte("number 2"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.AddNamespace("test", nil); err == nil {
+		t.Fatalf("namespace added again before its tree was destroyed")
+	}
+
+	m.ReleaseTree("test")
+	if tr, err = m.AddNamespace("test", nil); err != nil {
+		t.Fatal(err)
+	}
+	if size, err := tr.Size(nil); err != nil || size != 0 {
+		t.Errorf("tree not destroyed, size %d (%v)", size, err)
+	}
+}
+
+func TestPublicFlag(t *testing.T) {
+	dir := t.TempDir()
+	// a census of an older version without the flag is public
+	nsJSON, err := json.Marshal(Namespaces{Namespaces: []Namespace{{Name: "legacy"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "namespaces.json"), nsJSON, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var m Manager
+	if err := m.Init(dir, "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"private", "public"} {
+		if _, err := m.AddNamespace(name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.PublishTree("public"); err != nil {
+		t.Fatal(err)
+	}
+	m.TreesMu.Lock()
+	for _, name := range []string{"private", "public"} {
+		m.UnloadTree(name)
+	}
+	m.TreesMu.Unlock()
+
+	var m2 Manager
+	if err := m2.Init(dir, "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	for name, public := range map[string]bool{"legacy": true, "private": false, "public": true} {
+		tr, err := m2.AcquireTree(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tr.IsPublic() != public {
+			t.Errorf("census %s loaded with public %t", name, tr.IsPublic())
+		}
+		m2.ReleaseTree(name)
+	}
+}
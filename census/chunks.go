@@ -0,0 +1,84 @@
+package census
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.vocdoni.io/dvote/censustree"
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// DumpChunkSize is the number of claims of each chunk of a chunked census
+// dump, which is published if the publish request is chunked
+const DumpChunkSize = 50000
+
+// MaxChunkBytes is the maximum size of a census dump chunk
+const MaxChunkBytes = 64 << 20
+
+// checkChunk returns an error if the hash of chunk is not hash
+func checkChunk(chunk, hash []byte) error {
+	if len(chunk) > MaxChunkBytes {
+		return fmt.Errorf("chunk too big (%d bytes, maximum is %d)", len(chunk), MaxChunkBytes)
+	}
+	if !bytes.Equal(ethereum.HashRaw(chunk), hash) {
+		return fmt.Errorf("chunk hash does not match")
+	}
+	return nil
+}
+
+// publishChunks publishes each chunk of at most chunkSize claims of the census
+// tr with root to the remote storage and returns the manifest of the chunked
+// dump
+func (m *Manager) publishChunks(ctx context.Context, tr censustree.Tree, root []byte,
+	chunkSize int) (*types.CensusDump, error) {
+	manifest := &types.CensusDump{RootHash: root}
+	err := tr.DumpChunks(root, chunkSize, func(chunk []byte) error {
+		cid, err := m.RemoteStorage.Publish(ctx, m.compressBytes(chunk))
+		if err != nil {
+			return fmt.Errorf("cannot publish chunk %d: %w", len(manifest.Chunks), err)
+		}
+		manifest.Chunks = append(manifest.Chunks, types.CensusChunk{
+			URI:  m.RemoteStorage.URIprefix() + cid,
+			Hash: ethereum.HashRaw(chunk),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("published census %x in %d chunks", root, len(manifest.Chunks))
+	return manifest, nil
+}
+
+// importChunks retrieves from the remote storage the chunks of the census
+// described by manifest, checks their hashes and imports them into tr
+func (m *Manager) importChunks(ctx context.Context, tr censustree.Tree, manifest *types.CensusDump) error {
+	if m.RemoteStorage == nil {
+		return fmt.Errorf("no remote storage available")
+	}
+	prefix := m.RemoteStorage.URIprefix()
+	for i, c := range manifest.Chunks {
+		if !strings.HasPrefix(c.URI, prefix) || len(c.URI) <= len(prefix) {
+			return fmt.Errorf("chunk %d URI not supported: %q", i, c.URI)
+		}
+		rctx, cancel := context.WithTimeout(ctx, ImportRetrieveTimeout)
+		chunk, err := m.RemoteStorage.Retrieve(rctx, c.URI[len(prefix):])
+		cancel()
+		if err != nil {
+			return fmt.Errorf("cannot retrieve chunk %d: %w", i, err)
+		}
+		chunk = m.decompressBytes(chunk)
+		if err := checkChunk(chunk, c.Hash); err != nil {
+			return fmt.Errorf("invalid chunk %d: %w", i, err)
+		}
+		if err := tr.ImportDump(chunk); err != nil {
+			return fmt.Errorf("cannot import chunk %d: %w", i, err)
+		}
+		log.Debugf("imported chunk %d/%d of census %x", i+1, len(manifest.Chunks), manifest.RootHash)
+	}
+	return nil
+}
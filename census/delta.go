@@ -0,0 +1,193 @@
+package census
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.vocdoni.io/dvote/censustree"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// MaxDeltaChain is the maximum number of delta dumps resolved for importing a
+// census, including the one being imported
+const MaxDeltaChain = 64
+
+// setURI records the remote storage URI where the census name was published
+func (m *Manager) setURI(name, uri string) error {
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	for i := range m.Census.Namespaces {
+		if m.Census.Namespaces[i].Name == name {
+			m.Census.Namespaces[i].URI = uri
+			return m.save()
+		}
+	}
+	return fmt.Errorf("namespace %s does not exist", name)
+}
+
+// publishedTree acquires the local copy of the census published with root and
+// returns it with its remote storage URI. The tree must be released.
+func (m *Manager) publishedTree(root []byte) (censustree.Tree, string, error) {
+	name := hex.EncodeToString(root)
+	m.TreesMu.RLock()
+	ns, ok := m.namespace(name)
+	m.TreesMu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("census %s not found", name)
+	}
+	if ns.URI == "" {
+		return nil, "", fmt.Errorf("census %s has not been published", name)
+	}
+	tr, err := m.AcquireTree(name)
+	if err != nil {
+		return nil, "", err
+	}
+	return tr, ns.URI, nil
+}
+
+// pathClaim is a claim with its path in the tree
+type pathClaim struct {
+	path, key, value []byte
+}
+
+// iterateClaims sends the claims of the current root of tr sorted by path,
+// until done is closed, and then the error of the iteration
+func iterateClaims(tr censustree.Tree, done <-chan struct{}) (<-chan pathClaim, <-chan error) {
+	claims := make(chan pathClaim, 64)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(claims)
+		errc <- tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+			select {
+			case claims <- pathClaim{path: path, key: key, value: value}:
+				return false
+			case <-done:
+				return true
+			}
+		})
+	}()
+	return claims, errc
+}
+
+// deltaDump returns the claims added to and removed from base to get tr. A
+// claim whose value changed is both removed and added. Both trees are
+// iterated sorted by path and merged, so they are never held in memory.
+func deltaDump(base, tr censustree.Tree) ([]types.CensusClaim, [][]byte, error) {
+	done := make(chan struct{})
+	defer close(done)
+	baseClaims, baseErr := iterateClaims(base, done)
+	claims, claimsErr := iterateClaims(tr, done)
+	var added []types.CensusClaim
+	var removed [][]byte
+	b, baseOk := <-baseClaims
+	c, ok := <-claims
+	for baseOk || ok {
+		cmp := 0
+		switch {
+		case !ok:
+			cmp = -1
+		case !baseOk:
+			cmp = 1
+		default:
+			cmp = bytes.Compare(b.path, c.path)
+		}
+		if cmp < 0 {
+			removed = append(removed, b.key)
+			b, baseOk = <-baseClaims
+			continue
+		}
+		if cmp > 0 {
+			added = append(added, types.CensusClaim{Key: c.key, Value: c.value})
+			c, ok = <-claims
+			continue
+		}
+		if !bytes.Equal(b.value, c.value) {
+			removed = append(removed, b.key)
+			added = append(added, types.CensusClaim{Key: c.key, Value: c.value})
+		}
+		b, baseOk = <-baseClaims
+		c, ok = <-claims
+	}
+	if err := <-baseErr; err != nil {
+		return nil, nil, fmt.Errorf("cannot iterate base census: %w", err)
+	}
+	if err := <-claimsErr; err != nil {
+		return nil, nil, fmt.Errorf("cannot iterate census: %w", err)
+	}
+	return added, removed, nil
+}
+
+// applyDelta removes and adds the claims of a delta dump to tr
+func applyDelta(tr censustree.Tree, dump *types.CensusDump) error {
+	for _, k := range dump.Removed {
+		if err := tr.Delete(k); err != nil {
+			return fmt.Errorf("cannot remove claim %x: %w", k, err)
+		}
+	}
+	for _, c := range dump.Added {
+		if err := tr.Add(c.Key, c.Value); err != nil {
+			return fmt.Errorf("cannot add claim %x: %w", c.Key, err)
+		}
+	}
+	return nil
+}
+
+// baseTree acquires the local copy of the census a delta dump applies to,
+// importing it and the chain of its bases if they are not available. The
+// tree must be released.
+func (m *Manager) baseTree(dump *types.CensusDump, depth int) (censustree.Tree, error) {
+	name := hex.EncodeToString(dump.BaseRoot)
+	if tr, err := m.AcquireTree(name); err == nil {
+		if bytes.Equal(tr.Root(), dump.BaseRoot) {
+			return tr, nil
+		}
+		m.ReleaseTree(name)
+	}
+	if depth+1 >= MaxDeltaChain {
+		return nil, fmt.Errorf("too many chained delta dumps (maximum is %d)", MaxDeltaChain)
+	}
+	if m.RemoteStorage == nil || !strings.HasPrefix(dump.BaseURI, m.RemoteStorage.URIprefix()) ||
+		len(dump.BaseURI) <= len(m.RemoteStorage.URIprefix()) {
+		return nil, fmt.Errorf("base census URI not supported: %q", dump.BaseURI)
+	}
+	log.Infof("retrieving base census %s from %s", name, dump.BaseURI)
+	ctx, cancel := context.WithTimeout(context.Background(), ImportRetrieveTimeout)
+	censusRaw, err := m.RemoteStorage.Retrieve(ctx, dump.BaseURI[len(m.RemoteStorage.URIprefix()):])
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve base census %s: %w", name, err)
+	}
+	if err := m.importTree(m.decompressBytes(censusRaw), name, dump.BaseURI, depth+1); err != nil {
+		return nil, err
+	}
+	tr, err := m.AcquireTree(name)
+	if err != nil {
+		return nil, fmt.Errorf("base census %s not found after import: %w", name, err)
+	}
+	return tr, nil
+}
+
+// importDelta imports into tr the base census of a delta dump, applies the
+// delta and checks the resulting root
+func (m *Manager) importDelta(tr censustree.Tree, dump *types.CensusDump, depth int) error {
+	base, err := m.baseTree(dump, depth)
+	if err != nil {
+		return err
+	}
+	defer m.ReleaseTree(hex.EncodeToString(dump.BaseRoot))
+	if err := base.DumpChunks(base.Root(), DumpChunkSize, tr.ImportDump); err != nil {
+		return fmt.Errorf("cannot import base census: %w", err)
+	}
+	if err := applyDelta(tr, dump); err != nil {
+		return err
+	}
+	if !bytes.Equal(tr.Root(), dump.RootHash) {
+		return fmt.Errorf("root hash does not match after applying the delta dump")
+	}
+	log.Infof("delta dump applied, %d claims added and %d removed", len(dump.Added), len(dump.Removed))
+	return nil
+}
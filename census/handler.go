@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
 	"go.vocdoni.io/dvote/crypto"
 	"go.vocdoni.io/dvote/crypto/ethereum"
 	"go.vocdoni.io/dvote/crypto/snarks"
@@ -20,6 +22,9 @@ import (
 const (
 	censusHTTPhandlerTimeout   = 30 * time.Second
 	censusRemoteStorageTimeout = 1 * time.Minute
+	// MaxProofBatchSize is the maximum number of keys of genProofBatch and
+	// checkProofBatch
+	MaxProofBatchSize = 1000
 )
 
 func httpReply(resp *types.ResponseMessage, w http.ResponseWriter) {
@@ -76,7 +81,11 @@ func (m *Manager) HTTPhandler(ctx context.Context, w http.ResponseWriter, req *h
 	}
 	tctx, cancel := context.WithTimeout(ctx, toWait)
 	defer cancel()
-	resp := m.Handler(tctx, &reqInner, auth, "")
+	signerAddr, err := ethereum.AddrFromSignature(reqOuter.MetaRequest, reqOuter.Signature)
+	if err != nil {
+		log.Debugf("cannot recover the signer address: %s", err)
+	}
+	resp := m.Handler(tctx, &reqInner, auth, "", signerAddr)
 	resp.Request = reqOuter.ID
 
 	respInner, err := crypto.SortedMarshalJSON(resp)
@@ -101,7 +110,9 @@ func (m *Manager) HTTPhandler(ctx context.Context, w http.ResponseWriter, req *h
 // Handler handles an API census manager request.
 // isAuth gives access to the private methods only if censusPrefix match or censusPrefix not defined
 // censusPrefix should usually be the Ethereum Address or a Hash of the allowed PubKey
-func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool, censusPrefix string) *types.MetaResponse {
+// signer is the address which signed the request, it also has access if it is a manager key of the census
+func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool, censusPrefix string,
+	signer ethcommon.Address) *types.MetaResponse {
 	resp := new(types.MetaResponse)
 
 	// Process data
@@ -117,12 +128,13 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 	// Special methods not depending on census existence
 	if r.Method == "addCensus" {
 		if isAuth {
-			t, err := m.AddNamespace(censusPrefix+r.CensusID, r.PubKeys)
-			if err != nil {
+			if _, err := m.AddNamespace(censusPrefix+r.CensusID, r.PubKeys); err != nil {
 				log.Warnf("error creating census: %s", err)
 				resp.SetError(err)
+			} else if err := m.PublishTree(censusPrefix + r.CensusID); err != nil {
+				log.Warnf("error publishing census: %s", err)
+				resp.SetError(err)
 			} else {
-				t.Publish()
 				log.Infof("census %s%s created successfully managed by %s", censusPrefix, r.CensusID, r.PubKeys)
 				resp.CensusID = censusPrefix + r.CensusID
 			}
@@ -134,9 +146,11 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 
 	if r.Method == "getCensusList" {
 		if isAuth {
-			for n := range m.Trees {
-				resp.CensusList = append(resp.CensusList, n)
+			m.TreesMu.RLock()
+			for _, ns := range m.Census.Namespaces {
+				resp.CensusList = append(resp.CensusList, ns.Name)
 			}
+			m.TreesMu.RUnlock()
 		} else {
 			resp.SetError("invalid authentication")
 		}
@@ -152,24 +166,29 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		return resp
 	}
 
-	// validAuthPrefix is true: either censusPrefix is not used or censusID contains the prefix
+	if isKeyMethod(r.Method) {
+		return m.keysHandler(r, isAuth, censusPrefix, signer)
+	}
+
+	// validAuthPrefix is true: either censusPrefix is not used, censusID contains the prefix
+	// or the signer is a manager key of the census
 	validAuthPrefix := false
 	if len(censusPrefix) == 0 {
 		validAuthPrefix = true
 		log.Debugf("prefix not specified, allowing access to all census IDs if pubkey validation correct")
 	} else {
-		validAuthPrefix = strings.HasPrefix(r.CensusID, censusPrefix)
-		log.Debugf("prefix allowed for %s", r.CensusID)
+		validAuthPrefix = m.isPrefixOwner(r.CensusID, censusPrefix) || m.isManager(r.CensusID, signer)
+		log.Debugf("prefix allowed for %s: %t", r.CensusID, validAuthPrefix)
 	}
 
 	// Load the merkle tree
-	m.TreesMu.Lock()
-	tr, ok := m.Trees[r.CensusID]
-	m.TreesMu.Unlock()
-	if !ok {
+	tr, err := m.AcquireTree(r.CensusID)
+	if err != nil {
+		log.Warnf("cannot load census %s: %s", r.CensusID, err)
 		resp.SetError("censusId cannot be loaded")
 		return resp
 	}
+	defer m.ReleaseTree(r.CensusID)
 	if !tr.IsPublic() {
 		resp.SetError("census not yet published")
 		return resp
@@ -181,6 +200,15 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		resp.Root = tr.Root()
 		return resp
 
+	case "getRootHistory":
+		history, err := m.RootHistory(r.CensusID)
+		if err != nil {
+			resp.SetError(err)
+			return resp
+		}
+		resp.RootHistory = history
+		return resp
+
 	case "addClaimBulk":
 		if isAuth && validAuthPrefix {
 			addedClaims := 0
@@ -209,6 +237,7 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 				resp.InvalidClaims = invalidClaims
 			}
 			resp.Root = tr.Root()
+			m.recordRoot(r.CensusID, resp.Root)
 			log.Infof("%d claims addedd successfully", addedClaims)
 		} else {
 			resp.SetError("invalid authentication")
@@ -230,6 +259,7 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 				resp.SetError(err)
 			} else {
 				resp.Root = tr.Root()
+				m.recordRoot(r.CensusID, resp.Root)
 				log.Debugf("claim added %x/%x", data, r.CensusValue)
 			}
 		} else {
@@ -237,6 +267,72 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		}
 		return resp
 
+	case "delClaim", "updateClaim":
+		if isAuth && validAuthPrefix {
+			if r.CensusKey == nil {
+				resp.SetError("error decoding claim data")
+				return resp
+			}
+			data := r.CensusKey
+			if !r.Digested {
+				data = snarks.Poseidon.Hash(data)
+			}
+			var err error
+			if r.Method == "delClaim" {
+				err = tr.Delete(data)
+			} else {
+				err = tr.Update(data, r.CensusValue)
+			}
+			if err != nil {
+				resp.SetError(err)
+			} else {
+				resp.Root = tr.Root()
+				m.recordRoot(r.CensusID, resp.Root)
+				log.Debugf("%s done for claim %x", r.Method, data)
+			}
+		} else {
+			resp.SetError("invalid authentication")
+		}
+		return resp
+
+	case "delClaimBulk", "updateClaimBulk":
+		if isAuth && validAuthPrefix {
+			doneClaims := 0
+			var invalidClaims []int
+			var err error
+			var value types.HexBytes
+			for i, key := range r.CensusKeys {
+				if !r.Digested {
+					key = snarks.Poseidon.Hash(key)
+				}
+				if r.Method == "delClaimBulk" {
+					err = tr.Delete(key)
+				} else {
+					if i < len(r.CensusValues) {
+						value = r.CensusValues[i]
+					} else {
+						value = []byte{}
+					}
+					err = tr.Update(key, value)
+				}
+				if err != nil {
+					log.Warnf("error on %s for claim %x: %s", r.Method, key, err)
+					invalidClaims = append(invalidClaims, i)
+				} else {
+					doneClaims++
+				}
+			}
+			if len(invalidClaims) > 0 {
+				resp.InvalidClaims = invalidClaims
+			}
+			resp.Root = tr.Root()
+			m.recordRoot(r.CensusID, resp.Root)
+			log.Infof("%s: %d claims processed successfully", r.Method, doneClaims)
+		} else {
+			resp.SetError("invalid authentication")
+		}
+		return resp
+
 	case "importDump":
 		if isAuth && validAuthPrefix {
 			if len(r.CensusKeys) > 0 {
@@ -245,6 +341,7 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 					log.Warnf("error importing dump: %s", err)
 					resp.SetError(err)
 				} else {
+					m.recordRoot(r.CensusID, tr.Root())
 					log.Infof("dump imported successfully, %d claims", len(r.CensusKeys))
 				}
 			}
@@ -284,7 +381,19 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 			return resp
 		}
 		log.Infof("retrieved census with rootHash %s and size %d bytes", dump.RootHash, len(censusRaw))
-		if len(dump.Data) > 0 {
+		if len(dump.BaseRoot) > 0 {
+			if err := m.importDelta(tr, &dump, 0); err != nil {
+				log.Warnf("error importing delta dump: %s", err)
+				resp.SetError(fmt.Sprintf("error importing census: %s", err))
+			}
+		} else if len(dump.Chunks) > 0 {
+			if err := m.importChunks(ctx, tr, &dump); err != nil {
+				log.Warnf("error importing chunked dump: %s", err)
+				resp.SetError(fmt.Sprintf("error importing census: %s", err))
+			} else {
+				log.Infof("chunked dump imported successfully, %d chunks", len(dump.Chunks))
+			}
+		} else if len(dump.Data) > 0 {
 			err = tr.ImportDump(dump.Data)
 			if err != nil {
 				log.Warnf("error importing dump: %s", err)
@@ -322,6 +431,36 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		}
 		resp.ValidProof = &validProof
 		return resp
+
+	case "checkProofBatch":
+		if len(r.CensusKeys) == 0 || len(r.CensusKeys) > MaxProofBatchSize {
+			resp.SetError(fmt.Sprintf("censusKeys must have between 1 and %d keys", MaxProofBatchSize))
+			return resp
+		}
+		if len(r.Proofs) != len(r.CensusKeys) {
+			resp.SetError("proofs and censusKeys must have the same length")
+			return resp
+		}
+		root := tr.Root()
+		if len(r.RootHash) > 0 {
+			root = r.RootHash
+		}
+		for i, key := range r.CensusKeys {
+			if !r.Digested {
+				key = snarks.Poseidon.Hash(key)
+			}
+			var value []byte
+			if i < len(r.CensusValues) {
+				value = r.CensusValues[i]
+			}
+			validProof, err := tr.CheckProof(key, value, root, r.Proofs[i])
+			if err != nil {
+				resp.SetError(fmt.Sprintf("cannot check proof %d: %s", i, err))
+				return resp
+			}
+			resp.ValidProofs = append(resp.ValidProofs, validProof)
+		}
+		return resp
 	}
 
 	// Methods with rootHash, if rootHash specified snapshot the tree.
@@ -346,6 +485,29 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 			resp.SetError(err)
 		}
 		resp.Siblings = siblings
+		resp.Root = tr.Root()
+		return resp
+
+	case "genProofBatch":
+		if len(r.CensusKeys) == 0 || len(r.CensusKeys) > MaxProofBatchSize {
+			resp.SetError(fmt.Sprintf("censusKeys must have between 1 and %d keys", MaxProofBatchSize))
+			return resp
+		}
+		for i, key := range r.CensusKeys {
+			if !r.Digested {
+				key = snarks.Poseidon.Hash(key)
+			}
+			var value []byte
+			if i < len(r.CensusValues) {
+				value = r.CensusValues[i]
+			}
+			siblings, err := tr.GenProof(key, value)
+			if err != nil {
+				resp.SetError(fmt.Sprintf("cannot generate proof %d: %s", i, err))
+				return resp
+			}
+			resp.Proofs = append(resp.Proofs, siblings)
+		}
 		return resp
 
 	case "getSize":
@@ -396,10 +558,35 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		var dump types.CensusDump
 		dump.RootHash = tr.Root()
 		var err error
-		dump.Data, err = tr.Dump(tr.Root())
-		if err != nil {
+		if len(r.BaseRoot) > 0 {
+			// publish only the changes since the census published with baseRoot
+			base, baseURI, err := m.publishedTree(r.BaseRoot)
+			if err != nil {
+				resp.SetError(err)
+				return resp
+			}
+			defer m.ReleaseTree(hex.EncodeToString(r.BaseRoot))
+			dump.BaseRoot = r.BaseRoot
+			dump.BaseURI = baseURI
+			if dump.Added, dump.Removed, err = deltaDump(base, tr); err != nil {
+				resp.SetError(err)
+				log.Warnf("cannot compute census delta from %x: %s", r.BaseRoot, err)
+				return resp
+			}
+			log.Infof("publishing census delta from %x, %d claims added and %d removed",
+				r.BaseRoot, len(dump.Added), len(dump.Removed))
+		} else if r.Chunked {
+			// publish the claims in chunks, so the whole dump is never in memory
+			manifest, err := m.publishChunks(ctx, tr, dump.RootHash, DumpChunkSize)
+			if err != nil {
+				resp.SetError(err)
+				log.Warnf("cannot publish census chunks: %s", err)
+				return resp
+			}
+			dump.Chunks = manifest.Chunks
+		} else if dump.Data, err = tr.Dump(dump.RootHash); err != nil {
 			resp.SetError(err)
-			log.Warnf("cannot dump census with root %s: %s", tr.Root(), err)
+			log.Warnf("cannot dump census with root %x: %s", dump.RootHash, err)
 			return resp
 		}
 		dumpBytes, err := json.Marshal(dump)
@@ -426,15 +613,27 @@ func (m *Manager) Handler(ctx context.Context, r *types.MetaRequest, isAuth bool
 		if err != nil && err != ErrNamespaceExist {
 			log.Warnf("error creating local published census: %s", err)
 		} else if err == nil {
+			// keep the new tree loaded while importing the claims
+			if tr2, err = m.AcquireTree(namespace); err != nil {
+				resp.SetError(err)
+				return resp
+			}
+			defer m.ReleaseTree(namespace)
 			log.Infof("import claims to new census")
-			err = tr2.ImportDump(dump.Data)
+			err = tr.DumpChunks(resp.Root, DumpChunkSize, tr2.ImportDump)
 			if err != nil {
 				m.DelNamespace(namespace)
 				log.Warn(err)
 				resp.SetError(err)
 				return resp
 			}
-			tr2.Publish()
+			if err := m.PublishTree(namespace); err != nil {
+				log.Warnf("cannot publish census %s: %s", namespace, err)
+			}
+			m.recordRoot(namespace, tr2.Root())
+		}
+		if err := m.setURI(namespace, resp.URI); err != nil {
+			log.Warnf("cannot set the URI of census %s: %s", namespace, err)
 		}
 	}
 	return resp
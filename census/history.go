@@ -0,0 +1,199 @@
+package census
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// MaxRootHistory is the maximum number of roots kept in the history of a census
+const MaxRootHistory = 1024
+
+// rootHistoryDir is the directory, under the storage directory, with the
+// root history file of each namespace
+const rootHistoryDir = "roots"
+
+// rootHistory keeps the root history of each namespace in its own file, with
+// a JSON encoded types.CensusRoot per line, so recording a root appends a line
+// instead of rewriting namespaces.json
+type rootHistory struct {
+	lock sync.Mutex
+	// last and count are the last root and the number of lines of the
+	// history files already read
+	last  map[string][]byte
+	count map[string]int
+}
+
+// historyFile returns the path of the root history file of the namespace
+// name, which is hex encoded as names can contain slashes
+func (m *Manager) historyFile(name string) string {
+	return filepath.Join(m.StorageDir, rootHistoryDir, hex.EncodeToString([]byte(name)))
+}
+
+// readRoots returns the roots of the history file of the namespace name,
+// oldest first. Not thread safe.
+func (m *Manager) readRoots(name string) ([]types.CensusRoot, error) {
+	f, err := os.Open(m.historyFile(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var roots []types.CensusRoot
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var root types.CensusRoot
+		if err := json.Unmarshal(scanner.Bytes(), &root); err != nil {
+			// a line can be cut if the node stopped while writing it
+			log.Warnf("skipping invalid root of census %s: (%v)", name, err)
+			continue
+		}
+		roots = append(roots, root)
+	}
+	return roots, scanner.Err()
+}
+
+// writeRoots replaces the history file of the namespace name with roots. Not
+// thread safe.
+func (m *Manager) writeRoots(name string, roots []types.CensusRoot) error {
+	var buf bytes.Buffer
+	for _, root := range roots {
+		line, err := json.Marshal(root)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+	file := m.historyFile(name)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	// write a new file and rename it, so the history is never left halfway
+	if err := ioutil.WriteFile(file+".tmp", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file+".tmp", file)
+}
+
+// appendRoot appends root to the history of the namespace name, unless it is
+// already its last root. Once the file has twice MaxRootHistory roots, it is
+// compacted to the last MaxRootHistory.
+func (m *Manager) appendRoot(name string, root []byte) error {
+	h := &m.rootHistory
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.last == nil {
+		h.last = make(map[string][]byte)
+		h.count = make(map[string]int)
+	}
+	if _, ok := h.last[name]; !ok {
+		roots, err := m.readRoots(name)
+		if err != nil {
+			return err
+		}
+		h.last[name], h.count[name] = nil, len(roots)
+		if len(roots) > 0 {
+			h.last[name] = roots[len(roots)-1].Root
+		}
+	}
+	if h.count[name] > 0 && bytes.Equal(h.last[name], root) {
+		return nil
+	}
+	entry := types.CensusRoot{Root: append([]byte{}, root...), Timestamp: time.Now().Unix()}
+	if h.count[name] >= 2*MaxRootHistory {
+		roots, err := m.readRoots(name)
+		if err != nil {
+			return err
+		}
+		if len(roots) >= MaxRootHistory {
+			roots = roots[len(roots)-MaxRootHistory+1:]
+		}
+		roots = append(roots, entry)
+		if err := m.writeRoots(name, roots); err != nil {
+			return err
+		}
+		h.last[name], h.count[name] = entry.Root, len(roots)
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file := m.historyFile(name)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	h.last[name] = entry.Root
+	h.count[name]++
+	return nil
+}
+
+// delRoots removes the root history of the namespace name
+func (m *Manager) delRoots(name string) error {
+	h := &m.rootHistory
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.last, name)
+	delete(h.count, name)
+	if err := os.Remove(m.historyFile(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// recordRoot appends root to the history of the namespace name, unless it is
+// already its last root
+func (m *Manager) recordRoot(name string, root []byte) {
+	m.TreesMu.RLock()
+	exists := m.Exists(name)
+	m.TreesMu.RUnlock()
+	if !exists {
+		return
+	}
+	if err := m.appendRoot(name, root); err != nil {
+		log.Warnf("cannot save the root history of census %s: (%v)", name, err)
+	}
+}
+
+// RootHistory returns the roots the census name has had, oldest first
+func (m *Manager) RootHistory(name string) ([]types.CensusRoot, error) {
+	m.TreesMu.RLock()
+	exists := m.Exists(name)
+	m.TreesMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("censusId not valid or not found %s", name)
+	}
+	m.rootHistory.lock.Lock()
+	defer m.rootHistory.lock.Unlock()
+	roots, err := m.readRoots(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) > MaxRootHistory {
+		roots = roots[len(roots)-MaxRootHistory:]
+	}
+	return roots, nil
+}
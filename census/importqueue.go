@@ -18,8 +18,9 @@ type censusImport struct {
 	censusID, censusURI string
 }
 
-// importTree adds the raw (uncompressed) []byte tree to the cid namespace
-func (m *Manager) importTree(tree []byte, cid string) error {
+// importTree adds the raw (uncompressed) []byte tree retrieved from uri to the
+// cid namespace. depth is the number of delta dumps which have this one as base.
+func (m *Manager) importTree(tree []byte, cid, uri string, depth int) error {
 	var dump types.CensusDump
 	if err := json.Unmarshal(tree, &dump); err != nil {
 		return fmt.Errorf("retrieved census does not have a valid format: (%s)", err)
@@ -28,17 +29,32 @@ func (m *Manager) importTree(tree []byte, cid string) error {
 	if fmt.Sprintf("%x", dump.RootHash) != util.TrimHex(cid) {
 		return fmt.Errorf("dump root Hash and census ID root hash do not match, aborting import")
 	}
-	if len(dump.Data) == 0 {
+	isDelta := len(dump.BaseRoot) > 0
+	if len(dump.Data) == 0 && len(dump.Chunks) == 0 && !isDelta {
 		return fmt.Errorf("no claims found on the retreived census")
 	}
-	tr, err := m.AddNamespace(cid, []string{})
-	if err == ErrNamespaceExist {
+	if _, err := m.AddNamespace(cid, []string{}); err == ErrNamespaceExist {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("cannot create new census namespace: (%s)", err)
 	}
-	err = tr.ImportDump(dump.Data)
+	// keep the new tree loaded while importing the claims
+	tr, err := m.AcquireTree(cid)
 	if err != nil {
+		return err
+	}
+	defer m.ReleaseTree(cid)
+	if isDelta {
+		err = m.importDelta(tr, &dump, depth)
+	} else if len(dump.Chunks) > 0 {
+		err = m.importChunks(context.Background(), tr, &dump)
+	} else {
+		err = tr.ImportDump(dump.Data)
+	}
+	if err != nil {
+		if err := m.DelNamespace(cid); err != nil {
+			log.Error(err)
+		}
 		return fmt.Errorf("error importing dump: %s", err)
 	}
 	if !bytes.Equal(tr.Root(), dump.RootHash) {
@@ -47,11 +63,32 @@ func (m *Manager) importTree(tree []byte, cid string) error {
 		}
 		return fmt.Errorf("root hash does not match on imported census, aborting import")
 	}
-	tr.Publish()
-	log.Infof("census imported successfully, %d claims. Status is public:%t", len(dump.Data), tr.IsPublic())
+	m.recordRoot(cid, tr.Root())
+	if err := m.setImported(cid, uri); err != nil {
+		log.Warnf("cannot set census %s as imported: (%v)", cid, err)
+	}
+	if err := m.PublishTree(cid); err != nil {
+		log.Warnf("cannot publish census %s: (%v)", cid, err)
+	}
+	log.Infof("census imported successfully, %d bytes. Status is public:%t", len(tree), tr.IsPublic())
 	return nil
 }
 
+// setImported records that the census name was imported from the remote
+// storage URI
+func (m *Manager) setImported(name, uri string) error {
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	for i := range m.Census.Namespaces {
+		if m.Census.Namespaces[i].Name == name {
+			m.Census.Namespaces[i].URI = uri
+			m.Census.Namespaces[i].Imported = true
+			return m.save()
+		}
+	}
+	return fmt.Errorf("namespace %s does not exist", name)
+}
+
 // ImportQueueSize returns the size of the import census queue
 func (m *Manager) ImportQueueSize() int32 {
 	return atomic.LoadInt32(&m.queueSize)
@@ -96,7 +133,7 @@ func (m *Manager) importFailedQueueDaemon() {
 				continue
 			}
 			censusRaw = m.decompressBytes(censusRaw)
-			if err := m.importTree(censusRaw, cid); err != nil {
+			if err := m.importTree(censusRaw, cid, uri, 0); err != nil {
 				log.Warnf("cannot import census %s: (%v)", cid, err)
 			}
 			m.failedQueueLock.Lock()
@@ -139,7 +176,7 @@ func (m *Manager) importQueueDaemon() {
 			continue
 		}
 		censusRaw = m.decompressBytes(censusRaw)
-		if err = m.importTree(censusRaw, cid); err != nil {
+		if err = m.importTree(censusRaw, cid, uri, 0); err != nil {
 			log.Warnf("cannot import census %s: (%s)", cid, err)
 		}
 		m.queueAdd(-1)
@@ -0,0 +1,216 @@
+package census
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+)
+
+// KeyAuditEntry is a change of the manager keys of a census namespace,
+// recorded in the key audit log
+type KeyAuditEntry struct {
+	Time     time.Time `json:"time"`
+	CensusID string    `json:"censusId"`
+	Method   string    `json:"method"`
+	Signer   string    `json:"signer"`
+	Keys     []string  `json:"keys"`
+	Result   []string  `json:"result,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// keyAudit writes the changes of the namespace manager keys
+type keyAudit struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// EnableKeyAuditLog writes every change of the namespace manager keys to w
+// as a JSON line
+func (m *Manager) EnableKeyAuditLog(w io.Writer) {
+	m.keyAudit.lock.Lock()
+	defer m.keyAudit.lock.Unlock()
+	m.keyAudit.w = w
+}
+
+func (m *Manager) auditKeys(entry KeyAuditEntry) {
+	m.keyAudit.lock.Lock()
+	defer m.keyAudit.lock.Unlock()
+	if m.keyAudit.w == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Warnf("cannot encode key audit entry: (%s)", err)
+		return
+	}
+	if _, err := m.keyAudit.w.Write(append(line, '\n')); err != nil {
+		log.Warnf("cannot write key audit entry: (%s)", err)
+	}
+}
+
+// isKeyMethod returns whether method administrates the namespace manager keys
+func isKeyMethod(method string) bool {
+	switch method {
+	case "addCensusKeys", "delCensusKeys", "transferCensus":
+		return true
+	}
+	return false
+}
+
+// keyAddress returns the address of a hex public key
+func keyAddress(keyHex string) (ethcommon.Address, error) {
+	key, err := hex.DecodeString(util.TrimHex(keyHex))
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return ethereum.AddrFromPublicKey(key)
+}
+
+// namespace returns a copy of the namespace name. Not thread safe.
+func (m *Manager) namespace(name string) (Namespace, bool) {
+	for _, ns := range m.Census.Namespaces {
+		if ns.Name == name {
+			return ns, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// isManager returns whether signer is one of the manager keys of the
+// namespace name or the root key
+func (m *Manager) isManager(name string, signer ethcommon.Address) bool {
+	if signer == (ethcommon.Address{}) {
+		return false
+	}
+	m.TreesMu.RLock()
+	defer m.TreesMu.RUnlock()
+	ns, _ := m.namespace(name)
+	keys := append([]string{m.Census.RootKey}, ns.Keys...)
+	for _, k := range keys {
+		if len(k) < ethereum.PubKeyLengthBytes*2 {
+			continue
+		}
+		if addr, err := keyAddress(k); err == nil && addr == signer {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrefixOwner returns whether censusPrefix grants access to the namespace
+// name, which is not the case once it has been transferred
+func (m *Manager) isPrefixOwner(name, censusPrefix string) bool {
+	if !strings.HasPrefix(name, censusPrefix) {
+		return false
+	}
+	m.TreesMu.RLock()
+	defer m.TreesMu.RUnlock()
+	ns, _ := m.namespace(name)
+	return !ns.Transferred
+}
+
+// keysHandler handles the methods administrating the manager keys of a
+// namespace. They must be authenticated, and signed by a manager, the root key
+// or the owner of the prefix of the namespace, as signer is recovered even if
+// the request is not valid, e.g. out of the time window.
+func (m *Manager) keysHandler(r *types.MetaRequest, isAuth bool, censusPrefix string,
+	signer ethcommon.Address) *types.MetaResponse {
+	resp := &types.MetaResponse{Ok: true, Timestamp: int32(time.Now().Unix())}
+	owner := len(censusPrefix) > 0 && m.isPrefixOwner(r.CensusID, censusPrefix)
+	if !isAuth || (!owner && !m.isManager(r.CensusID, signer)) {
+		resp.SetError("invalid authentication")
+		return resp
+	}
+	entry := KeyAuditEntry{
+		Time:     time.Now().UTC(),
+		CensusID: r.CensusID,
+		Method:   r.Method,
+		Signer:   signer.Hex(),
+		Keys:     r.PubKeys,
+	}
+	keys, err := m.updateKeys(r.CensusID, r.Method, r.PubKeys)
+	if err != nil {
+		entry.Error = err.Error()
+		resp.SetError(err)
+	} else {
+		entry.Result = keys
+		resp.PubKeys = keys
+		log.Infof("%s on census %s by %s, manager keys are %v", r.Method, r.CensusID, signer.Hex(), keys)
+	}
+	m.auditKeys(entry)
+	return resp
+}
+
+// updateKeys applies a key administration method to the namespace name and
+// returns its resulting manager keys
+func (m *Manager) updateKeys(name, method string, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys provided")
+	}
+	for _, k := range keys {
+		if _, err := keyAddress(k); err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", k, err)
+		}
+	}
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	idx := -1
+	for i := range m.Census.Namespaces {
+		if m.Census.Namespaces[i].Name == name {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("censusId not valid or not found %s", name)
+	}
+	ns := &m.Census.Namespaces[idx]
+	current := make(map[string]bool)
+	for _, k := range ns.Keys {
+		current[util.TrimHex(k)] = true
+	}
+	switch method {
+	case "addCensusKeys":
+		for _, k := range keys {
+			if !current[util.TrimHex(k)] {
+				ns.Keys = append(ns.Keys, util.TrimHex(k))
+				current[util.TrimHex(k)] = true
+			}
+		}
+	case "delCensusKeys":
+		remove := make(map[string]bool)
+		for _, k := range keys {
+			remove[util.TrimHex(k)] = true
+		}
+		var remaining []string
+		for _, k := range ns.Keys {
+			if !remove[util.TrimHex(k)] {
+				remaining = append(remaining, k)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil, fmt.Errorf("cannot remove all the manager keys, use transferCensus instead")
+		}
+		ns.Keys = remaining
+	case "transferCensus":
+		ns.Keys = nil
+		for _, k := range keys {
+			ns.Keys = append(ns.Keys, util.TrimHex(k))
+		}
+		ns.Transferred = true
+	default:
+		return nil, fmt.Errorf("unknown method %s", method)
+	}
+	result := append([]string{}, ns.Keys...)
+	return result, m.save()
+}
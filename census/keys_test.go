@@ -0,0 +1,188 @@
+package census
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"go.vocdoni.io/dvote/censustree/gravitontree"
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+)
+
+func TestCensusKeys(t *testing.T) {
+	signers := make(map[string]*ethereum.SignKeys)
+	for _, name := range []string{"root", "owner", "manager", "other", "newManager"} {
+		s := ethereum.NewSignKeys()
+		if err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		signers[name] = s
+	}
+	pubKey := func(name string) string {
+		pub, _ := signers[name].HexString()
+		return pub
+	}
+	var m Manager
+	if err := m.Init(t.TempDir(), pubKey("root"), gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	var audit bytes.Buffer
+	m.EnableKeyAuditLog(&audit)
+
+	// the prefix of a signer is its address, as the router sets it, and the
+	// census is created by owner
+	prefix := func(name string) string {
+		return util.TrimHex(signers[name].AddressString()) + "/"
+	}
+	ctx := context.Background()
+	call := func(signer, method string, pubKeys ...string) *types.MetaResponse {
+		censusID := prefix("owner") + "test"
+		if method == "addCensus" {
+			censusID = "test"
+		}
+		return m.Handler(ctx, &types.MetaRequest{Method: method, CensusID: censusID,
+			CensusKey: []byte(signer + " claim"), Digested: true, PubKeys: pubKeys},
+			true, prefix(signer), signers[signer].Address())
+	}
+	if resp := call("owner", "addCensus", pubKey("manager")); !resp.Ok {
+		t.Fatal(resp.Message)
+	}
+	for _, signer := range []string{"owner", "manager", "root"} {
+		if resp := call(signer, "addClaim"); !resp.Ok {
+			t.Errorf("%s cannot add a claim: %s", signer, resp.Message)
+		}
+	}
+	if resp := call("other", "addClaim"); resp.Ok {
+		t.Errorf("a key which is not a manager added a claim")
+	}
+	if resp := call("other", "addCensusKeys", pubKey("other")); resp.Ok {
+		t.Errorf("a key which is not a manager added a manager key")
+	}
+
+	changes := 0
+	if resp := call("manager", "addCensusKeys", pubKey("other")); !resp.Ok || len(resp.PubKeys) != 2 {
+		t.Fatalf("manager cannot add a key: %s", resp.Message)
+	}
+	changes++
+	if resp := call("root", "delCensusKeys", pubKey("other")); !resp.Ok || len(resp.PubKeys) != 1 {
+		t.Fatalf("root key cannot remove a key: %s", resp.Message)
+	}
+	changes++
+	if resp := call("manager", "delCensusKeys", pubKey("manager")); resp.Ok {
+		t.Errorf("the last manager key was removed")
+	}
+	changes++
+
+	// once transferred, the owner of the prefix loses the access
+	if resp := call("owner", "transferCensus", pubKey("newManager")); !resp.Ok {
+		t.Fatal(resp.Message)
+	}
+	changes++
+	if resp := call("owner", "addClaim"); resp.Ok {
+		t.Errorf("the owner added a claim after transferring the census")
+	}
+	if resp := call("owner", "addCensusKeys", pubKey("owner")); resp.Ok {
+		t.Errorf("the owner added a manager key after transferring the census")
+	}
+	if resp := call("manager", "addClaim"); resp.Ok {
+		t.Errorf("the former manager added a claim after the transfer")
+	}
+	if resp := call("newManager", "addClaim"); !resp.Ok {
+		t.Errorf("the new manager cannot add a claim: %s", resp.Message)
+	}
+
+	// every key change, even the failed ones, is in the audit log
+	var entries []KeyAuditEntry
+	scanner := bufio.NewScanner(&audit)
+	for scanner.Scan() {
+		var entry KeyAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != changes {
+		t.Fatalf("expected %d audit log entries, got %d", changes, len(entries))
+	}
+	if entries[2].Error == "" || entries[3].Method != "transferCensus" ||
+		entries[3].Signer != signers["owner"].AddressString() || len(entries[3].Result) != 1 {
+		t.Errorf("wrong audit log entries %+v", entries)
+	}
+}
+
+func TestCensusKeysAuth(t *testing.T) {
+	signers := make(map[string]*ethereum.SignKeys)
+	for _, name := range []string{"root", "manager", "other"} {
+		s := ethereum.NewSignKeys()
+		if err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		signers[name] = s
+	}
+	pubKey := func(name string) string {
+		pub, _ := signers[name].HexString()
+		return pub
+	}
+	var m Manager
+	if err := m.Init(t.TempDir(), pubKey("root"), gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if resp := m.Handler(ctx, &types.MetaRequest{Method: "addCensus", CensusID: "test",
+		PubKeys: []string{pubKey("manager"), pubKey("other")}}, true, "", ethcommon.Address{}); !resp.Ok {
+		t.Fatal(resp.Message)
+	}
+
+	// the signer is recovered from a request which is not authenticated, but
+	// it cannot change the keys, even being the root key
+	if resp := m.Handler(ctx, &types.MetaRequest{Method: "delCensusKeys", CensusID: "test",
+		PubKeys: []string{pubKey("other")}}, false, "", signers["root"].Address()); resp.Ok {
+		t.Errorf("the root key removed a key without authentication")
+	}
+
+	// a request signed by a manager out of the time window is not valid
+	call := func(timestamp int32) *types.MetaResponse {
+		inner, err := json.Marshal(types.MetaRequest{Method: "delCensusKeys", CensusID: "test",
+			PubKeys: []string{pubKey("other")}, Timestamp: timestamp})
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := signers["manager"].Sign(inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := json.Marshal(types.RequestMessage{ID: "1", MetaRequest: inner, Signature: signature})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		m.HTTPhandler(ctx, w, httptest.NewRequest("POST", "/", bytes.NewReader(body)), signers["root"])
+		var outer types.ResponseMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &outer); err != nil {
+			t.Fatal(err)
+		}
+		var resp types.MetaResponse
+		if err := json.Unmarshal(outer.MetaResponse, &resp); err != nil {
+			t.Fatal(err)
+		}
+		return &resp
+	}
+	now := int32(time.Now().Unix())
+	if resp := call(now - 10*m.AuthWindow); resp.Ok {
+		t.Errorf("a request with a stale timestamp removed a key")
+	}
+	if ns, _ := m.namespace("test"); len(ns.Keys) != 2 {
+		t.Fatalf("the keys changed to %v", ns.Keys)
+	}
+	if resp := call(now); !resp.Ok || len(resp.PubKeys) != 1 {
+		t.Errorf("a valid request cannot remove a key: %s", resp.Message)
+	}
+}
@@ -34,6 +34,16 @@ var (
 		Name:      "retryQueue",
 		Help:      "Active queued census that failed but will be retried",
 	})
+	CensusUnloaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "census",
+		Name:      "unloaded",
+		Help:      "Census trees closed for being idle or over the loaded trees limit",
+	})
+	CensusReloaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "census",
+		Name:      "reloaded",
+		Help:      "Census trees loaded on demand",
+	})
 )
 
 // RegisterMetrics to the prometheus server
@@ -43,6 +53,8 @@ func (m *Manager) registerMetrics(ma *metrics.Agent) {
 	ma.Register(CensusLoaded)
 	ma.Register(CensusQueue)
 	ma.Register(CensusRetryQueue)
+	ma.Register(CensusUnloaded)
+	ma.Register(CensusReloaded)
 }
 
 // GetMetrics to the prometheus server
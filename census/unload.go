@@ -0,0 +1,92 @@
+package census
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.vocdoni.io/dvote/censustree"
+	"go.vocdoni.io/dvote/log"
+)
+
+// unloadInterval is the time between two checks for idle trees
+const unloadInterval = time.Minute
+
+// AcquireTree returns the tree of the namespace name, loading it if needed.
+// The tree is not unloaded until ReleaseTree is called.
+func (m *Manager) AcquireTree(name string) (censustree.Tree, error) {
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	if !m.Exists(name) {
+		return nil, fmt.Errorf("censusId not valid or not found %s", name)
+	}
+	_, loaded := m.Trees[name]
+	tr, err := m.LoadTree(name)
+	if err != nil {
+		return nil, err
+	}
+	m.treesInUse[name]++
+	if !loaded {
+		CensusReloaded.Inc()
+		m.unloadTrees()
+	}
+	return tr, nil
+}
+
+// ReleaseTree allows unloading the tree of the namespace name, once every
+// AcquireTree has its ReleaseTree. The tree of a namespace removed meanwhile
+// is destroyed then.
+func (m *Manager) ReleaseTree(name string) {
+	m.TreesMu.Lock()
+	defer m.TreesMu.Unlock()
+	if m.treesInUse[name] <= 1 {
+		delete(m.treesInUse, name)
+		if m.treesDeleted[name] {
+			delete(m.treesDeleted, name)
+			if err := m.destroyTree(name); err != nil {
+				log.Warnf("cannot remove census %s: (%v)", name, err)
+			}
+		}
+		return
+	}
+	m.treesInUse[name]--
+}
+
+// unloadTrees closes the trees not in use which have been idle for longer
+// than TreeIdleTimeout, and then the least recently used ones until there are
+// at most MaxLoadedTrees. Not thread safe.
+func (m *Manager) unloadTrees() {
+	var idle []string
+	for name := range m.Trees {
+		if m.treesInUse[name] == 0 {
+			idle = append(idle, name)
+		}
+	}
+	sort.Slice(idle, func(i, j int) bool {
+		return m.Trees[idle[i]].LastAccess() < m.Trees[idle[j]].LastAccess()
+	})
+	now := time.Now().Unix()
+	for _, name := range idle {
+		expired := m.TreeIdleTimeout > 0 &&
+			now-m.Trees[name].LastAccess() > int64(m.TreeIdleTimeout.Seconds())
+		overBudget := m.MaxLoadedTrees > 0 && len(m.Trees) > m.MaxLoadedTrees
+		if !expired && !overBudget {
+			break
+		}
+		m.UnloadTree(name)
+		CensusUnloaded.Inc()
+	}
+}
+
+// unloadDaemon periodically closes the idle trees
+func (m *Manager) unloadDaemon() {
+	for {
+		time.Sleep(unloadInterval)
+		if m.TreeIdleTimeout == 0 && m.MaxLoadedTrees == 0 {
+			continue
+		}
+		m.TreesMu.Lock()
+		m.unloadTrees()
+		m.TreesMu.Unlock()
+	}
+}
@@ -8,13 +8,24 @@ type Tree interface {
 	UnPublish()     // UnPublish will make the tree not available for queries
 	IsPublic() bool // Check if the census tree is available for queries or not
 	Add(key, value []byte) error
+	Delete(key []byte) error        // Delete removes a claim, it must exist
+	Update(key, value []byte) error // Update sets the value of an existing claim
 	GenProof(key, value []byte) (mproof []byte, err error)
 	CheckProof(key, value, root, mproof []byte) (included bool, err error)
 	Root() []byte
 	Dump(root []byte) (data []byte, err error) // Dump must return a format compatible with ImportDump
+	// DumpChunks calls send with the dump of each group of at most chunkSize
+	// claims, every chunk must be compatible with ImportDump
+	DumpChunks(root []byte, chunkSize int, send func(chunk []byte) error) error
 	DumpPlain(root []byte) (keys [][]byte, values [][]byte, err error)
+	// Iterate calls callback with the path, key and value of each claim of
+	// root, sorted by path, until it returns true. The path of a claim only
+	// depends on its key, so two roots can be diffed by merging them.
+	Iterate(root []byte, callback func(path, key, value []byte) bool) error
 	ImportDump(data []byte) error
 	Size(root []byte) (int64, error)
 	Snapshot(root []byte) (Tree, error)
 	HashExists(hash []byte) (bool, error)
+	Close() error   // Close releases the storage of the tree, which must be opened again to be used
+	Destroy() error // Destroy closes the tree and removes its storage, it must not be used afterwards
 }
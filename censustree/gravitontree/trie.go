@@ -2,7 +2,9 @@
 package gravitontree
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path"
 	"sync/atomic"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"go.vocdoni.io/dvote/log"
 	"go.vocdoni.io/dvote/statedb"
 	"go.vocdoni.io/dvote/statedb/gravitonstate"
+	"golang.org/x/crypto/blake2s"
 )
 
 type Tree struct {
@@ -137,17 +140,66 @@ func (t *Tree) Add(index, value []byte) error {
 	return err
 }
 
-// GenProof generates a merkle tree proof that can be later used on CheckProof() to validate it
+// Delete removes a claim from the merkle tree
+func (t *Tree) Delete(index []byte) error {
+	t.updateAccessTime()
+	if err := t.Tree.Delete(index); err != nil {
+		return err
+	}
+	_, err := t.store.Commit()
+	return err
+}
+
+// Update replaces the value of an existing claim of the merkle tree
+func (t *Tree) Update(index, value []byte) error {
+	t.updateAccessTime()
+	if len(value) > MaxValueSize {
+		return fmt.Errorf("value claim data too big")
+	}
+	// Both operations are committed at once, so no root is left without the claim
+	if err := t.Tree.Delete(index); err != nil {
+		return err
+	}
+	if err := t.Tree.Add(index, value); err != nil {
+		return err
+	}
+	_, err := t.store.Commit()
+	return err
+}
+
+// GenProof generates a merkle tree proof that can be later used on CheckProof() to validate it.
+// The proof includes the value of the claim, if value is not empty it must match.
 func (t *Tree) GenProof(index, value []byte) ([]byte, error) {
 	t.updateAccessTime()
 	proof, err := t.Tree.Proof(index)
 	if err != nil {
 		return nil, err
 	}
+	if proof != nil && len(value) > 0 {
+		if ok, err := checkValue(value, proof); err != nil || !ok {
+			return nil, fmt.Errorf("claim value does not match")
+		}
+	}
 	return proof, nil
 }
 
-// CheckProof standalone function for checking a merkle proof
+// ProofValue returns the value of the claim included in a merkle proof,
+// which is bound to the claim index when the proof is checked
+func ProofValue(mproof []byte) ([]byte, error) {
+	return gravitonstate.ProofValue(mproof)
+}
+
+// checkValue returns true if value is the claim value included in mproof
+func checkValue(value, mproof []byte) (bool, error) {
+	pvalue, err := gravitonstate.ProofValue(mproof)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(pvalue, value), nil
+}
+
+// CheckProof standalone function for checking a merkle proof.
+// If value is not empty, the proof must also be for that claim value.
 func CheckProof(index, value, root []byte, mproof []byte) (bool, error) {
 	if len(index) > gravitonstate.GravitonMaxKeySize {
 		return false, fmt.Errorf("index is too big, maximum allow is %d", gravitonstate.GravitonMaxKeySize)
@@ -158,6 +210,11 @@ func CheckProof(index, value, root []byte, mproof []byte) (bool, error) {
 	if len(root) != gravitonstate.GravitonHashSizeBytes {
 		return false, fmt.Errorf("root hash length is incorrect (expected %d)", gravitonstate.GravitonHashSizeBytes)
 	}
+	if len(value) > 0 {
+		if ok, err := checkValue(value, mproof); err != nil || !ok {
+			return false, err
+		}
+	}
 	return gravitonstate.Verify(index, mproof, root)
 }
 
@@ -173,6 +230,11 @@ func (t *Tree) CheckProof(index, value, root, mproof []byte) (bool, error) {
 		return false, fmt.Errorf("tree %s does not exist", t.name)
 	}
 	t.updateAccessTime()
+	if len(value) > 0 {
+		if ok, err := checkValue(value, mproof); err != nil || !ok {
+			return false, err
+		}
+	}
 	return t.Tree.Verify(index, mproof, root), nil
 }
 
@@ -209,6 +271,43 @@ func (t *Tree) Dump(root []byte) ([]byte, error) {
 	return bare.Marshal(&dump)
 }
 
+// DumpChunks dumps the merkle tree in chunks of at most chunkSize claims, so
+// the whole dump is never held in memory
+func (t *Tree) DumpChunks(root []byte, chunkSize int, send func(chunk []byte) error) error {
+	if chunkSize < 1 {
+		return fmt.Errorf("chunk size must be positive")
+	}
+	t.updateAccessTime()
+	tree := t.treeWithRoot(root)
+	if tree == nil {
+		return fmt.Errorf("dump: root not found %x", root)
+	}
+	dump := exportData{}
+	flush := func() error {
+		chunk, err := bare.Marshal(&dump)
+		if err != nil {
+			return err
+		}
+		dump.Elements = dump.Elements[:0]
+		return send(chunk)
+	}
+	var err error
+	tree.Iterate(nil, func(k, v []byte) bool {
+		ee := exportElement{Key: make([]byte, len(k)), Value: make([]byte, len(v))}
+		copy(ee.Key, k[:])
+		copy(ee.Value, v[:])
+		dump.Elements = append(dump.Elements, ee)
+		if len(dump.Elements) >= chunkSize {
+			err = flush()
+		}
+		return err != nil
+	})
+	if err != nil || len(dump.Elements) == 0 {
+		return err
+	}
+	return flush()
+}
+
 // Size returns the number of leaf nodes on the merkle tree
 func (t *Tree) Size(root []byte) (int64, error) {
 	tree := t.treeWithRoot(root)
@@ -240,6 +339,23 @@ func (t *Tree) DumpPlain(root []byte) ([][]byte, [][]byte, error) {
 	return indexes, values, err
 }
 
+// Iterate calls callback with the claims of root until it returns true. The
+// leaves are stored sorted by the blake2s hash of their index, which is the
+// path of the claim.
+func (t *Tree) Iterate(root []byte, callback func(path, index, value []byte) bool) error {
+	t.updateAccessTime()
+	tree := t.treeWithRoot(root)
+	if tree == nil {
+		return fmt.Errorf("iterate: root not found %x", root)
+	}
+	tree.Iterate(nil, func(k, v []byte) bool {
+		keyhash := blake2s.Sum256(k)
+		// Copy elements since it's not safe to hold on to the []byte values from Iterate
+		return callback(keyhash[:], append([]byte{}, k...), append([]byte{}, v...))
+	})
+	return nil
+}
+
 // ImportDump imports a partial or whole tree previously exported with Dump()
 func (t *Tree) ImportDump(data []byte) error {
 	t.updateAccessTime()
@@ -274,3 +390,23 @@ func (t *Tree) HashExists(hash []byte) (bool, error) {
 	}
 	return true, nil
 }
+
+// Destroy closes the tree and removes its storage directory
+func (t *Tree) Destroy() error {
+	if t.dataDir == "" {
+		return fmt.Errorf("tree %s has no storage directory", t.name)
+	}
+	if err := t.store.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(t.dataDir)
+}
+
+// Close closes the storage of the tree. Snapshots share the storage of their
+// tree, so closing them does nothing.
+func (t *Tree) Close() error {
+	if t.dataDir == "" {
+		return nil
+	}
+	return t.store.Close()
+}
@@ -3,6 +3,8 @@ package gravitontree
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -84,3 +86,141 @@ func TestTree(t *testing.T) {
 	}
 
 }
+
+func TestDeleteUpdate(t *testing.T) {
+	storage := t.TempDir()
+	tr1, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := NewTree("test2", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("number %d", i))
+		if err := tr1.Add(key, key); err != nil {
+			t.Fatal(err)
+		}
+		if i%3 == 0 {
+			continue
+		}
+		if err := tr2.Add(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i += 3 {
+		if err := tr1.Delete([]byte(fmt.Sprintf("number %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Fatalf("root after deleting is different (%x != %x)", tr1.Root(), tr2.Root())
+	}
+	if err := tr1.Delete([]byte("number 3")); err == nil {
+		t.Errorf("deleting a missing claim should fail")
+	}
+	if s, _ := tr1.Size(nil); s != 66 {
+		t.Errorf("size is wrong (have %d, expected 66)", s)
+	}
+
+	if err := tr1.Update([]byte("number 4"), []byte("new value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.Delete([]byte("number 4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.Add([]byte("number 4"), []byte("new value")); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Fatalf("root after updating is different (%x != %x)", tr1.Root(), tr2.Root())
+	}
+	if err := tr1.Update([]byte("number 6"), nil); err == nil {
+		t.Errorf("updating a missing claim should fail")
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	storage := t.TempDir()
+	tr, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Add([]byte("number 1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(storage, "test1")); !os.IsNotExist(err) {
+		t.Errorf("tree storage was not removed: %v", err)
+	}
+}
+
+func TestDumpChunks(t *testing.T) {
+	storage := t.TempDir()
+	tr1, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 250; i++ {
+		if err := tr1.Add([]byte(fmt.Sprintf("number %d", i)), []byte(fmt.Sprintf("%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr2, err := NewTree("test2", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := 0
+	if err := tr1.DumpChunks(tr1.Root(), 100, func(chunk []byte) error {
+		chunks++
+		return tr2.ImportDump(chunk)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if chunks != 3 {
+		t.Errorf("expected 3 chunks, got %d", chunks)
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Errorf("roots are different after importing the chunks")
+	}
+}
+
+func TestIterate(t *testing.T) {
+	tr, err := NewTree("test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := tr.Add([]byte(fmt.Sprintf("number %d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var last []byte
+	count := 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		if last != nil && bytes.Compare(last, path) >= 0 {
+			t.Errorf("path %x is not after %x", path, last)
+		}
+		last = path
+		count++
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 100 {
+		t.Errorf("iterated %d claims, expected 100", count)
+	}
+	count = 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		count++
+		return count == 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("iteration did not stop, %d claims iterated", count)
+	}
+}
@@ -0,0 +1,295 @@
+package iden3tree
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/iden3/go-iden3-core/common"
+	iden3db "github.com/iden3/go-iden3-core/db"
+	"github.com/iden3/go-iden3-core/merkletree"
+)
+
+// The iden3 merkletree cannot remove leaves, and it refuses to store a node
+// that already exists, so adding back a deleted claim would fail. The
+// functions of this file modify the tree at the storage level, producing the
+// same nodes and root the library would produce for the resulting set of
+// claims.
+
+// rootNodeKey is the storage key of the current root, as used by the library
+var rootNodeKey = []byte("currentroot")
+
+// Delete removes a claim from the merkle tree
+func (t *Tree) Delete(index []byte) error {
+	t.updateAccessTime()
+	if t.readOnly {
+		return merkletree.ErrNotWritable
+	}
+	e, err := t.entry(index, nil)
+	if err != nil {
+		return err
+	}
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+	tx, err := newNodeTx(t.Tree.Storage())
+	if err != nil {
+		return err
+	}
+	root, err := t.remove(tx, e)
+	if err != nil {
+		tx.Close()
+		return err
+	}
+	return t.commitRoot(tx, root)
+}
+
+// Update replaces the value of an existing claim of the merkle tree
+func (t *Tree) Update(index, value []byte) error {
+	t.updateAccessTime()
+	if t.readOnly {
+		return merkletree.ErrNotWritable
+	}
+	e, err := t.entry(index, value)
+	if err != nil {
+		return err
+	}
+	if !merkletree.CheckEntryInField(*e) {
+		return fmt.Errorf("claim elements not inside the finite field")
+	}
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+	tx, err := newNodeTx(t.Tree.Storage())
+	if err != nil {
+		return err
+	}
+	root, err := t.remove(tx, e)
+	if err == nil {
+		root, err = t.insert(tx, root, e)
+	}
+	if err != nil {
+		tx.Close()
+		return err
+	}
+	return t.commitRoot(tx, root)
+}
+
+// addDeleted adds a claim whose nodes might already exist on the storage
+func (t *Tree) addDeleted(e *merkletree.Entry) error {
+	tx, err := newNodeTx(t.Tree.Storage())
+	if err != nil {
+		return err
+	}
+	root, err := t.insert(tx, t.Tree.RootKey(), e)
+	if err != nil {
+		tx.Close()
+		return err
+	}
+	return t.commitRoot(tx, root)
+}
+
+// commitRoot sets root as the current root of the tree and commits tx
+func (t *Tree) commitRoot(tx iden3db.Tx, root *merkletree.Hash) error {
+	tx.Put(rootNodeKey, append([]byte{byte(merkletree.DBEntryTypeRoot)}, root[:]...))
+	if err := tx.Commit(); err != nil {
+		tx.Close()
+		return err
+	}
+	// the root of the library tree cannot be set, so it is loaded again
+	mt, err := merkletree.NewMerkleTree(t.Tree.Storage(), t.Tree.MaxLevels())
+	if err != nil {
+		return err
+	}
+	t.Tree = mt
+	return nil
+}
+
+// walk descends from root following path and returns the siblings found on
+// the way and the node where it stops, which is either empty or a leaf
+func (t *Tree) walk(tx iden3db.Tx, root *merkletree.Hash, path []bool) ([]*merkletree.Hash, *merkletree.Node, error) {
+	var siblings []*merkletree.Hash
+	key := root
+	for lvl := 0; lvl < t.Tree.MaxLevels(); lvl++ {
+		n, err := getNode(tx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n.Type != merkletree.NodeTypeMiddle {
+			return siblings, n, nil
+		}
+		if path[lvl] {
+			siblings = append(siblings, n.ChildL)
+			key = n.ChildR
+		} else {
+			siblings = append(siblings, n.ChildR)
+			key = n.ChildL
+		}
+	}
+	return nil, nil, merkletree.ErrReachedMaxLevel
+}
+
+// remove deletes the leaf with the index of e from the tree of root and
+// returns the new root
+func (t *Tree) remove(tx iden3db.Tx, e *merkletree.Entry) (*merkletree.Hash, error) {
+	hIndex, err := e.HIndex()
+	if err != nil {
+		return nil, err
+	}
+	path := getPath(t.Tree.MaxLevels(), hIndex)
+	siblings, n, err := t.walk(tx, t.Tree.RootKey(), path)
+	if err != nil {
+		return nil, err
+	}
+	if n.Type != merkletree.NodeTypeLeaf {
+		return nil, fmt.Errorf("claim not found")
+	}
+	leafIndex, err := n.Entry.HIndex()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(leafIndex[:], hIndex[:]) {
+		return nil, fmt.Errorf("claim not found")
+	}
+	return rebuild(tx, path, siblings, &merkletree.HashZero, true)
+}
+
+// insert adds the leaf of e to the tree of root and returns the new root
+func (t *Tree) insert(tx iden3db.Tx, root *merkletree.Hash, e *merkletree.Entry) (*merkletree.Hash, error) {
+	hIndex, err := e.HIndex()
+	if err != nil {
+		return nil, err
+	}
+	path := getPath(t.Tree.MaxLevels(), hIndex)
+	siblings, n, err := t.walk(tx, root, path)
+	if err != nil {
+		return nil, err
+	}
+	leaf := merkletree.NewNodeLeaf(e)
+	key, err := putNode(tx, leaf)
+	if err != nil {
+		return nil, err
+	}
+	if n.Type == merkletree.NodeTypeLeaf {
+		oldIndex, err := n.Entry.HIndex()
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(oldIndex[:], hIndex[:]) {
+			return nil, merkletree.ErrEntryIndexAlreadyExists
+		}
+		oldKey, err := n.Key()
+		if err != nil {
+			return nil, err
+		}
+		// push both leaves down until their paths diverge
+		oldPath := getPath(t.Tree.MaxLevels(), oldIndex)
+		lvl := len(siblings)
+		if lvl > t.Tree.MaxLevels()-2 {
+			return nil, merkletree.ErrReachedMaxLevel
+		}
+		for path[lvl] == oldPath[lvl] {
+			if lvl++; lvl > t.Tree.MaxLevels()-2 {
+				return nil, merkletree.ErrReachedMaxLevel
+			}
+		}
+		pushed := make([]*merkletree.Hash, lvl+1-len(siblings))
+		for i := range pushed {
+			pushed[i] = &merkletree.HashZero
+		}
+		pushed[len(pushed)-1] = oldKey
+		siblings = append(siblings, pushed...)
+	}
+	return rebuild(tx, path, siblings, key, false)
+}
+
+// rebuild recomputes the middle nodes from the bottom of siblings up to the
+// root, with key as the new node below them. If compact is set, key is either
+// empty or a leaf, and it moves up while it has no sibling, since the library
+// never stores a middle node with a leaf and an empty child.
+func rebuild(tx iden3db.Tx, path []bool, siblings []*merkletree.Hash,
+	key *merkletree.Hash, compact bool) (*merkletree.Hash, error) {
+	for lvl := len(siblings) - 1; lvl >= 0; lvl-- {
+		sibling := siblings[lvl]
+		if compact {
+			if bytes.Equal(sibling[:], merkletree.HashZero[:]) {
+				continue
+			}
+			if bytes.Equal(key[:], merkletree.HashZero[:]) {
+				n, err := getNode(tx, sibling)
+				if err != nil {
+					return nil, err
+				}
+				if n.Type == merkletree.NodeTypeLeaf {
+					key = sibling
+					continue
+				}
+			}
+			compact = false
+		}
+		var err error
+		if path[lvl] {
+			key, err = putNode(tx, merkletree.NewNodeMiddle(sibling, key))
+		} else {
+			key, err = putNode(tx, merkletree.NewNodeMiddle(key, sibling))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// getNode returns the node of key, including the ones written on tx
+func getNode(tx iden3db.Tx, key *merkletree.Hash) (*merkletree.Node, error) {
+	if bytes.Equal(key[:], merkletree.HashZero[:]) {
+		return merkletree.NewNodeEmpty(), nil
+	}
+	data, err := tx.Get(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return merkletree.NewNodeFromBytes(data)
+}
+
+// putNode writes n on tx, overwriting it if it already exists, and returns its key
+func putNode(tx iden3db.Tx, n *merkletree.Node) (*merkletree.Hash, error) {
+	k, err := n.Key()
+	if err != nil {
+		return nil, err
+	}
+	tx.Put(k[:], n.Value())
+	return k, nil
+}
+
+// getPath returns the path of a leaf from the bits of its index hash, as the library does
+func getPath(levels int, hIndex *merkletree.Hash) []bool {
+	path := make([]bool, levels)
+	for n := range path {
+		path[n] = common.TestBitBigEndian(hIndex[:], uint(n))
+	}
+	return path
+}
+
+// nodeTx is a storage transaction which can read its own pending writes
+type nodeTx struct {
+	iden3db.Tx
+	written map[string][]byte
+}
+
+func newNodeTx(storage iden3db.Storage) (*nodeTx, error) {
+	tx, err := storage.NewTx()
+	if err != nil {
+		return nil, err
+	}
+	return &nodeTx{Tx: tx, written: make(map[string][]byte)}, nil
+}
+
+func (tx *nodeTx) Get(key []byte) ([]byte, error) {
+	if v, ok := tx.written[string(key)]; ok {
+		return v, nil
+	}
+	return tx.Tx.Get(key)
+}
+
+func (tx *nodeTx) Put(key, value []byte) {
+	tx.written[string(key)] = value
+	tx.Tx.Put(key, value)
+}
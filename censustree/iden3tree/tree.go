@@ -3,10 +3,14 @@ package iden3tree
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"math/bits"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +27,9 @@ type Tree struct {
 	Tree           *merkletree.MerkleTree
 	public         uint32
 	lastAccessUnix int64 // a unix timestamp, used via sync/atomic
+	readOnly       bool
+	writeLock      sync.Mutex
+	dataDir        string
 }
 
 type exportElement struct {
@@ -73,6 +80,7 @@ func (t *Tree) Init(name, storageDir string) error {
 		return err
 	}
 	t.Tree = mt
+	t.dataDir = dbDir
 	t.updateAccessTime()
 	return nil
 }
@@ -152,7 +160,14 @@ func (t *Tree) Add(index, value []byte) error {
 	if err != nil {
 		return err
 	}
-	return t.Tree.AddClaim(c)
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+	err = t.Tree.AddClaim(c)
+	if errors.Is(err, merkletree.ErrNodeKeyAlreadyExists) {
+		// the claim was deleted, so its nodes are still on the storage
+		return t.addDeleted(c.Entry())
+	}
+	return err
 }
 
 // GenProof generates a merkle tree proof that can be later used on CheckProof() to validate it
@@ -239,6 +254,49 @@ func (t *Tree) Dump(root []byte) (keys []byte, err error) {
 	return bare.Marshal(&dump)
 }
 
+// DumpChunks dumps the merkle tree in chunks of at most chunkSize claims, so
+// the whole dump is never held in memory
+func (t *Tree) DumpChunks(root []byte, chunkSize int, send func(chunk []byte) error) error {
+	if chunkSize < 1 {
+		return fmt.Errorf("chunk size must be positive")
+	}
+	var rootHash *merkletree.Hash
+	if len(root) > 0 {
+		rootHash = new(merkletree.Hash)
+		if n := copy(rootHash[:], root); n != HashSize {
+			return fmt.Errorf("root hash length not correct, expected %d got %d", HashSize, n)
+		}
+	}
+	t.updateAccessTime()
+	dump := exportData{}
+	flush := func() error {
+		chunk, err := bare.Marshal(&dump)
+		if err != nil {
+			return err
+		}
+		dump.Elements = dump.Elements[:0]
+		return send(chunk)
+	}
+	var sendErr error
+	err := t.Tree.Walk(rootHash, func(n *merkletree.Node) {
+		// Walk cannot be stopped, so skip the remaining leaves after an error
+		if sendErr != nil || n.Type != merkletree.NodeTypeLeaf {
+			return
+		}
+		dump.Elements = append(dump.Elements, exportElement{Key: n.Entry.Bytes()})
+		if len(dump.Elements) >= chunkSize {
+			sendErr = flush()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if sendErr != nil || len(dump.Elements) == 0 {
+		return sendErr
+	}
+	return flush()
+}
+
 // Size returns the number of leaf nodes on the merkle tree
 func (t *Tree) Size(root []byte) (int64, error) {
 	var err error
@@ -284,6 +342,43 @@ func (t *Tree) DumpPlain(root []byte) ([][]byte, [][]byte, error) {
 	return indexes, values, err
 }
 
+// Iterate calls callback with the claims of root until it returns true. The
+// path of a claim is the hash of its index with the bit order reversed, as
+// the tree takes the path from the least significant bit of the hash.
+func (t *Tree) Iterate(root []byte, callback func(path, index, value []byte) bool) error {
+	var rootHash *merkletree.Hash
+	if len(root) > 0 {
+		rootHash = new(merkletree.Hash)
+		if n := copy(rootHash[:], root); n != HashSize {
+			return fmt.Errorf("root hash length not correct, expected %d got %d", HashSize, n)
+		}
+	}
+	t.updateAccessTime()
+	var stop bool
+	var iterErr error
+	err := t.Tree.Walk(rootHash, func(n *merkletree.Node) {
+		// Walk cannot be stopped, so skip the remaining leaves
+		if stop || iterErr != nil || n.Type != merkletree.NodeTypeLeaf {
+			return
+		}
+		hIndex, err := n.Entry.HIndex()
+		if err != nil {
+			iterErr = err
+			return
+		}
+		path := make([]byte, len(hIndex))
+		for i := range hIndex {
+			path[i] = bits.Reverse8(hIndex[len(hIndex)-1-i])
+		}
+		index, value := getDataFromClaim(claims.NewClaimBasicFromEntry(n.Entry))
+		stop = callback(path, index, value)
+	})
+	if err != nil {
+		return err
+	}
+	return iterErr
+}
+
 // ImportDump imports a partial or whole tree previously exported with Dump().
 // TO-DO import also values (currently only keys are imported)
 func (t *Tree) ImportDump(data []byte) error {
@@ -312,6 +407,7 @@ func (t *Tree) Snapshot(root []byte) (censustree.Tree, error) {
 	}
 	mt, err := t.Tree.Snapshot(rootHash)
 	snapshotTree.Tree = mt
+	snapshotTree.readOnly = true
 	return snapshotTree, err
 }
 
@@ -330,3 +426,26 @@ func (t *Tree) HashExists(hash []byte) (bool, error) {
 	}
 	return true, nil
 }
+
+// Destroy closes the tree and removes its storage directory
+func (t *Tree) Destroy() error {
+	if t.dataDir == "" {
+		return fmt.Errorf("tree has no storage directory")
+	}
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+	t.Tree.Storage().Close()
+	return os.RemoveAll(t.dataDir)
+}
+
+// Close closes the storage of the tree. Snapshots share the storage of their
+// tree, so closing them does nothing.
+func (t *Tree) Close() error {
+	if t.readOnly {
+		return nil
+	}
+	t.writeLock.Lock()
+	defer t.writeLock.Unlock()
+	t.Tree.Storage().Close()
+	return nil
+}
@@ -3,9 +3,11 @@ package iden3tree
 import (
 	"bytes"
 	"encoding/base64"
+	"fmt"
 	"testing"
 
 	"github.com/iden3/go-iden3-core/core/claims"
+	"go.vocdoni.io/dvote/censustree"
 	"go.vocdoni.io/dvote/test/testcommon/testutil"
 )
 
@@ -72,3 +74,146 @@ func TestClaimParsers(t *testing.T) {
 		t.Errorf("should return error to avoid overflow")
 	}
 }
+
+func TestDeleteUpdate(t *testing.T) {
+	storage := t.TempDir()
+	tr1, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := NewTree("test2", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("number %d", i))
+		if err := tr1.Add(key, nil); err != nil {
+			t.Fatal(err)
+		}
+		if i%3 == 0 {
+			continue
+		}
+		if err := tr2.Add(key, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i += 3 {
+		if err := tr1.Delete([]byte(fmt.Sprintf("number %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Fatalf("root after deleting is different (%x != %x)", tr1.Root(), tr2.Root())
+	}
+	if err := tr1.Delete([]byte("number 3")); err == nil {
+		t.Errorf("deleting a missing claim should fail")
+	}
+
+	// Add back a deleted claim
+	for _, tr := range []censustree.Tree{tr1, tr2} {
+		if err := tr.Add([]byte("number 3"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Fatalf("root after adding back is different (%x != %x)", tr1.Root(), tr2.Root())
+	}
+
+	// Update a claim on tr1 and create it with the new value on tr2
+	if err := tr1.Update([]byte("number 4"), []byte("new value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.Delete([]byte("number 4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.Add([]byte("number 4"), []byte("new value")); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Fatalf("root after updating is different (%x != %x)", tr1.Root(), tr2.Root())
+	}
+	proof, err := tr1.GenProof([]byte("number 4"), []byte("new value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := tr1.CheckProof([]byte("number 4"), []byte("new value"), nil, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Errorf("proof of the updated claim is invalid")
+	}
+	if err := tr1.Update([]byte("number 6"), nil); err == nil {
+		t.Errorf("updating a missing claim should fail")
+	}
+	if s, _ := tr1.Size(tr1.Root()); s != 67 {
+		t.Errorf("size is wrong (have %d, expected 67)", s)
+	}
+}
+
+func TestDumpChunks(t *testing.T) {
+	storage := t.TempDir()
+	tr1, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 250; i++ {
+		if err := tr1.Add([]byte(fmt.Sprintf("number %d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr2, err := NewTree("test2", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := 0
+	if err := tr1.DumpChunks(tr1.Root(), 100, func(chunk []byte) error {
+		chunks++
+		return tr2.ImportDump(chunk)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if chunks != 3 {
+		t.Errorf("expected 3 chunks, got %d", chunks)
+	}
+	if !bytes.Equal(tr1.Root(), tr2.Root()) {
+		t.Errorf("roots are different after importing the chunks")
+	}
+}
+
+func TestIterate(t *testing.T) {
+	tr, err := NewTree("test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := tr.Add([]byte(fmt.Sprintf("number %d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var last []byte
+	count := 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		if last != nil && bytes.Compare(last, path) >= 0 {
+			t.Errorf("path %x is not after %x", path, last)
+		}
+		last = path
+		count++
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 100 {
+		t.Errorf("iterated %d claims, expected 100", count)
+	}
+	count = 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		count++
+		return count == 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("iteration did not stop, %d claims iterated", count)
+	}
+}
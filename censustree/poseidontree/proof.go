@@ -0,0 +1,117 @@
+package poseidontree
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// CircuitInputs are the inputs of a circuit verifying the inclusion of a
+// claim, named as the signals of the circomlib SMTVerifier template and
+// encoded as decimal strings
+type CircuitInputs struct {
+	Enabled  string   `json:"enabled"`
+	Fnc      string   `json:"fnc"` // 0 for inclusion
+	Root     string   `json:"root"`
+	Siblings []string `json:"siblings"`
+	OldKey   string   `json:"oldKey"`
+	OldValue string   `json:"oldValue"`
+	IsOld0   string   `json:"isOld0"`
+	Key      string   `json:"key"`
+	Value    string   `json:"value"`
+}
+
+// proofSiblings returns the siblings of a proof generated with GenProof
+func proofSiblings(mproof []byte) ([]*big.Int, error) {
+	if len(mproof) != Levels*HashSize {
+		return nil, fmt.Errorf("proof length is incorrect (expected %d)", Levels*HashSize)
+	}
+	siblings := make([]*big.Int, Levels)
+	for i := range siblings {
+		s, err := elem(mproof[i*HashSize : (i+1)*HashSize])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sibling %d: %w", i, err)
+		}
+		siblings[i] = s
+	}
+	return siblings, nil
+}
+
+// NewCircuitInputs returns the circuit inputs proving the inclusion of the
+// claim index and value in the tree with root, from a proof generated with
+// GenProof
+func NewCircuitInputs(index, value, root, mproof []byte) (*CircuitInputs, error) {
+	key, err := elem(index)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim index: %w", err)
+	}
+	v, err := valueElem(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim value: %w", err)
+	}
+	r, err := elem(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	siblings, err := proofSiblings(mproof)
+	if err != nil {
+		return nil, err
+	}
+	inputs := &CircuitInputs{
+		Enabled:  "1",
+		Fnc:      "0",
+		Root:     r.String(),
+		OldKey:   "0",
+		OldValue: "0",
+		IsOld0:   "0",
+		Key:      key.String(),
+		Value:    v.String(),
+	}
+	for _, s := range siblings {
+		inputs.Siblings = append(inputs.Siblings, s.String())
+	}
+	return inputs, nil
+}
+
+// CheckProof standalone function for checking a merkle proof.
+// The value must be the claim value, since it is part of the leaf hash.
+func CheckProof(index, value, root, mproof []byte) (bool, error) {
+	if len(root) != HashSize {
+		return false, fmt.Errorf("root hash length is incorrect (expected %d)", HashSize)
+	}
+	key, err := elem(index)
+	if err != nil {
+		return false, fmt.Errorf("invalid claim index: %w", err)
+	}
+	v, err := valueElem(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid claim value: %w", err)
+	}
+	siblings, err := proofSiblings(mproof)
+	if err != nil {
+		return false, err
+	}
+	// the leaf is right below the last non empty sibling
+	depth := 0
+	for i, s := range siblings {
+		if s.Sign() != 0 {
+			depth = i + 1
+		}
+	}
+	hash, err := leafHash(key, v)
+	if err != nil {
+		return false, err
+	}
+	for i := depth - 1; i >= 0; i-- {
+		sibling := mproof[i*HashSize : (i+1)*HashSize]
+		if pathBit(key, i) == 0 {
+			hash, err = middleHash(hash, sibling)
+		} else {
+			hash, err = middleHash(sibling, hash)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return bytes.Equal(hash, root), nil
+}
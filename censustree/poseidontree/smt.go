@@ -0,0 +1,342 @@
+package poseidontree
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/utils"
+
+	"go.vocdoni.io/dvote/crypto/snarks"
+	"go.vocdoni.io/dvote/db"
+)
+
+const (
+	nodeMiddle = byte(1)
+	nodeLeaf   = byte(2)
+)
+
+var (
+	nodePrefix = []byte("n")
+	rootKey    = []byte("root")
+	// emptyHash is the hash of an empty subtree
+	emptyHash = make([]byte, HashSize)
+)
+
+// node is a middle node, with the hashes of its children, or a leaf, with
+// the claim data
+type node struct {
+	typ         byte
+	left, right []byte
+	index       []byte
+	value       []byte
+}
+
+// elem returns the field element encoded as little endian in b
+func elem(b []byte) (*big.Int, error) {
+	if len(b) > HashSize {
+		return nil, fmt.Errorf("%d bytes do not fit in a field element", len(b))
+	}
+	e := utils.SetBigIntFromLEBytes(new(big.Int), b)
+	if !utils.CheckBigIntInField(e) {
+		return nil, fmt.Errorf("%x is not in the field", b)
+	}
+	return e, nil
+}
+
+// valueElem returns the field element of a claim value, which is encoded as
+// a big endian unsigned integer, as the census weights are
+func valueElem(b []byte) (*big.Int, error) {
+	if len(b) > HashSize {
+		return nil, fmt.Errorf("%d bytes do not fit in a field element", len(b))
+	}
+	e := new(big.Int).SetBytes(b)
+	if !utils.CheckBigIntInField(e) {
+		return nil, fmt.Errorf("%x is not in the field", b)
+	}
+	return e, nil
+}
+
+// elemBytes returns the little endian encoding of the field element e
+func elemBytes(e *big.Int) []byte {
+	b := utils.BigIntLEBytes(e)
+	return b[:]
+}
+
+// hashElems returns the encoded Poseidon hash of the field elements elems
+func hashElems(elems ...*big.Int) ([]byte, error) {
+	h, err := snarks.PoseidonHash(elems...)
+	if err != nil {
+		return nil, err
+	}
+	return elemBytes(h), nil
+}
+
+// leafHash returns the hash of a leaf, H(key, value, 1)
+func leafHash(key, value *big.Int) ([]byte, error) {
+	return hashElems(key, value, big.NewInt(1))
+}
+
+// middleHash returns the hash of a middle node, H(left, right)
+func middleHash(left, right []byte) ([]byte, error) {
+	l, err := elem(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := elem(right)
+	if err != nil {
+		return nil, err
+	}
+	return hashElems(l, r)
+}
+
+func isEmpty(hash []byte) bool {
+	return len(hash) == 0 || bytes.Equal(hash, emptyHash)
+}
+
+// pathBit returns the direction of key at depth, 0 for left and 1 for right
+func pathBit(key *big.Int, depth int) uint {
+	return key.Bit(depth)
+}
+
+func newLeaf(index, value []byte) (*node, error) {
+	if _, err := elem(index); err != nil {
+		return nil, fmt.Errorf("invalid claim index: %w", err)
+	}
+	if _, err := valueElem(value); err != nil {
+		return nil, fmt.Errorf("invalid claim value: %w", err)
+	}
+	return &node{typ: nodeLeaf, index: index, value: value}, nil
+}
+
+func (n *node) key() *big.Int {
+	k, _ := elem(n.index)
+	return k
+}
+
+func (n *node) hash() ([]byte, error) {
+	if n.typ == nodeMiddle {
+		return middleHash(n.left, n.right)
+	}
+	v, err := valueElem(n.value)
+	if err != nil {
+		return nil, err
+	}
+	return leafHash(n.key(), v)
+}
+
+func (n *node) marshal() []byte {
+	if n.typ == nodeMiddle {
+		return append(append([]byte{nodeMiddle}, n.left...), n.right...)
+	}
+	b := append([]byte{nodeLeaf, byte(len(n.index))}, n.index...)
+	return append(b, n.value...)
+}
+
+func unmarshalNode(b []byte) (*node, error) {
+	switch {
+	case len(b) == 1+2*HashSize && b[0] == nodeMiddle:
+		return &node{typ: nodeMiddle, left: b[1 : 1+HashSize], right: b[1+HashSize:]}, nil
+	case len(b) >= 2 && b[0] == nodeLeaf && len(b) >= 2+int(b[1]):
+		return &node{typ: nodeLeaf, index: b[2 : 2+b[1]], value: b[2+b[1]:]}, nil
+	}
+	return nil, fmt.Errorf("invalid node encoding")
+}
+
+// tx accumulates the nodes written by an operation on the tree, so they are
+// stored at once with the resulting root
+type tx struct {
+	store   db.Database
+	written map[string][]byte
+}
+
+func newTx(store db.Database) *tx {
+	return &tx{store: store, written: make(map[string][]byte)}
+}
+
+func (t *tx) get(hash []byte) (*node, error) {
+	key := append(append([]byte{}, nodePrefix...), hash...)
+	b, ok := t.written[string(key)]
+	if !ok {
+		var err error
+		if b, err = t.store.Get(key); err != nil {
+			return nil, fmt.Errorf("node %x not found: %w", hash, err)
+		}
+	}
+	return unmarshalNode(b)
+}
+
+func (t *tx) put(n *node) ([]byte, error) {
+	hash, err := n.hash()
+	if err != nil {
+		return nil, err
+	}
+	t.written[string(append(append([]byte{}, nodePrefix...), hash...))] = n.marshal()
+	return hash, nil
+}
+
+func (t *tx) putMiddle(bit uint, child, sibling []byte) ([]byte, error) {
+	if bit == 0 {
+		return t.put(&node{typ: nodeMiddle, left: child, right: sibling})
+	}
+	return t.put(&node{typ: nodeMiddle, left: sibling, right: child})
+}
+
+func (t *tx) commit(root []byte) error {
+	batch := t.store.NewBatch()
+	for k, v := range t.written {
+		if err := batch.Put([]byte(k), v); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(rootKey, root); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// walk follows the path of key from root and returns the siblings on the
+// way and the first empty or leaf node found, which is nil if empty
+func (t *tx) walk(root []byte, key *big.Int) ([][]byte, *node, error) {
+	var siblings [][]byte
+	hash := root
+	for depth := 0; ; depth++ {
+		if isEmpty(hash) {
+			return siblings, nil, nil
+		}
+		n, err := t.get(hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n.typ == nodeLeaf {
+			return siblings, n, nil
+		}
+		if depth >= Levels {
+			return nil, nil, fmt.Errorf("tree deeper than %d levels", Levels)
+		}
+		if pathBit(key, depth) == 0 {
+			siblings = append(siblings, n.right)
+			hash = n.left
+		} else {
+			siblings = append(siblings, n.left)
+			hash = n.right
+		}
+	}
+}
+
+// up rebuilds the path of key from the node hash with siblings and returns
+// the new root
+func (t *tx) up(hash []byte, key *big.Int, siblings [][]byte) ([]byte, error) {
+	var err error
+	for i := len(siblings) - 1; i >= 0; i-- {
+		if hash, err = t.putMiddle(pathBit(key, i), hash, siblings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return hash, nil
+}
+
+// insert adds leaf to the tree with root and returns the new root
+func (t *tx) insert(root []byte, leaf *node) ([]byte, error) {
+	key := leaf.key()
+	siblings, old, err := t.walk(root, key)
+	if err != nil {
+		return nil, err
+	}
+	var hash []byte
+	if old == nil {
+		hash, err = t.put(leaf)
+	} else {
+		if old.key().Cmp(key) == 0 {
+			return nil, fmt.Errorf("claim %x already exists", leaf.index)
+		}
+		hash, err = t.split(old, leaf, len(siblings))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.up(hash, key, siblings)
+}
+
+// split returns the subtree at depth containing the leaves old and leaf,
+// whose paths are the same until depth
+func (t *tx) split(old, leaf *node, depth int) ([]byte, error) {
+	if depth >= Levels {
+		return nil, fmt.Errorf("claim %x cannot be added, the tree is limited to %d levels", leaf.index, Levels)
+	}
+	oldBit, bit := pathBit(old.key(), depth), pathBit(leaf.key(), depth)
+	if oldBit != bit {
+		oldHash, err := old.hash()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := t.put(leaf)
+		if err != nil {
+			return nil, err
+		}
+		return t.putMiddle(bit, hash, oldHash)
+	}
+	hash, err := t.split(old, leaf, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	return t.putMiddle(bit, hash, emptyHash)
+}
+
+// remove deletes the leaf of key from the tree with root and returns the new
+// root. A leaf left alone in a subtree is moved up, so each leaf is at the
+// shortest depth which tells it apart from the rest.
+func (t *tx) remove(root []byte, key *big.Int) ([]byte, error) {
+	siblings, leaf, err := t.walk(root, key)
+	if err != nil {
+		return nil, err
+	}
+	if leaf == nil || leaf.key().Cmp(key) != 0 {
+		return nil, fmt.Errorf("claim not found")
+	}
+	hash := emptyHash
+	// lone is true while the subtree is empty or a single leaf
+	lone := true
+	for i := len(siblings) - 1; i >= 0; i-- {
+		sibling := siblings[i]
+		if lone {
+			if isEmpty(sibling) {
+				continue
+			}
+			if isEmpty(hash) {
+				n, err := t.get(sibling)
+				if err != nil {
+					return nil, err
+				}
+				if n.typ == nodeLeaf {
+					hash = sibling
+					continue
+				}
+			}
+			lone = false
+		}
+		if hash, err = t.putMiddle(pathBit(key, i), hash, sibling); err != nil {
+			return nil, err
+		}
+	}
+	return hash, nil
+}
+
+// iterate calls f with the index and value of every leaf of the tree with
+// root, until f returns an error
+func (t *tx) iterate(hash []byte, f func(index, value []byte) error) error {
+	if isEmpty(hash) {
+		return nil
+	}
+	n, err := t.get(hash)
+	if err != nil {
+		return err
+	}
+	if n.typ == nodeLeaf {
+		return f(n.index, n.value)
+	}
+	if err := t.iterate(n.left, f); err != nil {
+		return err
+	}
+	return t.iterate(n.right, f)
+}
@@ -0,0 +1,389 @@
+// Package poseidontree provides a sparse merkle census tree hashed with
+// Poseidon, whose proofs can be verified by zk-SNARK circuits
+package poseidontree
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"git.sr.ht/~sircmpwn/go-bare"
+
+	"go.vocdoni.io/dvote/censustree"
+	"go.vocdoni.io/dvote/db"
+)
+
+// Tree is a sparse merkle tree of at most Levels levels. The index of a claim
+// is a little endian field element, as the Poseidon hashes of
+// snarks.Poseidon.Hash, and its value a big endian unsigned integer, as the
+// census weights encoded with big.Int.Bytes. The path of a claim is given by
+// the bits of its index starting from the least significant one, so the
+// indexes must differ in their first Levels bits, as hashes do. Leaves are
+// hashed as H(index, value, 1) and middle nodes as H(left, right), so the
+// roots are the ones of the go-iden3 and circomlib sparse merkle trees with
+// the same Poseidon hash. Roots and proof siblings are little endian.
+type Tree struct {
+	store          db.Database
+	root           []byte
+	lock           sync.RWMutex // protects root and serializes the writes
+	readOnly       bool
+	dataDir        string
+	public         uint32
+	lastAccessUnix int64 // a unix timestamp, used via sync/atomic
+}
+
+type exportElement struct {
+	Key   []byte `bare:"key"`
+	Value []byte `bare:"value"`
+}
+
+type exportData struct {
+	Elements []exportElement `bare:"elements"`
+}
+
+// errStopIteration stops iterate when the callback of Iterate returns true
+var errStopIteration = errors.New("iteration stopped")
+
+const (
+	// Levels is the maximum depth of the tree and the number of siblings of
+	// a proof
+	Levels       = 64
+	HashSize     = 32
+	MaxKeySize   = HashSize
+	MaxValueSize = HashSize
+)
+
+// NewTree opens or creates a merkle tree under the given storage.
+func NewTree(name, storageDir string) (censustree.Tree, error) {
+	tr := &Tree{}
+	if err := tr.Init(name, storageDir); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+func (t *Tree) Init(name, storageDir string) error {
+	dir := filepath.Join(storageDir, "poseidontree.db."+strings.TrimSpace(name))
+	store, err := db.NewBadgerDB(dir)
+	if err != nil {
+		return err
+	}
+	root := emptyHash
+	if ok, err := store.Has(rootKey); err != nil {
+		return err
+	} else if ok {
+		if root, err = store.Get(rootKey); err != nil {
+			return err
+		}
+	}
+	t.store = store
+	t.root = root
+	t.dataDir = dir
+	t.updateAccessTime()
+	return nil
+}
+
+func (t *Tree) MaxKeySize() int {
+	return MaxKeySize
+}
+
+// LastAccess returns the last time the Tree was accessed, in the form of a unix
+// timestamp.
+func (t *Tree) LastAccess() int64 {
+	return atomic.LoadInt64(&t.lastAccessUnix)
+}
+
+func (t *Tree) updateAccessTime() {
+	atomic.StoreInt64(&t.lastAccessUnix, time.Now().Unix())
+}
+
+// Publish makes a merkle tree available for queries.
+// Application layer should check IsPublish() before considering the Tree available.
+func (t *Tree) Publish() {
+	atomic.StoreUint32(&t.public, 1)
+}
+
+// UnPublish makes a merkle tree not available for queries
+func (t *Tree) UnPublish() {
+	atomic.StoreUint32(&t.public, 0)
+}
+
+// IsPublic returns true if the tree is available
+func (t *Tree) IsPublic() bool {
+	return atomic.LoadUint32(&t.public) == 1
+}
+
+// rootOrCurrent returns root, or the current root if it is empty
+func (t *Tree) rootOrCurrent(root []byte) []byte {
+	if len(root) > 0 {
+		return root
+	}
+	return t.Root()
+}
+
+// write applies op to the current root and stores the resulting tree
+func (t *Tree) write(op func(tx *tx, root []byte) ([]byte, error)) error {
+	if t.readOnly {
+		return fmt.Errorf("cannot modify a tree snapshot")
+	}
+	t.updateAccessTime()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tx := newTx(t.store)
+	root, err := op(tx, t.root)
+	if err != nil {
+		return err
+	}
+	if err := tx.commit(root); err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// Add adds a new claim to the merkle tree. The index is mandatory and gives the
+// position of the claim, the value is optional.
+func (t *Tree) Add(index, value []byte) error {
+	leaf, err := newLeaf(index, value)
+	if err != nil {
+		return err
+	}
+	return t.write(func(tx *tx, root []byte) ([]byte, error) {
+		return tx.insert(root, leaf)
+	})
+}
+
+// Delete removes a claim from the merkle tree
+func (t *Tree) Delete(index []byte) error {
+	key, err := elem(index)
+	if err != nil {
+		return fmt.Errorf("invalid claim index: %w", err)
+	}
+	return t.write(func(tx *tx, root []byte) ([]byte, error) {
+		return tx.remove(root, key)
+	})
+}
+
+// Update replaces the value of an existing claim of the merkle tree
+func (t *Tree) Update(index, value []byte) error {
+	leaf, err := newLeaf(index, value)
+	if err != nil {
+		return err
+	}
+	return t.write(func(tx *tx, root []byte) ([]byte, error) {
+		// the path of the claim does not change, so only its leaf is replaced
+		siblings, old, err := tx.walk(root, leaf.key())
+		if err != nil {
+			return nil, err
+		}
+		if old == nil || old.key().Cmp(leaf.key()) != 0 {
+			return nil, fmt.Errorf("claim not found")
+		}
+		hash, err := tx.put(leaf)
+		if err != nil {
+			return nil, err
+		}
+		return tx.up(hash, leaf.key(), siblings)
+	})
+}
+
+// GenProof generates a merkle tree proof that can be later used on CheckProof() to validate it.
+// The proof is made of Levels siblings, see CircuitInputs. If value is not empty it must match
+// the claim value. If the claim does not exist, the proof is nil.
+func (t *Tree) GenProof(index, value []byte) ([]byte, error) {
+	t.updateAccessTime()
+	key, err := elem(index)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim index: %w", err)
+	}
+	siblings, leaf, err := newTx(t.store).walk(t.Root(), key)
+	if err != nil {
+		return nil, err
+	}
+	if leaf == nil || leaf.key().Cmp(key) != 0 {
+		return nil, nil
+	}
+	if len(value) > 0 {
+		v, err := valueElem(value)
+		stored, _ := valueElem(leaf.value)
+		if err != nil || v.Cmp(stored) != 0 {
+			return nil, fmt.Errorf("claim value does not match")
+		}
+	}
+	proof := make([]byte, Levels*HashSize)
+	for i, s := range siblings {
+		copy(proof[i*HashSize:], s)
+	}
+	return proof, nil
+}
+
+// CheckProof validates a merkle proof and its data
+func (t *Tree) CheckProof(index, value, root, mproof []byte) (bool, error) {
+	t.updateAccessTime()
+	return CheckProof(index, value, t.rootOrCurrent(root), mproof)
+}
+
+// Root returns the current root hash of the merkle tree
+func (t *Tree) Root() []byte {
+	t.updateAccessTime()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return append([]byte{}, t.root...)
+}
+
+// Dump returns the whole merkle tree serialized in a format that can be used on Import.
+func (t *Tree) Dump(root []byte) ([]byte, error) {
+	t.updateAccessTime()
+	dump := exportData{}
+	if err := newTx(t.store).iterate(t.rootOrCurrent(root), func(index, value []byte) error {
+		dump.Elements = append(dump.Elements, exportElement{Key: index, Value: value})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return bare.Marshal(&dump)
+}
+
+// DumpChunks dumps the merkle tree in chunks of at most chunkSize claims, so
+// the whole dump is never held in memory
+func (t *Tree) DumpChunks(root []byte, chunkSize int, send func(chunk []byte) error) error {
+	if chunkSize < 1 {
+		return fmt.Errorf("chunk size must be positive")
+	}
+	t.updateAccessTime()
+	dump := exportData{}
+	flush := func() error {
+		chunk, err := bare.Marshal(&dump)
+		if err != nil {
+			return err
+		}
+		dump.Elements = dump.Elements[:0]
+		return send(chunk)
+	}
+	if err := newTx(t.store).iterate(t.rootOrCurrent(root), func(index, value []byte) error {
+		dump.Elements = append(dump.Elements, exportElement{Key: index, Value: value})
+		if len(dump.Elements) >= chunkSize {
+			return flush()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(dump.Elements) == 0 {
+		return nil
+	}
+	return flush()
+}
+
+// DumpPlain returns the entire list of added claims for a specific root hash
+// First return parametre are the indexes and second the values
+// If root is not specified, the current one is used
+func (t *Tree) DumpPlain(root []byte) ([][]byte, [][]byte, error) {
+	t.updateAccessTime()
+	var indexes, values [][]byte
+	err := newTx(t.store).iterate(t.rootOrCurrent(root), func(index, value []byte) error {
+		indexes = append(indexes, index)
+		values = append(values, value)
+		return nil
+	})
+	return indexes, values, err
+}
+
+// Iterate calls callback with the claims of root until it returns true. The
+// path of a claim is its index with the bits of each byte reversed, so paths
+// sort as the leaves, whose position is given by the index bits starting
+// from the least significant one.
+func (t *Tree) Iterate(root []byte, callback func(path, index, value []byte) bool) error {
+	t.updateAccessTime()
+	err := newTx(t.store).iterate(t.rootOrCurrent(root), func(index, value []byte) error {
+		k, err := elem(index)
+		if err != nil {
+			return err
+		}
+		path := elemBytes(k)
+		for i := range path {
+			path[i] = bits.Reverse8(path[i])
+		}
+		if callback(path, index, value) {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err == errStopIteration {
+		return nil
+	}
+	return err
+}
+
+// ImportDump imports a partial or whole tree previously exported with Dump()
+func (t *Tree) ImportDump(data []byte) error {
+	census := new(exportData)
+	if err := bare.Unmarshal(data, census); err != nil {
+		return fmt.Errorf("importdump cannot unmarshal data: %w", err)
+	}
+	return t.write(func(tx *tx, root []byte) ([]byte, error) {
+		for _, ee := range census.Elements {
+			leaf, err := newLeaf(ee.Key, ee.Value)
+			if err != nil {
+				return nil, err
+			}
+			if root, err = tx.insert(root, leaf); err != nil {
+				return nil, err
+			}
+		}
+		return root, nil
+	})
+}
+
+// Size returns the number of leaf nodes on the merkle tree
+func (t *Tree) Size(root []byte) (int64, error) {
+	t.updateAccessTime()
+	var size int64
+	err := newTx(t.store).iterate(t.rootOrCurrent(root), func(index, value []byte) error {
+		size++
+		return nil
+	})
+	return size, err
+}
+
+// Snapshot returns a Tree instance of a exiting merkle root
+func (t *Tree) Snapshot(root []byte) (censustree.Tree, error) {
+	root = t.rootOrCurrent(root)
+	if ok, err := t.HashExists(root); err != nil || !ok {
+		return nil, fmt.Errorf("snapshot: root not valid or not found %x", root)
+	}
+	return &Tree{store: t.store, root: root, readOnly: true, public: atomic.LoadUint32(&t.public)}, nil
+}
+
+// HashExists checks if a hash exists as a node in the merkle tree
+func (t *Tree) HashExists(hash []byte) (bool, error) {
+	t.updateAccessTime()
+	if isEmpty(hash) {
+		// the empty tree is the root before adding any claim
+		return true, nil
+	}
+	return t.store.Has(append(append([]byte{}, nodePrefix...), hash...))
+}
+
+// Close closes the storage of the tree. Snapshots share the storage of their
+// tree, so closing them does nothing.
+func (t *Tree) Close() error {
+	if t.readOnly {
+		return nil
+	}
+	return t.store.Close()
+}
+
+// Destroy closes the tree and removes its storage directory
+func (t *Tree) Destroy() error {
+	if err := t.store.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(t.dataDir)
+}
@@ -0,0 +1,231 @@
+package poseidontree
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	censusSize := 200
+	storage := t.TempDir()
+	tr1, err := NewTree("test1", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < censusSize; i++ {
+		if err := tr1.Add([]byte(fmt.Sprintf("%05d", i)), []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tr1.Add([]byte("00001"), nil); err == nil {
+		t.Errorf("duplicated claim was added")
+	}
+	root1 := tr1.Root()
+	for i := 0; i < censusSize; i++ {
+		key, value := []byte(fmt.Sprintf("%05d", i)), []byte{byte(i)}
+		proof, err := tr1.GenProof(key, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proof) != Levels*HashSize {
+			t.Fatalf("proof size is %d, expected %d", len(proof), Levels*HashSize)
+		}
+		if valid, err := CheckProof(key, value, root1, proof); err != nil || !valid {
+			t.Fatalf("proof %d is not valid (%v)", i, err)
+		}
+		if valid, _ := CheckProof(key, []byte{byte(i + 1)}, root1, proof); valid {
+			t.Fatalf("proof %d is valid for another value", i)
+		}
+	}
+	if proof, err := tr1.GenProof([]byte("01000"), nil); err != nil || proof != nil {
+		t.Errorf("proof generated for a claim not in the tree")
+	}
+
+	data, err := tr1.Dump(root1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := NewTree("test2", storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.ImportDump(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tr2.Root(), root1) {
+		t.Errorf("roots are different after importing the dump")
+	}
+	if size, err := tr2.Size(nil); err != nil || size != int64(censusSize) {
+		t.Errorf("size is %d, expected %d (%v)", size, censusSize, err)
+	}
+}
+
+func TestDeleteUpdate(t *testing.T) {
+	tr, err := NewTree("test1", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots [][]byte
+	for i := 0; i < 50; i++ {
+		roots = append(roots, tr.Root())
+		if err := tr.Add([]byte(fmt.Sprintf("%05d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// deleting the claims in reverse order must go back through the same roots
+	for i := 49; i >= 0; i-- {
+		if err := tr.Delete([]byte(fmt.Sprintf("%05d", i))); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(tr.Root(), roots[i]) {
+			t.Fatalf("root after deleting claim %d is not the root before adding it", i)
+		}
+	}
+	if err := tr.Delete([]byte("00001")); err == nil {
+		t.Errorf("deleted a claim not in the tree")
+	}
+
+	if err := tr.Add([]byte("00001"), []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Update([]byte("00001"), []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.GenProof([]byte("00001"), []byte{1}); err == nil {
+		t.Errorf("proof generated for the old value")
+	}
+	proof, err := tr.GenProof([]byte("00001"), []byte{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid, err := tr.CheckProof([]byte("00001"), []byte{2}, nil, proof); err != nil || !valid {
+		t.Errorf("proof for the new value is not valid (%v)", err)
+	}
+}
+
+func TestSnapshotCircuitInputs(t *testing.T) {
+	tr, err := NewTree("test1", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := tr.Add([]byte(fmt.Sprintf("%05d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := tr.Root()
+	if err := tr.Add([]byte("00010"), nil); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := tr.Snapshot(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshot.Add([]byte("00011"), nil); err == nil {
+		t.Errorf("claim added to a snapshot")
+	}
+	proof, err := snapshot.GenProof([]byte("00001"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs, err := NewCircuitInputs([]byte("00001"), nil, root, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs.Siblings) != Levels {
+		t.Errorf("circuit inputs have %d siblings, expected %d", len(inputs.Siblings), Levels)
+	}
+	if inputs.Value != "0" || inputs.Fnc != "0" {
+		t.Errorf("unexpected circuit inputs %+v", inputs)
+	}
+	if _, err := tr.Snapshot(make([]byte, HashSize-1)); err == nil {
+		t.Errorf("snapshot of an unknown root")
+	}
+}
+
+func TestIterate(t *testing.T) {
+	tr, err := NewTree("test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := tr.Add([]byte(fmt.Sprintf("%05d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var last []byte
+	count := 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		if last != nil && bytes.Compare(last, path) >= 0 {
+			t.Errorf("path %x is not after %x", path, last)
+		}
+		last = path
+		count++
+		return false
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 100 {
+		t.Errorf("iterated %d claims, expected 100", count)
+	}
+	count = 0
+	if err := tr.Iterate(tr.Root(), func(path, key, value []byte) bool {
+		count++
+		return count == 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("iteration did not stop, %d claims iterated", count)
+	}
+}
+
+// TestKnownRoots adds the claims of the TestNewTree and TestAddDifferentOrder
+// tests of github.com/iden3/go-merkletree-sql, which check them against
+// circomlib's smt.js. The roots were computed with go-merkletree-sql v2.0.4
+// and the Poseidon hash of go-iden3-crypto v0.0.4, used by snarks.PoseidonHash.
+func TestKnownRoots(t *testing.T) {
+	tr, err := NewTree("test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		key, value int64
+		root       string
+	}{
+		{1, 2, "6165644855941076673041248742966392747145703472271817858924440277515375361672"},
+		{33, 44, "9468820226836315426546143814342674693448162744181325354826267008104744584856"},
+		{1234, 9876, "1055201293719915373920551922039557430815240401541821133739830587712408852724"},
+	} {
+		// indexes are little endian and values big endian
+		if err := tr.Add(elemBytes(big.NewInt(c.key)), big.NewInt(c.value).Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		root, err := elem(tr.Root())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root.String() != c.root {
+			t.Errorf("root after adding %d is %s, expected %s", c.key, root, c.root)
+		}
+	}
+
+	tr, err = NewTree("test16", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 15; i >= 0; i-- {
+		if err := tr.Add(elemBytes(big.NewInt(int64(i))), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := elem(tr.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "17860455614109800370085434881255702884691765476371428793854615916949614582385"; root.String() != expected {
+		t.Errorf("root of 16 claims is %s, expected %s", root, expected)
+	}
+}
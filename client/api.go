@@ -180,22 +180,45 @@ func (c *Client) TestResults(pid []byte, totalVotes int) ([][]string, error) {
 	return results, nil
 }
 
+// proofBatchSize is the number of keys requested on each genProofBatch call
+const proofBatchSize = 500
+
 func (c *Client) GetProofBatch(signers []*ethereum.SignKeys, root []byte, tolerateError bool) ([][]byte, error) {
 	var proofs [][]byte
 	// Generate merkle proofs
 	log.Infof("generating proofs...")
-	for i, s := range signers {
-		proof, err := c.GetProof(s.PublicKey(), root)
+	for start := 0; start < len(signers); start += proofBatchSize {
+		end := start + proofBatchSize
+		if end > len(signers) {
+			end = len(signers)
+		}
+		var req types.MetaRequest
+		req.Method = "genProofBatch"
+		req.CensusID = hex.EncodeToString(root)
+		req.Digested = true
+		for _, s := range signers[start:end] {
+			req.CensusKeys = append(req.CensusKeys, snarks.Poseidon.Hash(s.PublicKey()))
+		}
+		resp, err := c.Request(req, nil)
+		if err == nil && (!resp.Ok || len(resp.Proofs) != end-start) {
+			err = fmt.Errorf("cannot get merkle proofs: (%s)", resp.Message)
+		}
 		if err != nil {
-			if tolerateError {
-				continue
+			if !tolerateError {
+				return proofs, err
+			}
+			// fall back to one request per key to skip only the failing ones
+			for _, s := range signers[start:end] {
+				if proof, err := c.GetProof(s.PublicKey(), root); err == nil {
+					proofs = append(proofs, proof)
+				}
 			}
-			return proofs, err
+			continue
 		}
-		proofs = append(proofs, proof)
-		if (i+1)%100 == 0 {
-			log.Infof("proof generation progress for %s: %d%%", c.Addr, ((i+1)*100)/(len(signers)))
+		for _, proof := range resp.Proofs {
+			proofs = append(proofs, proof)
 		}
+		log.Infof("proof generation progress for %s: %d%%", c.Addr, (end*100)/len(signers))
 	}
 	return proofs, nil
 }
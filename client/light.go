@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/statedb/iavlstate"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/vochain"
+	models "go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// headerRetries is the number of times the light client asks for a
+	// block header which is not yet available
+	headerRetries = 6
+	// headerRetryWait is the time to wait between header retries
+	headerRetryWait = 5 * time.Second
+)
+
+// LightClient is an API client which does not trust the gateways it connects to.
+// Every process or envelope returned by a gateway is checked against a merkle
+// proof of the Vochain state, and the state root is checked against a block
+// header signed by the genesis validators. If a gateway fails or returns data
+// that cannot be verified, the next gateway of the list is used.
+type LightClient struct {
+	gateways   []string
+	current    int
+	client     *Client
+	chainID    string
+	validators *tmtypes.ValidatorSet
+}
+
+// NewLightClient creates a light client for the given list of gateway addresses.
+// The genesis document (JSON) provides the chain ID and the trusted validator set.
+func NewLightClient(gateways []string, genesis []byte) (*LightClient, error) {
+	if len(gateways) == 0 {
+		return nil, fmt.Errorf("no gateways provided")
+	}
+	gen, err := tmtypes.GenesisDocFromJSON(genesis)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse genesis: %w", err)
+	}
+	vals := make([]*tmtypes.Validator, len(gen.Validators))
+	for i, v := range gen.Validators {
+		vals[i] = tmtypes.NewValidator(v.PubKey, v.Power)
+	}
+	lc := &LightClient{
+		gateways:   gateways,
+		chainID:    gen.ChainID,
+		validators: tmtypes.NewValidatorSet(vals),
+	}
+	if err := lc.validators.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid genesis validator set: %w", err)
+	}
+	return lc, nil
+}
+
+// Close closes the connection with the current gateway
+func (lc *LightClient) Close() error {
+	if lc.client == nil {
+		return nil
+	}
+	err := lc.client.Close()
+	lc.client = nil
+	return err
+}
+
+// GetProcess returns the process identified by pid, verified against the
+// signed Vochain state.
+func (lc *LightClient) GetProcess(pid []byte) (*models.Process, error) {
+	process := new(models.Process)
+	err := lc.verifiedRequest(types.MetaRequest{
+		Method:    "getProcessProof",
+		ProcessID: pid,
+	}, vochain.ProcessTree, pid, process)
+	if err != nil {
+		return nil, err
+	}
+	return process, nil
+}
+
+// GetEnvelope returns the vote envelope identified by pid and nullifier,
+// verified against the signed Vochain state.
+func (lc *LightClient) GetEnvelope(pid, nullifier []byte) (*models.Vote, error) {
+	vote := new(models.Vote)
+	key := make([]byte, 0, len(pid)+len(nullifier))
+	key = append(key, pid...)
+	key = append(key, nullifier...)
+	err := lc.verifiedRequest(types.MetaRequest{
+		Method:    "getEnvelopeProof",
+		ProcessID: pid,
+		Nullifier: nullifier,
+	}, vochain.VoteTree, key, vote)
+	if err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+// verifiedRequest asks for a state proof, verifies it and decodes the proven
+// value into msg. Each gateway is tried once until one of them succeeds.
+func (lc *LightClient) verifiedRequest(req types.MetaRequest, tree string,
+	key []byte, msg proto.Message) error {
+	var errs []string
+	for i := 0; i < len(lc.gateways); i++ {
+		err := lc.tryVerifiedRequest(req, tree, key, msg)
+		if err == nil {
+			return nil
+		}
+		log.Warnf("light client: gateway %s failed: %v", lc.gateways[lc.current], err)
+		errs = append(errs, fmt.Sprintf("%s: %v", lc.gateways[lc.current], err))
+		lc.next()
+	}
+	return fmt.Errorf("%s: no gateway could provide a verified response: %s",
+		req.Method, strings.Join(errs, "; "))
+}
+
+func (lc *LightClient) tryVerifiedRequest(req types.MetaRequest, tree string,
+	key []byte, msg proto.Message) error {
+	c, err := lc.conn()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Request(req, nil)
+	if err != nil {
+		return err
+	}
+	if !resp.Ok {
+		return fmt.Errorf("%s", resp.Message)
+	}
+	sp := resp.StateProof
+	if sp == nil {
+		return fmt.Errorf("no state proof in response")
+	}
+	if sp.Tree != tree || !bytes.Equal(sp.Key, key) {
+		return fmt.Errorf("state proof is for a different key")
+	}
+	if err := lc.verifyStateProof(c, sp); err != nil {
+		return err
+	}
+	return proto.Unmarshal(sp.Value, msg)
+}
+
+// verifyStateProof checks the merkle proof against the tree root, the tree
+// roots against the AppHash and the block header against the validator set.
+func (lc *LightClient) verifyStateProof(c *Client, sp *types.StateProof) error {
+	roots := make(map[string][]byte, len(sp.Roots))
+	for name, root := range sp.Roots {
+		roots[name] = root
+	}
+	root, ok := roots[sp.Tree]
+	if !ok {
+		return fmt.Errorf("missing root for tree %s", sp.Tree)
+	}
+	if err := iavlstate.VerifyProof(sp.Key, sp.Value, sp.Proof, root); err != nil {
+		return fmt.Errorf("invalid merkle proof: %w", err)
+	}
+	// The AppHash resulting from block H is included in the header of block H+1
+	sh, err := lc.signedHeader(c, sp.Height+1)
+	if err != nil {
+		return err
+	}
+	if err := lc.verifySignedHeader(sh); err != nil {
+		return err
+	}
+	if !bytes.Equal(sh.AppHash, iavlstate.RootsHash(roots)) {
+		return fmt.Errorf("state roots do not match the AppHash of block %d", sh.Height)
+	}
+	return nil
+}
+
+// signedHeader fetches the signed header at height, waiting for the block to
+// be produced if needed.
+func (lc *LightClient) signedHeader(c *Client, height int64) (*tmtypes.SignedHeader, error) {
+	var resp *types.MetaResponse
+	var err error
+	for i := 0; i < headerRetries; i++ {
+		resp, err = c.Request(types.MetaRequest{Method: "getSignedHeader", Height: height}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Ok {
+			break
+		}
+		time.Sleep(headerRetryWait)
+	}
+	if !resp.Ok {
+		return nil, fmt.Errorf("cannot get signed header %d: %s", height, resp.Message)
+	}
+	pb := new(tmproto.SignedHeader)
+	if err := pb.Unmarshal(resp.SignedHeader); err != nil {
+		return nil, fmt.Errorf("cannot decode signed header: %w", err)
+	}
+	sh, err := tmtypes.SignedHeaderFromProto(pb)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode signed header: %w", err)
+	}
+	if sh.Height != height {
+		return nil, fmt.Errorf("got signed header for height %d, expected %d", sh.Height, height)
+	}
+	return sh, nil
+}
+
+// verifySignedHeader checks that the header has been signed by more than two
+// thirds of the trusted validator set.
+func (lc *LightClient) verifySignedHeader(sh *tmtypes.SignedHeader) error {
+	if err := sh.ValidateBasic(lc.chainID); err != nil {
+		return fmt.Errorf("invalid signed header: %w", err)
+	}
+	if !bytes.Equal(sh.ValidatorsHash, lc.validators.Hash()) {
+		return fmt.Errorf("validator set at height %d differs from the genesis one, cannot verify", sh.Height)
+	}
+	if err := lc.validators.VerifyCommitLight(lc.chainID, sh.Commit.BlockID, sh.Height, sh.Commit); err != nil {
+		return fmt.Errorf("invalid commit: %w", err)
+	}
+	return nil
+}
+
+func (lc *LightClient) conn() (*Client, error) {
+	if lc.client != nil {
+		return lc.client, nil
+	}
+	c, err := New(lc.gateways[lc.current])
+	if err != nil {
+		return nil, err
+	}
+	lc.client = c
+	return c, nil
+}
+
+// next switches to the next gateway of the list
+func (lc *LightClient) next() {
+	if lc.client != nil {
+		lc.client.Close()
+		lc.client = nil
+	}
+	lc.current = (lc.current + 1) % len(lc.gateways)
+}
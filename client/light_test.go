@@ -0,0 +1,249 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+	"go.vocdoni.io/dvote/statedb/iavlstate"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/vochain"
+	models "go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
+)
+
+const testChainID = "light-client-test"
+
+// testGateway is a fake gateway answering getProcessProof with proof and
+// getSignedHeader with header
+type testGateway struct {
+	proof  *types.StateProof
+	header []byte
+}
+
+func (g *testGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	for {
+		_, msg, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		var reqOuter types.RequestMessage
+		var req types.MetaRequest
+		if err := json.Unmarshal(msg, &reqOuter); err != nil {
+			return
+		}
+		if err := json.Unmarshal(reqOuter.MetaRequest, &req); err != nil {
+			return
+		}
+		resp := types.MetaResponse{Ok: true}
+		switch req.Method {
+		case "getProcessProof":
+			resp.StateProof = g.proof
+		case "getSignedHeader":
+			resp.SignedHeader = g.header
+		}
+		respInner, err := json.Marshal(resp)
+		if err != nil {
+			return
+		}
+		respOuter, err := json.Marshal(types.ResponseMessage{
+			ID:           reqOuter.ID,
+			MetaResponse: respInner,
+			Signature:    []byte{1},
+		})
+		if err != nil {
+			return
+		}
+		if err := conn.Write(r.Context(), websocket.MessageText, respOuter); err != nil {
+			return
+		}
+	}
+}
+
+func (g *testGateway) start(t *testing.T) string {
+	srv := httptest.NewServer(g)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// testChain holds a validator and a proof of a process in a Vochain state
+type testChain struct {
+	pv      tmtypes.PrivValidator
+	vals    *tmtypes.ValidatorSet
+	genesis []byte
+	pid     []byte
+	proof   *types.StateProof
+}
+
+func newTestChain(t *testing.T) *testChain {
+	pv := tmtypes.NewMockPV()
+	pubKey, err := pv.GetPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := tmjson.Marshal(&tmtypes.GenesisDoc{
+		ChainID:     testChainID,
+		GenesisTime: time.Now(),
+		Validators:  []tmtypes.GenesisValidator{{PubKey: pubKey, Power: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &iavlstate.IavlState{}
+	if err := s.Init(t.TempDir(), "mem"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddTree(vochain.ProcessTree); err != nil {
+		t.Fatal(err)
+	}
+	pid := tmhash.Sum([]byte("process"))
+	value, err := proto.Marshal(&models.Process{ProcessId: pid, EntityId: []byte("entity")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Tree(vochain.ProcessTree).Add(pid, value); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	tr := s.ImmutableTree(vochain.ProcessTree)
+	mproof, err := tr.Proof(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testChain{
+		pv:      pv,
+		vals:    tmtypes.NewValidatorSet([]*tmtypes.Validator{tmtypes.NewValidator(pubKey, 10)}),
+		genesis: genesis,
+		pid:     pid,
+		proof: &types.StateProof{
+			Height: 10,
+			Key:    pid,
+			Proof:  mproof,
+			Roots:  map[string]types.HexBytes{vochain.ProcessTree: tr.Hash()},
+			Tree:   vochain.ProcessTree,
+			Value:  value,
+		},
+	}
+}
+
+// signedHeader returns the encoded header at height with appHash, signed by pv
+// and claiming the validator set vals
+func (c *testChain) signedHeader(t *testing.T, height int64, appHash []byte,
+	pv tmtypes.PrivValidator, vals *tmtypes.ValidatorSet) []byte {
+	header := &tmtypes.Header{
+		Version:            tmversion.Consensus{Block: version.BlockProtocol},
+		ChainID:            testChainID,
+		Height:             height,
+		Time:               time.Now(),
+		AppHash:            appHash,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+		ProposerAddress:    vals.GetProposer().Address,
+	}
+	blockID := tmtypes.BlockID{
+		Hash:          header.Hash(),
+		PartSetHeader: tmtypes.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+	}
+	voteSet := tmtypes.NewVoteSet(testChainID, height, 0, tmproto.PrecommitType, vals)
+	commit, err := tmtypes.MakeCommit(blockID, height, 0, voteSet, []tmtypes.PrivValidator{pv}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := tmtypes.SignedHeader{Header: header, Commit: commit}
+	b, err := sh.ToProto().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (c *testChain) appHash() []byte {
+	roots := make(map[string][]byte, len(c.proof.Roots))
+	for name, root := range c.proof.Roots {
+		roots[name] = root
+	}
+	return iavlstate.RootsHash(roots)
+}
+
+func TestLightClient(t *testing.T) {
+	t.Parallel()
+
+	c := newTestChain(t)
+	height := c.proof.Height + 1
+	honest := &testGateway{proof: c.proof, header: c.signedHeader(t, height, c.appHash(), c.pv, c.vals)}
+
+	tamperedProof := *c.proof
+	tamperedProof.Value = append([]byte{}, c.proof.Value...)
+	tamperedProof.Value[len(tamperedProof.Value)-1] ^= 1
+	tampered := &testGateway{proof: &tamperedProof, header: honest.header}
+
+	// the header must commit the state roots at the proof height
+	badAppHash := &testGateway{proof: c.proof,
+		header: c.signedHeader(t, height, tmhash.Sum([]byte("other state")), c.pv, c.vals)}
+
+	// the header must be signed by the genesis validators
+	otherPV := tmtypes.NewMockPV()
+	otherKey, err := otherPV.GetPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherVals := tmtypes.NewValidatorSet([]*tmtypes.Validator{tmtypes.NewValidator(otherKey, 10)})
+	untrusted := &testGateway{proof: c.proof, header: c.signedHeader(t, height, c.appHash(), otherPV, otherVals)}
+
+	for _, tc := range []struct {
+		name     string
+		gateways []*testGateway
+		err      string // empty if the process must be accepted
+	}{
+		{"honest", []*testGateway{honest}, ""},
+		{"tampered proof", []*testGateway{tampered}, "invalid merkle proof"},
+		{"mismatched AppHash", []*testGateway{badAppHash}, "do not match the AppHash"},
+		{"untrusted validators", []*testGateway{untrusted}, "differs from the genesis one"},
+		{"failover", []*testGateway{tampered, badAppHash, honest}, ""},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var addrs []string
+			for _, g := range tc.gateways {
+				addrs = append(addrs, g.start(t))
+			}
+			lc, err := NewLightClient(addrs, c.genesis)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer lc.Close()
+			process, err := lc.GetProcess(c.pid)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(process.ProcessId) != string(c.pid) {
+				t.Fatalf("got process %x, expected %x", process.ProcessId, c.pid)
+			}
+		})
+	}
+}
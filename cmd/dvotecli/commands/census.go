@@ -0,0 +1,348 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"nhooyr.io/websocket"
+
+	"go.vocdoni.io/dvote/censustree"
+	"go.vocdoni.io/dvote/censustree/gravitontree"
+	"go.vocdoni.io/dvote/censustree/iden3tree"
+	"go.vocdoni.io/dvote/censustree/poseidontree"
+	"go.vocdoni.io/dvote/client"
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/crypto/snarks"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+)
+
+var censusCmd = &cobra.Command{
+	Use:   "census",
+	Short: "Census creation, import and publishing",
+}
+
+var censusCreateCmd = &cobra.Command{
+	Use:   "create <censusId>",
+	Short: "Create a new census namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE:  censusCreate,
+}
+
+var censusImportCmd = &cobra.Command{
+	Use:   "import <censusId> <file.csv>",
+	Short: "Add the claims of a CSV file to a census",
+	Long: `Add the claims of a CSV file to a census.
+Each line contains a hex public key or address and, optionally, its voting weight,
+which must be a positive integer.
+Public keys are hashed with Poseidon, as the Vochain does for off-chain censuses.
+Lines starting with # and a header line are ignored.`,
+	Args: cobra.ExactArgs(2),
+	RunE: censusImportCSV,
+}
+
+var censusPublishCmd = &cobra.Command{
+	Use:   "publish <censusId>",
+	Short: "Publish a census on the remote storage and print its root and URI",
+	Args:  cobra.ExactArgs(1),
+	RunE:  censusPublish,
+}
+
+func init() {
+	rootCmd.AddCommand(censusCmd)
+	censusCmd.AddCommand(censusCreateCmd)
+	censusCmd.AddCommand(censusImportCmd)
+	censusCmd.AddCommand(censusPublishCmd)
+	censusCreateCmd.Flags().StringSlice("pubKeys", nil, "public keys allowed to manage the census")
+	censusImportCmd.Flags().Int("chunk", 500, "number of claims of each bulk request")
+	censusImportCmd.Flags().String("progress", "",
+		"file where the census ID and the number of imported claims are saved (default <file.csv>.progress)")
+	censusImportCmd.Flags().Bool("resume", false, "skip the claims already imported according to the progress file")
+	censusImportCmd.Flags().Bool("publish", false, "publish the census after the import")
+	censusImportCmd.Flags().Bool("dryRun", false, "do not connect, only compute the census root locally")
+	censusImportCmd.Flags().String("tree", "graviton", "census tree implementation for dry runs <graviton, iden3, poseidon>")
+	censusPublishCmd.Flags().String("baseRoot", "", "publish only the changes since the census with this root")
+	censusPublishCmd.Flags().Bool("chunked", false, "publish the claims in chunks, for large censuses")
+}
+
+// censusClient connects to the host and returns the client and the signer
+func censusClient() (*client.Client, *ethereum.SignKeys, error) {
+	signer := ethereum.NewSignKeys()
+	if privKey != "" {
+		if err := signer.AddHexKey(privKey); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		signer.Generate()
+	}
+	cl, err := client.New(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to %s: %w", host, err)
+	}
+	return cl, signer, nil
+}
+
+// censusRequest sends a census request and checks its response
+func censusRequest(cl *client.Client, signer *ethereum.SignKeys, req types.MetaRequest) (*types.MetaResponse, error) {
+	resp, err := cl.Request(req, signer)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Ok {
+		return nil, fmt.Errorf("%s failed: %s", req.Method, resp.Message)
+	}
+	return resp, nil
+}
+
+func censusCreate(cmd *cobra.Command, args []string) error {
+	pubKeys, _ := cmd.Flags().GetStringSlice("pubKeys")
+	cl, signer, err := censusClient()
+	if err != nil {
+		return err
+	}
+	defer cl.Conn.Close(websocket.StatusNormalClosure, "")
+	resp, err := censusRequest(cl, signer, types.MetaRequest{
+		Method:   "addCensus",
+		CensusID: args[0],
+		PubKeys:  pubKeys,
+	})
+	if err != nil {
+		return err
+	}
+	prettyHeader("Census created")
+	fmt.Printf("Census ID: %s\n", au.Yellow(resp.CensusID))
+	return nil
+}
+
+// readCensusCSV returns the keys and values of the claims of a CSV file
+func readCensusCSV(r io.Reader) ([][]byte, [][]byte, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var keys, values [][]byte
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := hex.DecodeString(util.TrimHex(strings.TrimSpace(record[0])))
+		if err != nil && len(keys) == 0 {
+			continue // header
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid key: %w", line, err)
+		}
+		switch len(key) {
+		case ethereum.PubKeyLengthBytesUncompressed:
+			// the Vochain hashes the compressed public key of the voter
+			pub, err := ethereum.CompressPubKey(hex.EncodeToString(key))
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: invalid public key: %w", line, err)
+			}
+			key, _ = hex.DecodeString(pub)
+			key = snarks.Poseidon.Hash(key)
+		case ethereum.PubKeyLengthBytes:
+			key = snarks.Poseidon.Hash(key)
+		case ethcommon.AddressLength:
+		default:
+			return nil, nil, fmt.Errorf("line %d: key is not a public key or an address", line)
+		}
+		var value []byte
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			weight, ok := new(big.Int).SetString(strings.TrimSpace(record[1]), 10)
+			if !ok || weight.Sign() <= 0 {
+				return nil, nil, fmt.Errorf("line %d: invalid weight %q", line, record[1])
+			}
+			value = weight.Bytes()
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+func censusImportCSV(cmd *cobra.Command, args []string) error {
+	censusID, file := args[0], args[1]
+	chunk, _ := cmd.Flags().GetInt("chunk")
+	progressFile, _ := cmd.Flags().GetString("progress")
+	resume, _ := cmd.Flags().GetBool("resume")
+	publish, _ := cmd.Flags().GetBool("publish")
+	dryRun, _ := cmd.Flags().GetBool("dryRun")
+	if chunk < 1 {
+		return fmt.Errorf("chunk must be positive")
+	}
+	if progressFile == "" {
+		progressFile = file + ".progress"
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	keys, values, err := readCensusCSV(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", file, err)
+	}
+	fmt.Printf("read %d claims from %s\n", len(keys), file)
+
+	if dryRun {
+		treeType, _ := cmd.Flags().GetString("tree")
+		return censusDryRun(treeType, censusID, keys, values)
+	}
+
+	done := 0
+	if resume {
+		if data, err := ioutil.ReadFile(progressFile); err == nil {
+			// the progress file holds the census ID and the number of claims
+			fields := strings.Fields(string(data))
+			if len(fields) != 2 {
+				return fmt.Errorf("invalid progress file %s", progressFile)
+			}
+			if fields[0] != censusID {
+				return fmt.Errorf("progress file %s belongs to census %s", progressFile, fields[0])
+			}
+			if done, err = strconv.Atoi(fields[1]); err != nil {
+				return fmt.Errorf("invalid progress file %s: %w", progressFile, err)
+			}
+			fmt.Printf("resuming after %d claims\n", done)
+		}
+	}
+
+	cl, signer, err := censusClient()
+	if err != nil {
+		return err
+	}
+	defer cl.Conn.Close(websocket.StatusNormalClosure, "")
+
+	var resp *types.MetaResponse
+	invalid := 0
+	for done < len(keys) {
+		end := done + chunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		req := types.MetaRequest{
+			Method:     "addClaimBulk",
+			CensusID:   censusID,
+			CensusKeys: keys[done:end],
+			Digested:   true,
+		}
+		for _, v := range values[done:end] {
+			req.CensusValues = append(req.CensusValues, v)
+		}
+		if resp, err = censusRequest(cl, signer, req); err != nil {
+			return fmt.Errorf("import stopped after %d claims, use --resume to continue: %w", done, err)
+		}
+		for _, i := range resp.InvalidClaims {
+			fmt.Println(au.Red(fmt.Sprintf("claim %x was not added", keys[done+i])))
+		}
+		invalid += len(resp.InvalidClaims)
+		done = end
+		if err := ioutil.WriteFile(progressFile, []byte(fmt.Sprintf("%s %d", censusID, done)), 0o644); err != nil {
+			return fmt.Errorf("cannot save the progress: %w", err)
+		}
+		fmt.Printf("imported %d/%d claims (%d%%)\n", done, len(keys), done*100/len(keys))
+	}
+	if err := os.Remove(progressFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot remove the progress file: %w", err)
+	}
+
+	prettyHeader("Census imported")
+	if resp != nil {
+		fmt.Printf("Root: %x\n", au.Yellow(resp.Root))
+	}
+	fmt.Printf("Invalid claims: %d\n", au.Yellow(invalid))
+	if !publish {
+		return nil
+	}
+	return publishCensus(cl, signer, censusID, nil, false)
+}
+
+// censusDryRun adds the claims to a temporary local tree and prints its root
+func censusDryRun(treeType, censusID string, keys, values [][]byte) error {
+	var newTree func(name, storageDir string) (censustree.Tree, error)
+	switch treeType {
+	case "graviton":
+		newTree = gravitontree.NewTree
+	case "iden3":
+		newTree = iden3tree.NewTree
+	case "poseidon":
+		newTree = poseidontree.NewTree
+	default:
+		return fmt.Errorf("unknown census tree %q", treeType)
+	}
+	dir, err := ioutil.TempDir("", "dvotecli-census")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	tr, err := newTree(strings.ReplaceAll(censusID, "/", "_"), dir)
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	for i, k := range keys {
+		if err := tr.Add(k, values[i]); err != nil {
+			fmt.Println(au.Red(fmt.Sprintf("claim %x cannot be added: %v", k, err)))
+			invalid++
+		}
+		if (i+1)%10000 == 0 {
+			fmt.Printf("added %d/%d claims\n", i+1, len(keys))
+		}
+	}
+	prettyHeader("Census dry run")
+	fmt.Printf("Root: %x\n", au.Yellow(tr.Root()))
+	fmt.Printf("Invalid claims: %d\n", au.Yellow(invalid))
+	return nil
+}
+
+func censusPublish(cmd *cobra.Command, args []string) error {
+	baseRoot, _ := cmd.Flags().GetString("baseRoot")
+	chunked, _ := cmd.Flags().GetBool("chunked")
+	var base []byte
+	if baseRoot != "" {
+		var err error
+		if base, err = hex.DecodeString(util.TrimHex(baseRoot)); err != nil {
+			return fmt.Errorf("invalid base root: %w", err)
+		}
+	}
+	cl, signer, err := censusClient()
+	if err != nil {
+		return err
+	}
+	defer cl.Conn.Close(websocket.StatusNormalClosure, "")
+	return publishCensus(cl, signer, args[0], base, chunked)
+}
+
+// publishCensus publishes a census, as a delta of baseRoot if it is not nil,
+// or in chunks if chunked is set
+func publishCensus(cl *client.Client, signer *ethereum.SignKeys, censusID string, baseRoot []byte,
+	chunked bool) error {
+	resp, err := censusRequest(cl, signer, types.MetaRequest{
+		Method:   "publish",
+		CensusID: censusID,
+		BaseRoot: baseRoot,
+		Chunked:  chunked,
+	})
+	if err != nil {
+		return err
+	}
+	prettyHeader("Census published")
+	fmt.Printf("Root: %x\n", au.Yellow(resp.Root))
+	fmt.Printf("URI: %s\n", au.Yellow(resp.URI))
+	return nil
+}
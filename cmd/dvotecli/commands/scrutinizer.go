@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.vocdoni.io/dvote/vochain"
+	"go.vocdoni.io/dvote/vochain/scrutinizer"
+)
+
+var scrutinizerCmd = &cobra.Command{
+	Use:   "scrutinizer",
+	Short: "Local scrutinizer database maintenance (the node must be stopped)",
+}
+
+var scrutinizerReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Wipe and rebuild the scrutinizer database from the Vochain state",
+	RunE:  scrutinizerReindex,
+}
+
+func init() {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	rootCmd.AddCommand(scrutinizerCmd)
+	scrutinizerCmd.AddCommand(scrutinizerReindexCmd)
+	scrutinizerReindexCmd.Flags().String("vochainDir", home+"/.dvote/dev/vochain",
+		"vochain data directory of the node")
+	scrutinizerReindexCmd.Flags().Bool("verify", false,
+		"do not write anything, only report the differences found")
+}
+
+func scrutinizerReindex(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("vochainDir")
+	verify, _ := cmd.Flags().GetBool("verify")
+
+	state, err := vochain.NewStateLastVersion(dir + "/data")
+	if err != nil {
+		return fmt.Errorf("cannot open vochain state: %w", err)
+	}
+	defer state.Store.Close()
+	sc, err := scrutinizer.NewScrutinizer(dir+"/scrutinizer", state)
+	if err != nil {
+		return fmt.Errorf("cannot open scrutinizer: %w", err)
+	}
+	defer sc.Storage.Close()
+
+	report, err := sc.Reindex(verify)
+	if err != nil {
+		return err
+	}
+	prettyHeader("Scrutinizer")
+	fmt.Printf("Processes: %d\n", au.Yellow(report.Processes))
+	fmt.Printf("Entities: %d\n", au.Yellow(report.Entities))
+	fmt.Printf("Live results: %d\n", au.Yellow(report.LiveResults))
+	fmt.Printf("Results: %d\n", au.Yellow(report.Results))
+	if !verify {
+		fmt.Println(au.Green("database rebuilt"))
+		return nil
+	}
+	for _, d := range report.Differences {
+		fmt.Println(au.Red(d))
+	}
+	if len(report.Differences) > 0 {
+		return fmt.Errorf("found %d differences", len(report.Differences))
+	}
+	fmt.Println(au.Green("no differences found"))
+	return nil
+}
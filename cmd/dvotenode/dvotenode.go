@@ -26,6 +26,7 @@ import (
 	"go.vocdoni.io/dvote/internal"
 	"go.vocdoni.io/dvote/log"
 	"go.vocdoni.io/dvote/metrics"
+	"go.vocdoni.io/dvote/router"
 	"go.vocdoni.io/dvote/service"
 	"go.vocdoni.io/dvote/types"
 	"go.vocdoni.io/dvote/vochain"
@@ -66,8 +67,25 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	// api
 	globalCfg.API.Websockets = *flag.Bool("apiws", true, "enable websockets transport for the API")
 	globalCfg.API.HTTP = *flag.Bool("apihttp", true, "enable http transport for the API")
+	globalCfg.API.REST = *flag.Bool("apiRest", false, "enable the REST API facade for the public methods")
+	globalCfg.API.Protobuf = *flag.Bool("apiProtobuf", false, "enable the length-prefixed protobuf transport for the API")
+	globalCfg.API.RateLimit = *flag.Float64("apiRateLimit", 0, "requests per second allowed to each client IP and address, 0 to disable")
+	globalCfg.API.RateLimitBurst = *flag.Int("apiRateLimitBurst", 20, "maximum burst of requests allowed to each client")
+	globalCfg.API.RateLimitMethods = *flag.String("apiRateLimitMethods", "",
+		"per method rate limits as method:rate:burst items separated by commas")
+	globalCfg.API.Workers = *flag.Int("apiWorkers", router.DefaultWorkers, "number of API requests handled concurrently")
+	globalCfg.API.QueueSize = *flag.Int("apiQueueSize", router.DefaultQueueSize,
+		"maximum number of API requests waiting for a worker")
+	globalCfg.API.RequestTimeout = *flag.Int("apiRequestTimeout", int(router.DefaultRequestTimeout.Seconds()),
+		"seconds an API request may wait and run before timing out")
 	globalCfg.API.File = *flag.Bool("fileApi", true, "enable the file API")
 	globalCfg.API.Census = *flag.Bool("censusApi", true, "enable the census API")
+	globalCfg.API.CensusTree = *flag.String("censusTree", "graviton",
+		"census tree implementation <graviton, iden3, poseidon>, existing census are not converted")
+	globalCfg.API.CensusIdleTimeout = *flag.Int("censusIdleTimeout", 30,
+		"minutes a census tree can be unused before being closed, 0 disables it")
+	globalCfg.API.CensusMaxLoaded = *flag.Int("censusMaxLoaded", 0,
+		"maximum number of open census trees, the least recently used are closed first (0 means no limit)")
 	globalCfg.API.Vote = *flag.Bool("voteApi", true, "enable the vote API")
 	globalCfg.API.Tendermint = *flag.Bool("tendermintApi", false, "make the Tendermint API public available")
 	globalCfg.API.Results = *flag.Bool("resultsApi", true, "enable the results API")
@@ -76,7 +94,7 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	globalCfg.API.AllowedAddrs = *flag.String("apiAllowedAddrs", "", "comma delimited list of allowed client ETH addresses for private methods")
 	globalCfg.API.ListenHost = *flag.String("listenHost", "0.0.0.0", "API endpoint listen address")
 	globalCfg.API.ListenPort = *flag.Int("listenPort", 9090, "API endpoint http port")
-	globalCfg.API.WebsocketsReadLimit = *flag.Int64("apiWsReadLimit", types.Web3WsReadLimit, "dvote websocket API read size limit in bytes")
+	globalCfg.API.WebsocketsReadLimit = *flag.Int64("apiWsReadLimit", types.Web3WsReadLimit, "dvote API read size limit in bytes, for websocket messages and HTTP request bodies")
 	// ssl
 	globalCfg.API.Ssl.Domain = *flag.String("sslDomain", "", "enable TLS secure domain with LetsEncrypt auto-generated certificate (listenPort=443 is required)")
 	// ethereum node
@@ -118,6 +136,13 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	globalCfg.VochainConfig.MempoolSize = *flag.Int("vochainMempoolSize", 20000, "vochain mempool size")
 	globalCfg.VochainConfig.KeyKeeperIndex = *flag.Int8("keyKeeperIndex", 0, "if this node is a key keeper, use this index slot")
 	globalCfg.VochainConfig.ImportPreviousCensus = *flag.Bool("importPreviousCensus", false, "if enabled the census downloader will import all existing census")
+	globalCfg.VochainConfig.CensusGCInterval = *flag.Int("censusGCInterval", 0,
+		"minutes between two removals of the imported census not used by any ongoing process (0 disables it)")
+	globalCfg.VochainConfig.CensusGCGracePeriod = *flag.Int("censusGCGracePeriod", 24*60,
+		"minutes an imported census must be unused before its removal")
+	globalCfg.VochainConfig.CensusGCDryRun = *flag.Bool("censusGCDryRun", false,
+		"only log the census the garbage collector would remove")
+	globalCfg.VochainConfig.ResultsSnapshotInterval = *flag.Uint32("resultsSnapshotInterval", 1, "minimum number of blocks between two live results snapshots")
 	// metrics
 	globalCfg.Metrics.Enabled = *flag.Bool("metricsEnabled", false, "enable prometheus metrics")
 	globalCfg.Metrics.RefreshInterval = *flag.Int("metricsRefreshInterval", 5, "metrics refresh interval in seconds")
@@ -162,8 +187,19 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	viper.BindPFlag("api.Websockets", flag.Lookup("apiws"))
 	viper.BindPFlag("api.WebsocketsReadLimit", flag.Lookup("apiWsReadLimit"))
 	viper.BindPFlag("api.Http", flag.Lookup("apihttp"))
+	viper.BindPFlag("api.REST", flag.Lookup("apiRest"))
+	viper.BindPFlag("api.Protobuf", flag.Lookup("apiProtobuf"))
+	viper.BindPFlag("api.RateLimit", flag.Lookup("apiRateLimit"))
+	viper.BindPFlag("api.RateLimitBurst", flag.Lookup("apiRateLimitBurst"))
+	viper.BindPFlag("api.RateLimitMethods", flag.Lookup("apiRateLimitMethods"))
+	viper.BindPFlag("api.Workers", flag.Lookup("apiWorkers"))
+	viper.BindPFlag("api.QueueSize", flag.Lookup("apiQueueSize"))
+	viper.BindPFlag("api.RequestTimeout", flag.Lookup("apiRequestTimeout"))
 	viper.BindPFlag("api.File", flag.Lookup("fileApi"))
 	viper.BindPFlag("api.Census", flag.Lookup("censusApi"))
+	viper.BindPFlag("api.CensusTree", flag.Lookup("censusTree"))
+	viper.BindPFlag("api.CensusIdleTimeout", flag.Lookup("censusIdleTimeout"))
+	viper.BindPFlag("api.CensusMaxLoaded", flag.Lookup("censusMaxLoaded"))
 	viper.BindPFlag("api.Vote", flag.Lookup("voteApi"))
 	viper.BindPFlag("api.Results", flag.Lookup("resultsApi"))
 	viper.BindPFlag("api.Tendermint", flag.Lookup("tendermintApi"))
@@ -173,6 +209,7 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	viper.BindPFlag("api.ListenHost", flag.Lookup("listenHost"))
 	viper.BindPFlag("api.ListenPort", flag.Lookup("listenPort"))
 	viper.Set("api.Ssl.DirCert", globalCfg.DataDir+"/tls")
+	viper.SetDefault("api.AuditLog", globalCfg.DataDir+"/audit.log")
 	viper.BindPFlag("api.Ssl.Domain", flag.Lookup("sslDomain"))
 
 	// ethereum node
@@ -220,6 +257,10 @@ func newConfig() (*config.DvoteCfg, config.Error) {
 	viper.BindPFlag("vochainConfig.MempoolSize", flag.Lookup("vochainMempoolSize"))
 	viper.BindPFlag("vochainConfig.KeyKeeperIndex", flag.Lookup("keyKeeperIndex"))
 	viper.BindPFlag("vochainConfig.ImportPreviousCensus", flag.Lookup("importPreviousCensus"))
+	viper.BindPFlag("vochainConfig.ResultsSnapshotInterval", flag.Lookup("resultsSnapshotInterval"))
+	viper.BindPFlag("vochainConfig.CensusGCInterval", flag.Lookup("censusGCInterval"))
+	viper.BindPFlag("vochainConfig.CensusGCGracePeriod", flag.Lookup("censusGCGracePeriod"))
+	viper.BindPFlag("vochainConfig.CensusGCDryRun", flag.Lookup("censusGCDryRun"))
 
 	// metrics
 	viper.BindPFlag("metrics.Enabled", flag.Lookup("metricsEnabled"))
@@ -437,7 +478,7 @@ func main() {
 
 		// Census service
 		if globalCfg.API.Census {
-			cm, err = service.Census(globalCfg.DataDir, ma)
+			cm, err = service.Census(globalCfg.DataDir, globalCfg.API, ma)
 			if err != nil {
 				log.Fatal(err)
 			}
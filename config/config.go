@@ -72,10 +72,24 @@ type API struct {
 	Tendermint bool
 	Vote       bool
 	Results    bool
+	// CensusTree is the census tree implementation <graviton, iden3, poseidon>
+	CensusTree string
+	// CensusIdleTimeout is the time in minutes a census tree can be unused
+	// before being closed, 0 disables it
+	CensusIdleTimeout int
+	// CensusMaxLoaded is the maximum number of open census trees, the least
+	// recently used are closed first, 0 means no limit
+	CensusMaxLoaded int
 	// AllowPrivate allow to use private methods
 	AllowPrivate bool
 	// AllowedAddrs allowed addresses to interact with
 	AllowedAddrs string
+	// Roles grant addresses access to sets of private methods. If there are
+	// roles, AllowedAddrs are granted all the private methods by the reserved
+	// role allowed-addrs.
+	Roles []APIRole
+	// AuditLog is the file where the private calls are logged
+	AuditLog string
 	// ListenPort port where the API server will listen on
 	ListenPort int
 	// ListenHost host where the API server will listen on
@@ -90,6 +104,34 @@ type API struct {
 	WebsocketsReadLimit int64
 	// Enable HTTP API
 	HTTP bool
+	// Enable the REST API facade
+	REST bool
+	// Enable the length-prefixed protobuf transport
+	Protobuf bool
+	// RateLimit is the number of requests per second allowed to each client,
+	// 0 disables the rate limiter
+	RateLimit      float64
+	RateLimitBurst int
+	// RateLimitMethods are per method budgets, as method:rate:burst items
+	// separated by commas
+	RateLimitMethods string
+	// Workers is the number of API requests handled concurrently
+	Workers int
+	// QueueSize is the maximum number of API requests waiting for a worker
+	QueueSize int
+	// RequestTimeout is the time in seconds an API request may wait and run
+	RequestTimeout int
+}
+
+// APIRole is a named set of private methods granted to a set of addresses
+type APIRole struct {
+	Name string
+	// Methods of the role, the predefined roles census-admin, file-admin,
+	// tx-submitter and results-admin have default methods
+	Methods   []string
+	Addresses []string
+	// Expiry is the RFC3339 time when the role stops granting access, if set
+	Expiry string
 }
 
 // IPFSCfg includes all possible config params needed by IPFS
@@ -192,8 +234,17 @@ type VochainCfg struct {
 	KeyKeeperIndex int8
 	// ImportPreviousCensus if true the census downloader will try to download all census (not only the new ones)
 	ImportPreviousCensus bool
+	// CensusGCInterval is the time in minutes between two removals of the imported census
+	// not used by any ongoing process, zero disables it
+	CensusGCInterval int
+	// CensusGCGracePeriod is the time in minutes an imported census must be unused before its removal
+	CensusGCGracePeriod int
+	// CensusGCDryRun if true the census garbage collector only logs the census it would remove
+	CensusGCDryRun bool
 	// Enable Prometheus metrics from tendermint
 	TendermintMetrics bool
+	// ResultsSnapshotInterval is the minimum number of blocks between two live results snapshots
+	ResultsSnapshotInterval uint32
 }
 
 // OracleCfg includes all possible config params needed by the Oracle
@@ -2,6 +2,8 @@
 package snarks
 
 import (
+	"math/big"
+
 	i3utils "github.com/iden3/go-iden3-core/merkletree"
 	"github.com/iden3/go-iden3-crypto/poseidon"
 
@@ -25,3 +27,9 @@ func (poseidonImpl) Hash(message []byte) []byte {
 	}
 	return i3utils.BigIntToHash(hashNum).Bytes()
 }
+
+// PoseidonHash computes the Poseidon hash of a list of field elements, as
+// the circuits do, instead of hashing their bytes
+func PoseidonHash(elems ...*big.Int) (*big.Int, error) {
+	return poseidon.Hash(elems)
+}
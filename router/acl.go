@@ -0,0 +1,155 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"go.vocdoni.io/dvote/log"
+)
+
+// AllMethods grants a role every private method
+const AllMethods = "*"
+
+// AllowedAddrsRole is the role of the addresses allowed to call every private
+// method by the API AllowedAddrs, which configured roles cannot be named as
+const AllowedAddrsRole = "allowed-addrs"
+
+// DefaultRoles are the methods of the predefined roles, used when a role of
+// the configuration with one of these names does not list its methods
+var DefaultRoles = map[string][]string{
+	"census-admin": {"dump", "dumpPlain", "addCensus", "addClaim", "addClaimBulk",
+		"delClaim", "delClaimBulk", "updateClaim", "updateClaimBulk",
+		"publish", "importRemote", "getCensusList", "addCensusKeys", "delCensusKeys", "transferCensus"},
+	"file-admin":    {"addFile", "pinList", "pinFile", "unpinFile"},
+	"tx-submitter":  {"submitRawTx"},
+	"results-admin": {"publishResultsDocument"},
+}
+
+// Role grants a set of addresses access to a set of private methods until
+// Expiry, if it is not zero
+type Role struct {
+	Name      string
+	Methods   map[string]bool
+	Addresses map[ethcommon.Address]bool
+	Expiry    time.Time
+}
+
+// allows returns whether the role grants addr access to method at time now
+func (role *Role) allows(addr ethcommon.Address, method string, now time.Time) bool {
+	if !role.Expiry.IsZero() && now.After(role.Expiry) {
+		return false
+	}
+	return role.Addresses[addr] && (role.Methods[method] || role.Methods[AllMethods])
+}
+
+// ACL authorizes the private method calls by the roles of the caller
+type ACL struct {
+	lock  sync.RWMutex
+	roles map[string]*Role
+}
+
+// NewACL returns an empty ACL, which denies all the private calls
+func NewACL() *ACL {
+	return &ACL{roles: make(map[string]*Role)}
+}
+
+// AddRole adds or replaces a role. If methods is empty and name is one of the
+// DefaultRoles, the role gets its default methods.
+func (acl *ACL) AddRole(name string, methods []string, addresses []ethcommon.Address, expiry time.Time) error {
+	if name == "" {
+		return fmt.Errorf("role name is empty")
+	}
+	if len(methods) == 0 {
+		methods = DefaultRoles[name]
+	}
+	if len(methods) == 0 {
+		return fmt.Errorf("role %q has no methods", name)
+	}
+	role := &Role{
+		Name:      name,
+		Methods:   make(map[string]bool),
+		Addresses: make(map[ethcommon.Address]bool),
+		Expiry:    expiry,
+	}
+	for _, m := range methods {
+		role.Methods[m] = true
+	}
+	for _, a := range addresses {
+		role.Addresses[a] = true
+	}
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		log.Warnf("role %q expired at %s", name, expiry.Format(time.RFC3339))
+	}
+	acl.lock.Lock()
+	defer acl.lock.Unlock()
+	acl.roles[name] = role
+	return nil
+}
+
+// Allowed returns the name of a role which grants addr access to method
+func (acl *ACL) Allowed(addr ethcommon.Address, method string) (string, bool) {
+	acl.lock.RLock()
+	defer acl.lock.RUnlock()
+	now := time.Now()
+	for name, role := range acl.roles {
+		if role.allows(addr, method, now) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// AuditEntry is a private call recorded in the audit log
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Address string    `json:"address"`
+	Method  string    `json:"method"`
+	Role    string    `json:"role,omitempty"`
+	Allowed bool      `json:"allowed"`
+	Peer    string    `json:"peer,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// EnableACL authorizes the private methods with the roles of acl instead of
+// the authorized keys of the signer
+func (r *Router) EnableACL(acl *ACL) {
+	r.acl = acl
+}
+
+// EnableAuditLog writes every private call to audit as a JSON line
+func (r *Router) EnableAuditLog(audit io.Writer) {
+	r.audit = audit
+}
+
+// auditRequest writes a private call to the audit log
+func (r *Router) auditRequest(request routerRequest, err error) {
+	if r.audit == nil || !request.private {
+		return
+	}
+	entry := AuditEntry{
+		Time:    time.Now().UTC(),
+		Address: request.address.Hex(),
+		Method:  request.method,
+		Role:    request.role,
+		Allowed: err == nil,
+		Peer:    request.peer,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	line, merr := json.Marshal(entry)
+	if merr != nil {
+		log.Warnf("cannot encode audit entry: (%s)", merr)
+		return
+	}
+	r.auditLock.Lock()
+	defer r.auditLock.Unlock()
+	if _, werr := r.audit.Write(append(line, '\n')); werr != nil {
+		log.Warnf("cannot write audit entry: (%s)", werr)
+	}
+}
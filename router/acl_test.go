@@ -0,0 +1,139 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestACLAddRole(t *testing.T) {
+	acl := NewACL()
+	if err := acl.AddRole("", []string{"addFile"}, nil, time.Time{}); err == nil {
+		t.Errorf("role without name accepted")
+	}
+	if err := acl.AddRole("custom", nil, nil, time.Time{}); err == nil {
+		t.Errorf("role without methods accepted")
+	}
+
+	// a predefined role without methods gets its default ones
+	addr := ethcommon.HexToAddress("0x01")
+	if err := acl.AddRole("file-admin", nil, []ethcommon.Address{addr}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range DefaultRoles["file-admin"] {
+		if role, ok := acl.Allowed(addr, method); !ok || role != "file-admin" {
+			t.Errorf("default method %s not allowed", method)
+		}
+	}
+	if _, ok := acl.Allowed(addr, "addCensus"); ok {
+		t.Errorf("method out of the role allowed")
+	}
+
+	// adding a role again replaces it
+	if err := acl.AddRole("file-admin", []string{"pinList"}, []ethcommon.Address{addr}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := acl.Allowed(addr, "addFile"); ok {
+		t.Errorf("method of the replaced role allowed")
+	}
+	if _, ok := acl.Allowed(addr, "pinList"); !ok {
+		t.Errorf("method of the new role not allowed")
+	}
+}
+
+func TestACLAllowed(t *testing.T) {
+	acl := NewACL()
+	admin := ethcommon.HexToAddress("0x01")
+	submitter := ethcommon.HexToAddress("0x02")
+	if err := acl.AddRole(AllowedAddrsRole, []string{AllMethods}, []ethcommon.Address{admin}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := acl.AddRole("tx-submitter", nil, []ethcommon.Address{submitter}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"addCensus", "submitRawTx", "unknownMethod"} {
+		if role, ok := acl.Allowed(admin, method); !ok || role != AllowedAddrsRole {
+			t.Errorf("method %s not allowed to every method role, got %q", method, role)
+		}
+	}
+	if role, ok := acl.Allowed(submitter, "submitRawTx"); !ok || role != "tx-submitter" {
+		t.Errorf("method of the role not allowed, got %q", role)
+	}
+	if _, ok := acl.Allowed(submitter, "addCensus"); ok {
+		t.Errorf("method out of the role allowed")
+	}
+	if _, ok := acl.Allowed(ethcommon.HexToAddress("0x03"), "submitRawTx"); ok {
+		t.Errorf("address without roles allowed")
+	}
+	if _, ok := NewACL().Allowed(admin, "addCensus"); ok {
+		t.Errorf("empty ACL allowed a call")
+	}
+}
+
+func TestACLExpiry(t *testing.T) {
+	acl := NewACL()
+	expired := ethcommon.HexToAddress("0x01")
+	valid := ethcommon.HexToAddress("0x02")
+	now := time.Now()
+	if err := acl.AddRole("expired", []string{"addFile"}, []ethcommon.Address{expired}, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := acl.AddRole("valid", []string{"addFile"}, []ethcommon.Address{valid}, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := acl.Allowed(expired, "addFile"); ok {
+		t.Errorf("expired role allowed a call")
+	}
+	if role, ok := acl.Allowed(valid, "addFile"); !ok || role != "valid" {
+		t.Errorf("role not expired yet denied a call")
+	}
+
+	// a role expires once its expiry time is past
+	role := acl.roles["valid"]
+	if !role.allows(valid, "addFile", now.Add(59*time.Minute)) {
+		t.Errorf("role denied a call before its expiry")
+	}
+	if role.allows(valid, "addFile", now.Add(61*time.Minute)) {
+		t.Errorf("role allowed a call after its expiry")
+	}
+}
+
+func TestAuditRequest(t *testing.T) {
+	var audit bytes.Buffer
+	r := &Router{}
+	r.EnableAuditLog(&audit)
+	addr := ethcommon.HexToAddress("0x01")
+
+	r.auditRequest(routerRequest{method: "getInfo", address: addr}, nil)
+	if audit.Len() != 0 {
+		t.Fatalf("public call written to the audit log")
+	}
+	r.auditRequest(routerRequest{method: "addFile", address: addr, private: true,
+		role: "file-admin", peer: "1.2.3.4"}, nil)
+	r.auditRequest(routerRequest{method: "addCensus", address: addr, private: true},
+		errors.New("not authorized"))
+
+	dec := json.NewDecoder(&audit)
+	var entries []AuditEntry
+	for dec.More() {
+		var entry AuditEntry
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	if e := entries[0]; e.Method != "addFile" || e.Role != "file-admin" || !e.Allowed ||
+		e.Peer != "1.2.3.4" || e.Address != addr.Hex() || e.Error != "" {
+		t.Errorf("wrong audit entry %+v", e)
+	}
+	if e := entries[1]; e.Method != "addCensus" || e.Allowed || e.Error != "not authorized" {
+		t.Errorf("wrong audit entry %+v", e)
+	}
+}
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vocdoni/multirpc/transports"
+)
+
+// MaxBatchSize is the maximum number of requests of a batch message
+const MaxBatchSize = 100
+
+// isBatch returns whether a message is a JSON array of requests
+func isBatch(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
+
+// routeBatch handles a JSON array of signed requests concurrently and replies
+// with the array of their responses, in the same order
+func (r *Router) routeBatch(msg transports.Message) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg.Data, &batch); err != nil {
+		r.sendError(routerRequest{MessageContext: msg.Context}, fmt.Sprintf("invalid batch: (%s)", err))
+		return
+	}
+	if len(batch) == 0 || len(batch) > MaxBatchSize {
+		r.sendError(routerRequest{MessageContext: msg.Context},
+			fmt.Sprintf("invalid batch: must have between 1 and %d requests", MaxBatchSize))
+		return
+	}
+	peer := peerOf(msg.Context)
+	replies := make([]json.RawMessage, len(batch))
+	var wg sync.WaitGroup
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := &restContext{peer: peer}
+			request := r.routeBatchRequest(batch[i], ctx)
+			if ctx.reply == nil || !json.Valid(ctx.reply.Data) {
+				// handlers reply with plain text on marshaling errors
+				r.sendError(request, "no response")
+			}
+			if ctx.reply != nil {
+				replies[i] = ctx.reply.Data
+			}
+		}(i)
+	}
+	wg.Wait()
+	data, err := json.Marshal(replies)
+	if err != nil {
+		r.sendError(routerRequest{MessageContext: msg.Context}, fmt.Sprintf("cannot encode batch: (%s)", err))
+		return
+	}
+	msg.Context.Send(transports.Message{
+		TimeStamp: int32(time.Now().Unix()),
+		Context:   msg.Context,
+		Data:      data,
+	})
+}
+
+// routeBatchRequest handles a request of a batch, whose reply is kept in ctx,
+// and returns the parsed request
+func (r *Router) routeBatchRequest(payload []byte, ctx *restContext) routerRequest {
+	request, err := r.getRequest(payload, ctx)
+	request.MessageContext = ctx
+	method, err := r.validateRequest(request, err)
+	if err == nil {
+		err = r.rateLimit(request)
+	}
+	if err == nil {
+		r.countRequest(request)
+		err = r.call(context.Background(), request, method)
+	}
+	if err != nil {
+		r.sendError(request, err.Error())
+	}
+	return request
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vocdoni/multirpc/transports"
+
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/types"
+)
+
+func TestRouteBatch(t *testing.T) {
+	signer := ethereum.NewSignKeys()
+	if err := signer.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	r := &Router{signer: signer, methods: make(map[string]registeredMethod)}
+	r.pool = newWorkerPool(2, 10, time.Hour, false, r.sendExpired)
+	r.registerPublic("testReply", func(request routerRequest) {
+		request.Send(r.buildReply(request, &types.MetaResponse{Message: "replied"}))
+	})
+	// a handler which never replies
+	r.registerPublic("testSilent", func(request routerRequest) {})
+
+	var batch []types.RequestMessage
+	for _, req := range [][2]string{{"1", "testReply"}, {"2", "testSilent"}, {"3", "testUnknown"}} {
+		inner, err := json.Marshal(types.MetaRequest{Method: req[1]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		batch = append(batch, types.RequestMessage{ID: req[0], MetaRequest: inner})
+	}
+	data, err := json.Marshal(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &restContext{}
+	r.routeBatch(transports.Message{Data: data, Context: ctx})
+	if ctx.reply == nil {
+		t.Fatal("no reply to the batch")
+	}
+
+	var replies []types.ResponseMessage
+	if err := json.Unmarshal(ctx.reply.Data, &replies); err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != len(batch) {
+		t.Fatalf("expected %d replies, got %d", len(batch), len(replies))
+	}
+	for i, reply := range replies {
+		var inner types.MetaResponse
+		if err := json.Unmarshal(reply.MetaResponse, &inner); err != nil {
+			t.Fatal(err)
+		}
+		// every reply, even the errors, has the ID of its request
+		if id := batch[i].ID; reply.ID != id || inner.Request != id {
+			t.Errorf("reply %d has the ID %q (%q), expected %q", i, reply.ID, inner.Request, id)
+		}
+		switch i {
+		case 0:
+			if !inner.Ok || inner.Message != "replied" {
+				t.Errorf("wrong reply %+v", inner)
+			}
+		case 1:
+			if inner.Ok || inner.Message != "no response" {
+				t.Errorf("expected a no response error, got %+v", inner)
+			}
+		case 2:
+			if inner.Ok {
+				t.Errorf("unknown method succeeded")
+			}
+		}
+	}
+}
@@ -18,9 +18,9 @@ func (r *Router) censusLocal(request routerRequest) {
 			return
 		}
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	ctx, cancel := context.WithTimeout(request.reqContext(), time.Minute)
 	defer cancel()
-	resp := r.census.Handler(ctx, &request.MetaRequest, auth, util.TrimHex(addr.String())+"/")
+	resp := r.census.Handler(ctx, &request.MetaRequest, auth, util.TrimHex(addr.String())+"/", addr)
 	if !resp.Ok {
 		r.sendError(request, resp.Message)
 		return
@@ -27,7 +27,7 @@ func (r *Router) fetchFile(request routerRequest) {
 			found = true
 			splt := strings.Split(parsedURIs[idx], "/")
 			hash := splt[len(splt)-1]
-			ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+			ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
 			content, err = r.storage.Retrieve(ctx, hash)
 			if err == nil && len(content) == 0 {
 				err = fmt.Errorf("no content fetched")
@@ -59,7 +59,7 @@ func (r *Router) fetchFile(request routerRequest) {
 
 func (r *Router) addFile(request routerRequest) {
 	log.Debugf("calling addFile")
-	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
 	defer cancel()
 	switch request.Type {
 	case "swarm":
@@ -80,7 +80,7 @@ func (r *Router) addFile(request routerRequest) {
 
 func (r *Router) pinList(request routerRequest) {
 	log.Debug("calling PinList")
-	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
 	defer cancel()
 	pins, err := r.storage.ListPins(ctx)
 	if err != nil {
@@ -99,7 +99,7 @@ func (r *Router) pinList(request routerRequest) {
 
 func (r *Router) pinFile(request routerRequest) {
 	log.Debugf("calling PinFile %s", request.URI)
-	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
 	defer cancel()
 	err := r.storage.Pin(ctx, request.URI)
 	if err != nil {
@@ -112,7 +112,7 @@ func (r *Router) pinFile(request routerRequest) {
 
 func (r *Router) unpinFile(request routerRequest) {
 	log.Debugf("calling UnPinFile %s", request.URI)
-	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
 	defer cancel()
 	err := r.storage.Unpin(ctx, request.URI)
 	if err != nil {
@@ -20,9 +20,44 @@ var (
 		Name:      "public_reqs",
 		Help:      "The number of public requests processed",
 	}, []string{"method"})
+	// RouterRateLimited ...
+	RouterRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "router",
+		Name:      "rate_limited_reqs",
+		Help:      "The number of requests rejected by the rate limiter",
+	}, []string{"method", "client"})
+	// RouterQueueDepth ...
+	RouterQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "router",
+		Name:      "queue_depth",
+		Help:      "The number of requests waiting for a worker",
+	}, []string{"priority"})
+	// RouterQueueTime ...
+	RouterQueueTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "router",
+		Name:      "queue_seconds",
+		Help:      "The time requests wait for a worker",
+	}, []string{"priority"})
+	// RouterRejectedReqs ...
+	RouterRejectedReqs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "router",
+		Name:      "rejected_reqs",
+		Help:      "The number of requests rejected because the queue is full",
+	}, []string{"method"})
+	// RouterTimeouts ...
+	RouterTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "router",
+		Name:      "timeouts",
+		Help:      "The number of requests which timed out before being handled",
+	}, []string{"method"})
 )
 
 func (r *Router) registerMetrics(ma *metrics.Agent) {
 	ma.Register(RouterPrivateReqs)
 	ma.Register(RouterPublicReqs)
+	ma.Register(RouterRateLimited)
+	ma.Register(RouterQueueDepth)
+	ma.Register(RouterQueueTime)
+	ma.Register(RouterRejectedReqs)
+	ma.Register(RouterTimeouts)
 }
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"reflect"
+	"strings"
+
+	"go.vocdoni.io/dvote/types"
+)
+
+// openAPIDocument generates the OpenAPI 3 description of the REST routes.
+// Parameter and body schemas are derived from the MetaRequest and MetaResponse
+// types, so the document follows the API types as they change.
+func openAPIDocument(prefix string, routes []restRoute) map[string]interface{} {
+	requestType := reflect.TypeOf(types.MetaRequest{})
+	response := map[string]interface{}{
+		"description": "Signed API response. The signature is sent in the X-Signature header.",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/MetaResponse"},
+			},
+		},
+	}
+	paths := make(map[string]interface{})
+	for _, rt := range routes {
+		var params []interface{}
+		for _, part := range strings.Split(rt.path, "/") {
+			if strings.HasPrefix(part, "{") {
+				params = append(params, openAPIParameter(requestType, strings.Trim(part, "{}"), "path"))
+			}
+		}
+		for _, name := range rt.query {
+			params = append(params, openAPIParameter(requestType, name, "query"))
+		}
+		operation := map[string]interface{}{
+			"operationId": rt.apiMethod,
+			"summary":     rt.description,
+			"responses": map[string]interface{}{
+				"200": response,
+				"400": response,
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if rt.method == "POST" {
+			operation["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{"$ref": "#/components/schemas/MetaRequest"},
+					},
+				},
+			}
+		}
+		item, ok := paths[rt.path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = operation
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Vocdoni REST API",
+			"version": "1",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": prefix}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"MetaRequest":  openAPISchema(requestType),
+				"MetaResponse": openAPISchema(reflect.TypeOf(types.MetaResponse{})),
+			},
+		},
+	}
+}
+
+func openAPIParameter(t reflect.Type, name, in string) map[string]interface{} {
+	param := map[string]interface{}{
+		"name":     name,
+		"in":       in,
+		"required": in == "path",
+	}
+	if field, ok := metaField(t, name); ok {
+		param["schema"] = openAPISchema(field.Type)
+	}
+	return param
+}
+
+var hexBytesType = reflect.TypeOf(types.HexBytes{})
+
+// openAPISchema returns the JSON schema of a type as encoded by encoding/json
+func openAPISchema(t reflect.Type) map[string]interface{} {
+	if t == hexBytesType {
+		return map[string]interface{}{"type": "string", "format": "hex"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return openAPISchema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": openAPISchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": openAPISchema(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if name := jsonName(f); name != "" && name != "-" && f.PkgPath == "" {
+				properties[name] = openAPISchema(f.Type)
+			}
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	}
+	return map[string]interface{}{}
+}
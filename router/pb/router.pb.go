@@ -0,0 +1,3219 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.12.4
+// source: router.proto
+
+package pb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	models "go.vocdoni.io/proto/build/go/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are assignable to Method:
+	//	*Request_SubmitEnvelope
+	//	*Request_EnvelopeStatus
+	//	*Request_Call
+	//	*Request_Subscribe
+	//	*Request_Unsubscribe
+	Method isRequest_Method `protobuf_oneof:"method"`
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Request) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (m *Request) GetMethod() isRequest_Method {
+	if m != nil {
+		return m.Method
+	}
+	return nil
+}
+
+func (x *Request) GetSubmitEnvelope() *SubmitEnvelope {
+	if x, ok := x.GetMethod().(*Request_SubmitEnvelope); ok {
+		return x.SubmitEnvelope
+	}
+	return nil
+}
+
+func (x *Request) GetEnvelopeStatus() *EnvelopeStatusRequest {
+	if x, ok := x.GetMethod().(*Request_EnvelopeStatus); ok {
+		return x.EnvelopeStatus
+	}
+	return nil
+}
+
+func (x *Request) GetCall() *Call {
+	if x, ok := x.GetMethod().(*Request_Call); ok {
+		return x.Call
+	}
+	return nil
+}
+
+func (x *Request) GetSubscribe() *Subscription {
+	if x, ok := x.GetMethod().(*Request_Subscribe); ok {
+		return x.Subscribe
+	}
+	return nil
+}
+
+func (x *Request) GetUnsubscribe() *Subscription {
+	if x, ok := x.GetMethod().(*Request_Unsubscribe); ok {
+		return x.Unsubscribe
+	}
+	return nil
+}
+
+type isRequest_Method interface {
+	isRequest_Method()
+}
+
+type Request_SubmitEnvelope struct {
+	SubmitEnvelope *SubmitEnvelope `protobuf:"bytes,2,opt,name=submitEnvelope,proto3,oneof"`
+}
+
+type Request_EnvelopeStatus struct {
+	EnvelopeStatus *EnvelopeStatusRequest `protobuf:"bytes,3,opt,name=envelopeStatus,proto3,oneof"`
+}
+
+type Request_Call struct {
+	Call *Call `protobuf:"bytes,4,opt,name=call,proto3,oneof"`
+}
+
+type Request_Subscribe struct {
+	Subscribe *Subscription `protobuf:"bytes,5,opt,name=subscribe,proto3,oneof"`
+}
+
+type Request_Unsubscribe struct {
+	Unsubscribe *Subscription `protobuf:"bytes,6,opt,name=unsubscribe,proto3,oneof"`
+}
+
+func (*Request_SubmitEnvelope) isRequest_Method() {}
+
+func (*Request_EnvelopeStatus) isRequest_Method() {}
+
+func (*Request_Call) isRequest_Method() {}
+
+func (*Request_Subscribe) isRequest_Method() {}
+
+func (*Request_Unsubscribe) isRequest_Method() {}
+
+type SubmitEnvelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Envelope      []byte `protobuf:"bytes,1,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	Signature     []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	WaitForCommit bool   `protobuf:"varint,3,opt,name=waitForCommit,proto3" json:"waitForCommit,omitempty"`
+}
+
+func (x *SubmitEnvelope) Reset() {
+	*x = SubmitEnvelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitEnvelope) ProtoMessage() {}
+
+func (x *SubmitEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitEnvelope.ProtoReflect.Descriptor instead.
+func (*SubmitEnvelope) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitEnvelope) GetEnvelope() []byte {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
+func (x *SubmitEnvelope) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SubmitEnvelope) GetWaitForCommit() bool {
+	if x != nil {
+		return x.WaitForCommit
+	}
+	return false
+}
+
+type EnvelopeStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProcessId []byte `protobuf:"bytes,1,opt,name=processId,proto3" json:"processId,omitempty"`
+	Nullifier []byte `protobuf:"bytes,2,opt,name=nullifier,proto3" json:"nullifier,omitempty"`
+}
+
+func (x *EnvelopeStatusRequest) Reset() {
+	*x = EnvelopeStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnvelopeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvelopeStatusRequest) ProtoMessage() {}
+
+func (x *EnvelopeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvelopeStatusRequest.ProtoReflect.Descriptor instead.
+func (*EnvelopeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EnvelopeStatusRequest) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *EnvelopeStatusRequest) GetNullifier() []byte {
+	if x != nil {
+		return x.Nullifier
+	}
+	return nil
+}
+
+type Call struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request   []byte `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Call) Reset() {
+	*x = Call{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Call) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Call) ProtoMessage() {}
+
+func (x *Call) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Call.ProtoReflect.Descriptor instead.
+func (*Call) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Call) GetRequest() []byte {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *Call) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type Subscription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Subscription) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+type Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ok      bool   `protobuf:"varint,2,opt,name=ok,proto3" json:"ok,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Types that are assignable to Result:
+	//	*Response_SubmitEnvelope
+	//	*Response_EnvelopeStatus
+	//	*Response_Call
+	//	*Response_Event
+	//	*Response_Subscription
+	Result    isResponse_Result `protobuf_oneof:"result"`
+	Signature []byte            `protobuf:"bytes,15,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Response) Reset() {
+	*x = Response{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response) ProtoMessage() {}
+
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Response) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Response) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *Response) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (m *Response) GetResult() isResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *Response) GetSubmitEnvelope() *SubmitEnvelopeReply {
+	if x, ok := x.GetResult().(*Response_SubmitEnvelope); ok {
+		return x.SubmitEnvelope
+	}
+	return nil
+}
+
+func (x *Response) GetEnvelopeStatus() *EnvelopeStatus {
+	if x, ok := x.GetResult().(*Response_EnvelopeStatus); ok {
+		return x.EnvelopeStatus
+	}
+	return nil
+}
+
+func (x *Response) GetCall() *CallReply {
+	if x, ok := x.GetResult().(*Response_Call); ok {
+		return x.Call
+	}
+	return nil
+}
+
+func (x *Response) GetEvent() *Event {
+	if x, ok := x.GetResult().(*Response_Event); ok {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Response) GetSubscription() *Subscription {
+	if x, ok := x.GetResult().(*Response_Subscription); ok {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *Response) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type isResponse_Result interface {
+	isResponse_Result()
+}
+
+type Response_SubmitEnvelope struct {
+	SubmitEnvelope *SubmitEnvelopeReply `protobuf:"bytes,4,opt,name=submitEnvelope,proto3,oneof"`
+}
+
+type Response_EnvelopeStatus struct {
+	EnvelopeStatus *EnvelopeStatus `protobuf:"bytes,5,opt,name=envelopeStatus,proto3,oneof"`
+}
+
+type Response_Call struct {
+	Call *CallReply `protobuf:"bytes,6,opt,name=call,proto3,oneof"`
+}
+
+type Response_Event struct {
+	Event *Event `protobuf:"bytes,7,opt,name=event,proto3,oneof"`
+}
+
+type Response_Subscription struct {
+	Subscription *Subscription `protobuf:"bytes,8,opt,name=subscription,proto3,oneof"`
+}
+
+func (*Response_SubmitEnvelope) isResponse_Result() {}
+
+func (*Response_EnvelopeStatus) isResponse_Result() {}
+
+func (*Response_Call) isResponse_Result() {}
+
+func (*Response_Event) isResponse_Result() {}
+
+func (*Response_Subscription) isResponse_Result() {}
+
+type SubmitEnvelopeReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nullifier      []byte `protobuf:"bytes,1,opt,name=nullifier,proto3" json:"nullifier,omitempty"`
+	Height         uint32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	BlockTimestamp int32  `protobuf:"varint,3,opt,name=blockTimestamp,proto3" json:"blockTimestamp,omitempty"`
+	Registered     bool   `protobuf:"varint,4,opt,name=registered,proto3" json:"registered,omitempty"`
+	Message        string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *SubmitEnvelopeReply) Reset() {
+	*x = SubmitEnvelopeReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitEnvelopeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitEnvelopeReply) ProtoMessage() {}
+
+func (x *SubmitEnvelopeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitEnvelopeReply.ProtoReflect.Descriptor instead.
+func (*SubmitEnvelopeReply) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitEnvelopeReply) GetNullifier() []byte {
+	if x != nil {
+		return x.Nullifier
+	}
+	return nil
+}
+
+func (x *SubmitEnvelopeReply) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *SubmitEnvelopeReply) GetBlockTimestamp() int32 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
+func (x *SubmitEnvelopeReply) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+func (x *SubmitEnvelopeReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type EnvelopeStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Registered     bool   `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
+	Height         uint32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	BlockTimestamp int32  `protobuf:"varint,3,opt,name=blockTimestamp,proto3" json:"blockTimestamp,omitempty"`
+}
+
+func (x *EnvelopeStatus) Reset() {
+	*x = EnvelopeStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnvelopeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvelopeStatus) ProtoMessage() {}
+
+func (x *EnvelopeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvelopeStatus.ProtoReflect.Descriptor instead.
+func (*EnvelopeStatus) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EnvelopeStatus) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+func (x *EnvelopeStatus) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *EnvelopeStatus) GetBlockTimestamp() int32 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
+type CallReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response *MetaResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *CallReply) Reset() {
+	*x = CallReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CallReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallReply) ProtoMessage() {}
+
+func (x *CallReply) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallReply.ProtoReflect.Descriptor instead.
+func (*CallReply) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CallReply) GetResponse() *MetaResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic     string      `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Height    uint32      `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	ProcessId []byte      `protobuf:"bytes,3,opt,name=processId,proto3" json:"processId,omitempty"`
+	Nullifier []byte      `protobuf:"bytes,4,opt,name=nullifier,proto3" json:"nullifier,omitempty"`
+	EntityId  []byte      `protobuf:"bytes,5,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	Status    string      `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Results   []*Question `protobuf:"bytes,7,rep,name=results,proto3" json:"results,omitempty"`
+	Final     bool        `protobuf:"varint,8,opt,name=final,proto3" json:"final,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Event) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Event) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Event) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *Event) GetNullifier() []byte {
+	if x != nil {
+		return x.Nullifier
+	}
+	return nil
+}
+
+func (x *Event) GetEntityId() []byte {
+	if x != nil {
+		return x.EntityId
+	}
+	return nil
+}
+
+func (x *Event) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Event) GetResults() []*Question {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Event) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+type Question struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Options []string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Question) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type MetaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CensusId      string               `protobuf:"bytes,1,opt,name=censusId,proto3" json:"censusId,omitempty"`
+	CensusUri     string               `protobuf:"bytes,2,opt,name=censusUri,proto3" json:"censusUri,omitempty"`
+	CensusKey     []byte               `protobuf:"bytes,3,opt,name=censusKey,proto3" json:"censusKey,omitempty"`
+	CensusKeys    [][]byte             `protobuf:"bytes,4,rep,name=censusKeys,proto3" json:"censusKeys,omitempty"`
+	CensusValue   []byte               `protobuf:"bytes,5,opt,name=censusValue,proto3" json:"censusValue,omitempty"`
+	CensusValues  [][]byte             `protobuf:"bytes,6,rep,name=censusValues,proto3" json:"censusValues,omitempty"`
+	CensusDump    []byte               `protobuf:"bytes,7,opt,name=censusDump,proto3" json:"censusDump,omitempty"`
+	Content       []byte               `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`
+	Digested      bool                 `protobuf:"varint,9,opt,name=digested,proto3" json:"digested,omitempty"`
+	EntityId      []byte               `protobuf:"bytes,10,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	From          int64                `protobuf:"varint,11,opt,name=from,proto3" json:"from,omitempty"`
+	FromId        []byte               `protobuf:"bytes,12,opt,name=fromId,proto3" json:"fromId,omitempty"`
+	Height        int64                `protobuf:"varint,13,opt,name=height,proto3" json:"height,omitempty"`
+	ListSize      int64                `protobuf:"varint,14,opt,name=listSize,proto3" json:"listSize,omitempty"`
+	Method        string               `protobuf:"bytes,15,opt,name=method,proto3" json:"method,omitempty"`
+	Name          string               `protobuf:"bytes,16,opt,name=name,proto3" json:"name,omitempty"`
+	Nullifier     []byte               `protobuf:"bytes,17,opt,name=nullifier,proto3" json:"nullifier,omitempty"`
+	Payload       []byte               `protobuf:"bytes,18,opt,name=payload,proto3" json:"payload,omitempty"`
+	ProcessId     []byte               `protobuf:"bytes,19,opt,name=processId,proto3" json:"processId,omitempty"`
+	ProofData     []byte               `protobuf:"bytes,20,opt,name=proofData,proto3" json:"proofData,omitempty"`
+	Proofs        [][]byte             `protobuf:"bytes,21,rep,name=proofs,proto3" json:"proofs,omitempty"`
+	PubKeys       []string             `protobuf:"bytes,22,rep,name=pubKeys,proto3" json:"pubKeys,omitempty"`
+	RootHash      []byte               `protobuf:"bytes,23,opt,name=rootHash,proto3" json:"rootHash,omitempty"`
+	SearchFilter  *ProcessSearchFilter `protobuf:"bytes,24,opt,name=searchFilter,proto3" json:"searchFilter,omitempty"`
+	Signature     []byte               `protobuf:"bytes,25,opt,name=signature,proto3" json:"signature,omitempty"`
+	Timestamp     int32                `protobuf:"varint,26,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	To            int64                `protobuf:"varint,27,opt,name=to,proto3" json:"to,omitempty"`
+	Topic         string               `protobuf:"bytes,28,opt,name=topic,proto3" json:"topic,omitempty"`
+	Type          string               `protobuf:"bytes,29,opt,name=type,proto3" json:"type,omitempty"`
+	Uri           string               `protobuf:"bytes,30,opt,name=uri,proto3" json:"uri,omitempty"`
+	WaitForCommit bool                 `protobuf:"varint,31,opt,name=waitForCommit,proto3" json:"waitForCommit,omitempty"`
+	BaseRoot      []byte               `protobuf:"bytes,32,opt,name=baseRoot,proto3" json:"baseRoot,omitempty"`
+	Chunked       bool                 `protobuf:"varint,33,opt,name=chunked,proto3" json:"chunked,omitempty"`
+}
+
+func (x *MetaRequest) Reset() {
+	*x = MetaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaRequest) ProtoMessage() {}
+
+func (x *MetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaRequest.ProtoReflect.Descriptor instead.
+func (*MetaRequest) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MetaRequest) GetCensusId() string {
+	if x != nil {
+		return x.CensusId
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetCensusUri() string {
+	if x != nil {
+		return x.CensusUri
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetCensusKey() []byte {
+	if x != nil {
+		return x.CensusKey
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetCensusKeys() [][]byte {
+	if x != nil {
+		return x.CensusKeys
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetCensusValue() []byte {
+	if x != nil {
+		return x.CensusValue
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetCensusValues() [][]byte {
+	if x != nil {
+		return x.CensusValues
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetCensusDump() []byte {
+	if x != nil {
+		return x.CensusDump
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetDigested() bool {
+	if x != nil {
+		return x.Digested
+	}
+	return false
+}
+
+func (x *MetaRequest) GetEntityId() []byte {
+	if x != nil {
+		return x.EntityId
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *MetaRequest) GetFromId() []byte {
+	if x != nil {
+		return x.FromId
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *MetaRequest) GetListSize() int64 {
+	if x != nil {
+		return x.ListSize
+	}
+	return 0
+}
+
+func (x *MetaRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetNullifier() []byte {
+	if x != nil {
+		return x.Nullifier
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetProofData() []byte {
+	if x != nil {
+		return x.ProofData
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetProofs() [][]byte {
+	if x != nil {
+		return x.Proofs
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetPubKeys() []string {
+	if x != nil {
+		return x.PubKeys
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetSearchFilter() *ProcessSearchFilter {
+	if x != nil {
+		return x.SearchFilter
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetTimestamp() int32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MetaRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *MetaRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *MetaRequest) GetWaitForCommit() bool {
+	if x != nil {
+		return x.WaitForCommit
+	}
+	return false
+}
+
+func (x *MetaRequest) GetBaseRoot() []byte {
+	if x != nil {
+		return x.BaseRoot
+	}
+	return nil
+}
+
+func (x *MetaRequest) GetChunked() bool {
+	if x != nil {
+		return x.Chunked
+	}
+	return false
+}
+
+type ProcessSearchFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Anonymous      *bool  `protobuf:"varint,1,opt,name=anonymous,proto3,oneof" json:"anonymous,omitempty"`
+	CensusOrigin   string `protobuf:"bytes,2,opt,name=censusOrigin,proto3" json:"censusOrigin,omitempty"`
+	EncryptedVotes *bool  `protobuf:"varint,3,opt,name=encryptedVotes,proto3,oneof" json:"encryptedVotes,omitempty"`
+	EndBlockFrom   uint32 `protobuf:"varint,4,opt,name=endBlockFrom,proto3" json:"endBlockFrom,omitempty"`
+	EndBlockTo     uint32 `protobuf:"varint,5,opt,name=endBlockTo,proto3" json:"endBlockTo,omitempty"`
+	Serial         *bool  `protobuf:"varint,6,opt,name=serial,proto3,oneof" json:"serial,omitempty"`
+	StartBlockFrom uint32 `protobuf:"varint,7,opt,name=startBlockFrom,proto3" json:"startBlockFrom,omitempty"`
+	StartBlockTo   uint32 `protobuf:"varint,8,opt,name=startBlockTo,proto3" json:"startBlockTo,omitempty"`
+	Status         string `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	UniqueValues   *bool  `protobuf:"varint,10,opt,name=uniqueValues,proto3,oneof" json:"uniqueValues,omitempty"`
+}
+
+func (x *ProcessSearchFilter) Reset() {
+	*x = ProcessSearchFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessSearchFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessSearchFilter) ProtoMessage() {}
+
+func (x *ProcessSearchFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessSearchFilter.ProtoReflect.Descriptor instead.
+func (*ProcessSearchFilter) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ProcessSearchFilter) GetAnonymous() bool {
+	if x != nil && x.Anonymous != nil {
+		return *x.Anonymous
+	}
+	return false
+}
+
+func (x *ProcessSearchFilter) GetCensusOrigin() string {
+	if x != nil {
+		return x.CensusOrigin
+	}
+	return ""
+}
+
+func (x *ProcessSearchFilter) GetEncryptedVotes() bool {
+	if x != nil && x.EncryptedVotes != nil {
+		return *x.EncryptedVotes
+	}
+	return false
+}
+
+func (x *ProcessSearchFilter) GetEndBlockFrom() uint32 {
+	if x != nil {
+		return x.EndBlockFrom
+	}
+	return 0
+}
+
+func (x *ProcessSearchFilter) GetEndBlockTo() uint32 {
+	if x != nil {
+		return x.EndBlockTo
+	}
+	return 0
+}
+
+func (x *ProcessSearchFilter) GetSerial() bool {
+	if x != nil && x.Serial != nil {
+		return *x.Serial
+	}
+	return false
+}
+
+func (x *ProcessSearchFilter) GetStartBlockFrom() uint32 {
+	if x != nil {
+		return x.StartBlockFrom
+	}
+	return 0
+}
+
+func (x *ProcessSearchFilter) GetStartBlockTo() uint32 {
+	if x != nil {
+		return x.StartBlockTo
+	}
+	return 0
+}
+
+func (x *ProcessSearchFilter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProcessSearchFilter) GetUniqueValues() bool {
+	if x != nil && x.UniqueValues != nil {
+		return *x.UniqueValues
+	}
+	return false
+}
+
+type MetaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiList            []string           `protobuf:"bytes,1,rep,name=apiList,proto3" json:"apiList,omitempty"`
+	BlockTime          []int32            `protobuf:"varint,2,rep,packed,name=blockTime,proto3" json:"blockTime,omitempty"`
+	BlockTimestamp     int32              `protobuf:"varint,3,opt,name=blockTimestamp,proto3" json:"blockTimestamp,omitempty"`
+	CensusId           string             `protobuf:"bytes,4,opt,name=censusId,proto3" json:"censusId,omitempty"`
+	CensusList         []string           `protobuf:"bytes,5,rep,name=censusList,proto3" json:"censusList,omitempty"`
+	CensusKeys         [][]byte           `protobuf:"bytes,6,rep,name=censusKeys,proto3" json:"censusKeys,omitempty"`
+	CensusValues       [][]byte           `protobuf:"bytes,7,rep,name=censusValues,proto3" json:"censusValues,omitempty"`
+	CensusDump         []byte             `protobuf:"bytes,8,opt,name=censusDump,proto3" json:"censusDump,omitempty"`
+	CommitmentKeys     []*Key             `protobuf:"bytes,9,rep,name=commitmentKeys,proto3" json:"commitmentKeys,omitempty"`
+	Content            []byte             `protobuf:"bytes,10,opt,name=content,proto3" json:"content,omitempty"`
+	DocumentSignature  []byte             `protobuf:"bytes,11,opt,name=documentSignature,proto3" json:"documentSignature,omitempty"`
+	EncryptionPrivKeys []*Key             `protobuf:"bytes,12,rep,name=encryptionPrivKeys,proto3" json:"encryptionPrivKeys,omitempty"`
+	EncryptionPubKeys  []*Key             `protobuf:"bytes,13,rep,name=encryptionPubKeys,proto3" json:"encryptionPubKeys,omitempty"`
+	EntityId           string             `protobuf:"bytes,14,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	EntityIds          []string           `protobuf:"bytes,15,rep,name=entityIds,proto3" json:"entityIds,omitempty"`
+	Files              []byte             `protobuf:"bytes,16,opt,name=files,proto3" json:"files,omitempty"`
+	Finished           *bool              `protobuf:"varint,17,opt,name=finished,proto3,oneof" json:"finished,omitempty"`
+	Health             int32              `protobuf:"varint,18,opt,name=health,proto3" json:"health,omitempty"`
+	Height             *uint32            `protobuf:"varint,19,opt,name=height,proto3,oneof" json:"height,omitempty"`
+	InvalidClaims      []int64            `protobuf:"varint,20,rep,packed,name=invalidClaims,proto3" json:"invalidClaims,omitempty"`
+	Message            string             `protobuf:"bytes,21,opt,name=message,proto3" json:"message,omitempty"`
+	Nullifier          string             `protobuf:"bytes,22,opt,name=nullifier,proto3" json:"nullifier,omitempty"`
+	Nullifiers         []string           `protobuf:"bytes,23,rep,name=nullifiers,proto3" json:"nullifiers,omitempty"`
+	Ok                 bool               `protobuf:"varint,24,opt,name=ok,proto3" json:"ok,omitempty"`
+	Paused             *bool              `protobuf:"varint,25,opt,name=paused,proto3,oneof" json:"paused,omitempty"`
+	Payload            string             `protobuf:"bytes,26,opt,name=payload,proto3" json:"payload,omitempty"`
+	Process            *ProcessDetails    `protobuf:"bytes,27,opt,name=process,proto3" json:"process,omitempty"`
+	ProcessId          []byte             `protobuf:"bytes,28,opt,name=processId,proto3" json:"processId,omitempty"`
+	ProcessIds         []string           `protobuf:"bytes,29,rep,name=processIds,proto3" json:"processIds,omitempty"`
+	ProcessList        []string           `protobuf:"bytes,30,rep,name=processList,proto3" json:"processList,omitempty"`
+	ProcessStats       *ProcessStats      `protobuf:"bytes,31,opt,name=processStats,proto3" json:"processStats,omitempty"`
+	Processes          []*ProcessSummary  `protobuf:"bytes,32,rep,name=processes,proto3" json:"processes,omitempty"`
+	Proofs             [][]byte           `protobuf:"bytes,33,rep,name=proofs,proto3" json:"proofs,omitempty"`
+	PubKeys            []string           `protobuf:"bytes,34,rep,name=pubKeys,proto3" json:"pubKeys,omitempty"`
+	Registered         *bool              `protobuf:"varint,35,opt,name=registered,proto3,oneof" json:"registered,omitempty"`
+	Request            string             `protobuf:"bytes,36,opt,name=request,proto3" json:"request,omitempty"`
+	Results            []*Question        `protobuf:"bytes,37,rep,name=results,proto3" json:"results,omitempty"`
+	ResultsSeries      []*ResultsSnapshot `protobuf:"bytes,38,rep,name=resultsSeries,proto3" json:"resultsSeries,omitempty"`
+	RevealKeys         []*Key             `protobuf:"bytes,39,rep,name=revealKeys,proto3" json:"revealKeys,omitempty"`
+	Root               []byte             `protobuf:"bytes,40,opt,name=root,proto3" json:"root,omitempty"`
+	RootHistory        []*CensusRoot      `protobuf:"bytes,41,rep,name=rootHistory,proto3" json:"rootHistory,omitempty"`
+	Siblings           []byte             `protobuf:"bytes,42,opt,name=siblings,proto3" json:"siblings,omitempty"`
+	SignedHeader       []byte             `protobuf:"bytes,43,opt,name=signedHeader,proto3" json:"signedHeader,omitempty"`
+	Size               *int64             `protobuf:"varint,44,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	State              string             `protobuf:"bytes,45,opt,name=state,proto3" json:"state,omitempty"`
+	StateProof         *StateProof        `protobuf:"bytes,46,opt,name=stateProof,proto3" json:"stateProof,omitempty"`
+	Timestamp          int32              `protobuf:"varint,47,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Topic              string             `protobuf:"bytes,48,opt,name=topic,proto3" json:"topic,omitempty"`
+	Type               string             `protobuf:"bytes,49,opt,name=type,proto3" json:"type,omitempty"`
+	Uri                string             `protobuf:"bytes,50,opt,name=uri,proto3" json:"uri,omitempty"`
+	ValidProof         *bool              `protobuf:"varint,51,opt,name=validProof,proto3,oneof" json:"validProof,omitempty"`
+	ValidProofs        []bool             `protobuf:"varint,52,rep,packed,name=validProofs,proto3" json:"validProofs,omitempty"`
+}
+
+func (x *MetaResponse) Reset() {
+	*x = MetaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaResponse) ProtoMessage() {}
+
+func (x *MetaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaResponse.ProtoReflect.Descriptor instead.
+func (*MetaResponse) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MetaResponse) GetApiList() []string {
+	if x != nil {
+		return x.ApiList
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetBlockTime() []int32 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetBlockTimestamp() int32 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
+func (x *MetaResponse) GetCensusId() string {
+	if x != nil {
+		return x.CensusId
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetCensusList() []string {
+	if x != nil {
+		return x.CensusList
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetCensusKeys() [][]byte {
+	if x != nil {
+		return x.CensusKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetCensusValues() [][]byte {
+	if x != nil {
+		return x.CensusValues
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetCensusDump() []byte {
+	if x != nil {
+		return x.CensusDump
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetCommitmentKeys() []*Key {
+	if x != nil {
+		return x.CommitmentKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetDocumentSignature() []byte {
+	if x != nil {
+		return x.DocumentSignature
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetEncryptionPrivKeys() []*Key {
+	if x != nil {
+		return x.EncryptionPrivKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetEncryptionPubKeys() []*Key {
+	if x != nil {
+		return x.EncryptionPubKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetEntityIds() []string {
+	if x != nil {
+		return x.EntityIds
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetFiles() []byte {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetFinished() bool {
+	if x != nil && x.Finished != nil {
+		return *x.Finished
+	}
+	return false
+}
+
+func (x *MetaResponse) GetHealth() int32 {
+	if x != nil {
+		return x.Health
+	}
+	return 0
+}
+
+func (x *MetaResponse) GetHeight() uint32 {
+	if x != nil && x.Height != nil {
+		return *x.Height
+	}
+	return 0
+}
+
+func (x *MetaResponse) GetInvalidClaims() []int64 {
+	if x != nil {
+		return x.InvalidClaims
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetNullifier() string {
+	if x != nil {
+		return x.Nullifier
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetNullifiers() []string {
+	if x != nil {
+		return x.Nullifiers
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *MetaResponse) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+func (x *MetaResponse) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetProcess() *ProcessDetails {
+	if x != nil {
+		return x.Process
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProcessIds() []string {
+	if x != nil {
+		return x.ProcessIds
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProcessList() []string {
+	if x != nil {
+		return x.ProcessList
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProcessStats() *ProcessStats {
+	if x != nil {
+		return x.ProcessStats
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProcesses() []*ProcessSummary {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetProofs() [][]byte {
+	if x != nil {
+		return x.Proofs
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetPubKeys() []string {
+	if x != nil {
+		return x.PubKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetRegistered() bool {
+	if x != nil && x.Registered != nil {
+		return *x.Registered
+	}
+	return false
+}
+
+func (x *MetaResponse) GetRequest() string {
+	if x != nil {
+		return x.Request
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetResults() []*Question {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetResultsSeries() []*ResultsSnapshot {
+	if x != nil {
+		return x.ResultsSeries
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetRevealKeys() []*Key {
+	if x != nil {
+		return x.RevealKeys
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetRoot() []byte {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetRootHistory() []*CensusRoot {
+	if x != nil {
+		return x.RootHistory
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetSiblings() []byte {
+	if x != nil {
+		return x.Siblings
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetSignedHeader() []byte {
+	if x != nil {
+		return x.SignedHeader
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetSize() int64 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *MetaResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetStateProof() *StateProof {
+	if x != nil {
+		return x.StateProof
+	}
+	return nil
+}
+
+func (x *MetaResponse) GetTimestamp() int32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MetaResponse) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *MetaResponse) GetValidProof() bool {
+	if x != nil && x.ValidProof != nil {
+		return *x.ValidProof
+	}
+	return false
+}
+
+func (x *MetaResponse) GetValidProofs() []bool {
+	if x != nil {
+		return x.ValidProofs
+	}
+	return nil
+}
+
+type Key struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Idx int32  `protobuf:"varint,1,opt,name=idx,proto3" json:"idx,omitempty"`
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *Key) Reset() {
+	*x = Key{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Key) ProtoMessage() {}
+
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Key) GetIdx() int32 {
+	if x != nil {
+		return x.Idx
+	}
+	return 0
+}
+
+func (x *Key) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ProcessDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockCount       uint32                     `protobuf:"varint,1,opt,name=blockCount,proto3" json:"blockCount,omitempty"`
+	CensusOrigin     string                     `protobuf:"bytes,2,opt,name=censusOrigin,proto3" json:"censusOrigin,omitempty"`
+	CensusRoot       []byte                     `protobuf:"bytes,3,opt,name=censusRoot,proto3" json:"censusRoot,omitempty"`
+	CensusUri        string                     `protobuf:"bytes,4,opt,name=censusUri,proto3" json:"censusUri,omitempty"`
+	EndBlock         uint32                     `protobuf:"varint,5,opt,name=endBlock,proto3" json:"endBlock,omitempty"`
+	EntityId         []byte                     `protobuf:"bytes,6,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	EnvelopeType     *models.EnvelopeType       `protobuf:"bytes,7,opt,name=envelopeType,proto3" json:"envelopeType,omitempty"`
+	KeyIndex         uint32                     `protobuf:"varint,8,opt,name=keyIndex,proto3" json:"keyIndex,omitempty"`
+	Mode             *models.ProcessMode        `protobuf:"bytes,9,opt,name=mode,proto3" json:"mode,omitempty"`
+	Namespace        uint32                     `protobuf:"varint,10,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	ProcessId        []byte                     `protobuf:"bytes,11,opt,name=processId,proto3" json:"processId,omitempty"`
+	QuestionCount    uint32                     `protobuf:"varint,12,opt,name=questionCount,proto3" json:"questionCount,omitempty"`
+	QuestionIndex    uint32                     `protobuf:"varint,13,opt,name=questionIndex,proto3" json:"questionIndex,omitempty"`
+	ResultsAvailable bool                       `protobuf:"varint,14,opt,name=resultsAvailable,proto3" json:"resultsAvailable,omitempty"`
+	StartBlock       uint32                     `protobuf:"varint,15,opt,name=startBlock,proto3" json:"startBlock,omitempty"`
+	Status           string                     `protobuf:"bytes,16,opt,name=status,proto3" json:"status,omitempty"`
+	VoteCount        uint32                     `protobuf:"varint,17,opt,name=voteCount,proto3" json:"voteCount,omitempty"`
+	VoteOptions      *models.ProcessVoteOptions `protobuf:"bytes,18,opt,name=voteOptions,proto3" json:"voteOptions,omitempty"`
+}
+
+func (x *ProcessDetails) Reset() {
+	*x = ProcessDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessDetails) ProtoMessage() {}
+
+func (x *ProcessDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessDetails.ProtoReflect.Descriptor instead.
+func (*ProcessDetails) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProcessDetails) GetBlockCount() uint32 {
+	if x != nil {
+		return x.BlockCount
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetCensusOrigin() string {
+	if x != nil {
+		return x.CensusOrigin
+	}
+	return ""
+}
+
+func (x *ProcessDetails) GetCensusRoot() []byte {
+	if x != nil {
+		return x.CensusRoot
+	}
+	return nil
+}
+
+func (x *ProcessDetails) GetCensusUri() string {
+	if x != nil {
+		return x.CensusUri
+	}
+	return ""
+}
+
+func (x *ProcessDetails) GetEndBlock() uint32 {
+	if x != nil {
+		return x.EndBlock
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetEntityId() []byte {
+	if x != nil {
+		return x.EntityId
+	}
+	return nil
+}
+
+func (x *ProcessDetails) GetEnvelopeType() *models.EnvelopeType {
+	if x != nil {
+		return x.EnvelopeType
+	}
+	return nil
+}
+
+func (x *ProcessDetails) GetKeyIndex() uint32 {
+	if x != nil {
+		return x.KeyIndex
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetMode() *models.ProcessMode {
+	if x != nil {
+		return x.Mode
+	}
+	return nil
+}
+
+func (x *ProcessDetails) GetNamespace() uint32 {
+	if x != nil {
+		return x.Namespace
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *ProcessDetails) GetQuestionCount() uint32 {
+	if x != nil {
+		return x.QuestionCount
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetQuestionIndex() uint32 {
+	if x != nil {
+		return x.QuestionIndex
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetResultsAvailable() bool {
+	if x != nil {
+		return x.ResultsAvailable
+	}
+	return false
+}
+
+func (x *ProcessDetails) GetStartBlock() uint32 {
+	if x != nil {
+		return x.StartBlock
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProcessDetails) GetVoteCount() uint32 {
+	if x != nil {
+		return x.VoteCount
+	}
+	return 0
+}
+
+func (x *ProcessDetails) GetVoteOptions() *models.ProcessVoteOptions {
+	if x != nil {
+		return x.VoteOptions
+	}
+	return nil
+}
+
+type ProcessStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CensusSize       *int64            `protobuf:"varint,1,opt,name=censusSize,proto3,oneof" json:"censusSize,omitempty"`
+	Envelopes        uint64            `protobuf:"varint,2,opt,name=envelopes,proto3" json:"envelopes,omitempty"`
+	InvalidEnvelopes uint64            `protobuf:"varint,3,opt,name=invalidEnvelopes,proto3" json:"invalidEnvelopes,omitempty"`
+	TotalWeight      string            `protobuf:"bytes,4,opt,name=totalWeight,proto3" json:"totalWeight,omitempty"`
+	Turnout          *float64          `protobuf:"fixed64,5,opt,name=turnout,proto3,oneof" json:"turnout,omitempty"`
+	VotesPerBlock    map[uint32]uint64 `protobuf:"bytes,6,rep,name=votesPerBlock,proto3" json:"votesPerBlock,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *ProcessStats) Reset() {
+	*x = ProcessStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessStats) ProtoMessage() {}
+
+func (x *ProcessStats) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessStats.ProtoReflect.Descriptor instead.
+func (*ProcessStats) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProcessStats) GetCensusSize() int64 {
+	if x != nil && x.CensusSize != nil {
+		return *x.CensusSize
+	}
+	return 0
+}
+
+func (x *ProcessStats) GetEnvelopes() uint64 {
+	if x != nil {
+		return x.Envelopes
+	}
+	return 0
+}
+
+func (x *ProcessStats) GetInvalidEnvelopes() uint64 {
+	if x != nil {
+		return x.InvalidEnvelopes
+	}
+	return 0
+}
+
+func (x *ProcessStats) GetTotalWeight() string {
+	if x != nil {
+		return x.TotalWeight
+	}
+	return ""
+}
+
+func (x *ProcessStats) GetTurnout() float64 {
+	if x != nil && x.Turnout != nil {
+		return *x.Turnout
+	}
+	return 0
+}
+
+func (x *ProcessStats) GetVotesPerBlock() map[uint32]uint64 {
+	if x != nil {
+		return x.VotesPerBlock
+	}
+	return nil
+}
+
+type ProcessSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Anonymous      bool   `protobuf:"varint,1,opt,name=anonymous,proto3" json:"anonymous,omitempty"`
+	BlockCount     uint32 `protobuf:"varint,2,opt,name=blockCount,proto3" json:"blockCount,omitempty"`
+	CensusOrigin   string `protobuf:"bytes,3,opt,name=censusOrigin,proto3" json:"censusOrigin,omitempty"`
+	EncryptedVotes bool   `protobuf:"varint,4,opt,name=encryptedVotes,proto3" json:"encryptedVotes,omitempty"`
+	EndBlock       uint32 `protobuf:"varint,5,opt,name=endBlock,proto3" json:"endBlock,omitempty"`
+	EntityId       []byte `protobuf:"bytes,6,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	ProcessId      []byte `protobuf:"bytes,7,opt,name=processId,proto3" json:"processId,omitempty"`
+	Serial         bool   `protobuf:"varint,8,opt,name=serial,proto3" json:"serial,omitempty"`
+	StartBlock     uint32 `protobuf:"varint,9,opt,name=startBlock,proto3" json:"startBlock,omitempty"`
+	Status         string `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	UniqueValues   bool   `protobuf:"varint,11,opt,name=uniqueValues,proto3" json:"uniqueValues,omitempty"`
+}
+
+func (x *ProcessSummary) Reset() {
+	*x = ProcessSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessSummary) ProtoMessage() {}
+
+func (x *ProcessSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessSummary.ProtoReflect.Descriptor instead.
+func (*ProcessSummary) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ProcessSummary) GetAnonymous() bool {
+	if x != nil {
+		return x.Anonymous
+	}
+	return false
+}
+
+func (x *ProcessSummary) GetBlockCount() uint32 {
+	if x != nil {
+		return x.BlockCount
+	}
+	return 0
+}
+
+func (x *ProcessSummary) GetCensusOrigin() string {
+	if x != nil {
+		return x.CensusOrigin
+	}
+	return ""
+}
+
+func (x *ProcessSummary) GetEncryptedVotes() bool {
+	if x != nil {
+		return x.EncryptedVotes
+	}
+	return false
+}
+
+func (x *ProcessSummary) GetEndBlock() uint32 {
+	if x != nil {
+		return x.EndBlock
+	}
+	return 0
+}
+
+func (x *ProcessSummary) GetEntityId() []byte {
+	if x != nil {
+		return x.EntityId
+	}
+	return nil
+}
+
+func (x *ProcessSummary) GetProcessId() []byte {
+	if x != nil {
+		return x.ProcessId
+	}
+	return nil
+}
+
+func (x *ProcessSummary) GetSerial() bool {
+	if x != nil {
+		return x.Serial
+	}
+	return false
+}
+
+func (x *ProcessSummary) GetStartBlock() uint32 {
+	if x != nil {
+		return x.StartBlock
+	}
+	return 0
+}
+
+func (x *ProcessSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProcessSummary) GetUniqueValues() bool {
+	if x != nil {
+		return x.UniqueValues
+	}
+	return false
+}
+
+type ResultsSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Height  uint32      `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Results []*Question `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *ResultsSnapshot) Reset() {
+	*x = ResultsSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResultsSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultsSnapshot) ProtoMessage() {}
+
+func (x *ResultsSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultsSnapshot.ProtoReflect.Descriptor instead.
+func (*ResultsSnapshot) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ResultsSnapshot) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ResultsSnapshot) GetResults() []*Question {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type CensusRoot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Root      []byte `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	Timestamp int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *CensusRoot) Reset() {
+	*x = CensusRoot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CensusRoot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CensusRoot) ProtoMessage() {}
+
+func (x *CensusRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CensusRoot.ProtoReflect.Descriptor instead.
+func (*CensusRoot) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CensusRoot) GetRoot() []byte {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *CensusRoot) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type StateProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Height int64             `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Key    []byte            `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Proof  []byte            `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	Roots  map[string][]byte `protobuf:"bytes,4,rep,name=roots,proto3" json:"roots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Tree   string            `protobuf:"bytes,5,opt,name=tree,proto3" json:"tree,omitempty"`
+	Value  []byte            `protobuf:"bytes,6,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *StateProof) Reset() {
+	*x = StateProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_router_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateProof) ProtoMessage() {}
+
+func (x *StateProof) ProtoReflect() protoreflect.Message {
+	mi := &file_router_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateProof.ProtoReflect.Descriptor instead.
+func (*StateProof) Descriptor() ([]byte, []int) {
+	return file_router_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StateProof) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *StateProof) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *StateProof) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *StateProof) GetRoots() map[string][]byte {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+func (x *StateProof) GetTree() string {
+	if x != nil {
+		return x.Tree
+	}
+	return ""
+}
+
+func (x *StateProof) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_router_proto protoreflect.FileDescriptor
+
+var file_router_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f,
+	0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a,
+	0x15, 0x76, 0x6f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x76, 0x6f, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xef, 0x02, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x49, 0x0a, 0x0e, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x76, 0x6f,
+	0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0e, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x50, 0x0a,
+	0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52,
+	0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x2b, 0x0a, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x61, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x12, 0x3d, 0x0a, 0x09,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x41, 0x0a, 0x0b, 0x75,
+	0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48,
+	0x00, 0x52, 0x0b, 0x75, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x42, 0x08,
+	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x70, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x65, 0x6e,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x77, 0x61, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x77, 0x61, 0x69,
+	0x74, 0x46, 0x6f, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x53, 0x0a, 0x15, 0x45, 0x6e,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22,
+	0x3e, 0x0a, 0x04, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22,
+	0x24, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x70, 0x69, 0x63, 0x22, 0xae, 0x03, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02,
+	0x6f, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x4e, 0x0a, 0x0e,
+	0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x49, 0x0a, 0x0e,
+	0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x48, 0x00, 0x52, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x12, 0x2e, 0x0a, 0x05, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x43, 0x0a, 0x0c, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x08, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xad, 0x01, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1e, 0x0a, 0x0a,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x70, 0x0a, 0x0e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f,
+	0x70, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x26, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x46, 0x0a, 0x09, 0x43, 0x61, 0x6c, 0x6c,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x39, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xf0, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x76, 0x6f, 0x74,
+	0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x22, 0x24, 0x0a, 0x08, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb9, 0x07, 0x0a, 0x0b, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x55,
+	0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73,
+	0x55, 0x72, 0x69, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4b, 0x65,
+	0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4b, 0x65, 0x79, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4b, 0x65, 0x79,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75,
+	0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75,
+	0x73, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x72, 0x6f, 0x6d, 0x49, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x66,
+	0x72, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x6c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x6c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x44, 0x61, 0x74, 0x61, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x16, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x48, 0x0a, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x52, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x19,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x1a, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x0e, 0x0a,
+	0x02, 0x74, 0x6f, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x1d, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x69, 0x12, 0x24, 0x0a, 0x0d, 0x77, 0x61, 0x69,
+	0x74, 0x46, 0x6f, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0d, 0x77, 0x61, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x20, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x65, 0x64, 0x18, 0x21, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x65, 0x64, 0x22, 0xb4, 0x03, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a,
+	0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x00, 0x52, 0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x12, 0x2b, 0x0a, 0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65,
+	0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x0e,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x46, 0x72, 0x6f,
+	0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x54, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x54, 0x6f, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x46, 0x72, 0x6f, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x6f, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x27, 0x0a, 0x0c, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0c,
+	0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x42, 0x11, 0x0a,
+	0x0f, 0x5f, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73,
+	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0xb4, 0x0f, 0x0a,
+	0x0c, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x70, 0x69, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x61, 0x70, 0x69, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x65, 0x6e, 0x73, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x63,
+	0x65, 0x6e, 0x73, 0x75, 0x73, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x3c, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x0e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x11, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x44, 0x0a, 0x12, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x72, 0x69, 0x76, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x72, 0x69, 0x76, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x42, 0x0a, 0x11, 0x65, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x11, 0x65, 0x6e, 0x63, 0x72,
+	0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a,
+	0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x00, 0x52, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x13, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x43, 0x6c,
+	0x61, 0x69, 0x6d, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18,
+	0x17, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x75, 0x6c, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f,
+	0x6b, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x19, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x02, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x39, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x76, 0x6f, 0x74,
+	0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64,
+	0x18, 0x1c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49,
+	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64, 0x73, 0x18,
+	0x1d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x49, 0x64,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74,
+	0x18, 0x1e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x76, 0x6f, 0x74,
+	0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x3d, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x20, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x76, 0x6f, 0x74,
+	0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18,
+	0x21, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x23, 0x0a, 0x0a, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x23, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0a, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x24, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x25, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x46, 0x0a, 0x0d, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x26, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x53, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0a, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x4b, 0x65, 0x79,
+	0x73, 0x18, 0x27, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x0a, 0x72,
+	0x65, 0x76, 0x65, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x28, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x12, 0x3d, 0x0a,
+	0x0b, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x29, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x52,
+	0x0b, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x2d,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3b, 0x0a, 0x0a, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x2f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18,
+	0x30, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x31, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x32, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x72, 0x69, 0x12, 0x23, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x33, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x0b, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18, 0x34, 0x20, 0x03, 0x28, 0x08, 0x52, 0x0b, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x66, 0x69,
+	0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x22, 0x29, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x69, 0x64, 0x78, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0xa9,
+	0x05, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x52,
+	0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75,
+	0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x55,
+	0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73,
+	0x55, 0x72, 0x69, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x40, 0x0a, 0x0c, 0x65,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0c, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x6b, 0x65, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x6b, 0x65, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2f, 0x0a, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0d, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x41, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x6f, 0x74, 0x65, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x76, 0x6f, 0x74, 0x65, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0b, 0x76, 0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x64, 0x76, 0x6f, 0x74,
+	0x65, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x56, 0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0b, 0x76,
+	0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xf3, 0x02, 0x0a, 0x0c, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0a, 0x63,
+	0x65, 0x6e, 0x73, 0x75, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x0a, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x2a,
+	0x0a, 0x10, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x07,
+	0x74, 0x75, 0x72, 0x6e, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52,
+	0x07, 0x74, 0x75, 0x72, 0x6e, 0x6f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12, 0x56, 0x0a, 0x0d, 0x76,
+	0x6f, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x2e, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x1a, 0x40, 0x0a, 0x12, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73,
+	0x53, 0x69, 0x7a, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x74, 0x75, 0x72, 0x6e, 0x6f, 0x75, 0x74,
+	0x22, 0xe4, 0x02, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x4f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x65,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x75, 0x6e, 0x69, 0x71, 0x75,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x5e, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x65, 0x6e, 0x73, 0x75,
+	0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xee, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x3c, 0x0a, 0x05, 0x72, 0x6f, 0x6f, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x72,
+	0x6f, 0x6f, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x72, 0x65, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x72, 0x65, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x38,
+	0x0a, 0x0a, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x1f, 0x5a, 0x1d, 0x67, 0x6f, 0x2e, 0x76,
+	0x6f, 0x63, 0x64, 0x6f, 0x6e, 0x69, 0x2e, 0x69, 0x6f, 0x2f, 0x64, 0x76, 0x6f, 0x74, 0x65, 0x2f,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_router_proto_rawDescOnce sync.Once
+	file_router_proto_rawDescData = file_router_proto_rawDesc
+)
+
+func file_router_proto_rawDescGZIP() []byte {
+	file_router_proto_rawDescOnce.Do(func() {
+		file_router_proto_rawDescData = protoimpl.X.CompressGZIP(file_router_proto_rawDescData)
+	})
+	return file_router_proto_rawDescData
+}
+
+var file_router_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_router_proto_goTypes = []interface{}{
+	(*Request)(nil),                   // 0: dvote.router.v1.Request
+	(*SubmitEnvelope)(nil),            // 1: dvote.router.v1.SubmitEnvelope
+	(*EnvelopeStatusRequest)(nil),     // 2: dvote.router.v1.EnvelopeStatusRequest
+	(*Call)(nil),                      // 3: dvote.router.v1.Call
+	(*Subscription)(nil),              // 4: dvote.router.v1.Subscription
+	(*Response)(nil),                  // 5: dvote.router.v1.Response
+	(*SubmitEnvelopeReply)(nil),       // 6: dvote.router.v1.SubmitEnvelopeReply
+	(*EnvelopeStatus)(nil),            // 7: dvote.router.v1.EnvelopeStatus
+	(*CallReply)(nil),                 // 8: dvote.router.v1.CallReply
+	(*Event)(nil),                     // 9: dvote.router.v1.Event
+	(*Question)(nil),                  // 10: dvote.router.v1.Question
+	(*MetaRequest)(nil),               // 11: dvote.router.v1.MetaRequest
+	(*ProcessSearchFilter)(nil),       // 12: dvote.router.v1.ProcessSearchFilter
+	(*MetaResponse)(nil),              // 13: dvote.router.v1.MetaResponse
+	(*Key)(nil),                       // 14: dvote.router.v1.Key
+	(*ProcessDetails)(nil),            // 15: dvote.router.v1.ProcessDetails
+	(*ProcessStats)(nil),              // 16: dvote.router.v1.ProcessStats
+	(*ProcessSummary)(nil),            // 17: dvote.router.v1.ProcessSummary
+	(*ResultsSnapshot)(nil),           // 18: dvote.router.v1.ResultsSnapshot
+	(*CensusRoot)(nil),                // 19: dvote.router.v1.CensusRoot
+	(*StateProof)(nil),                // 20: dvote.router.v1.StateProof
+	nil,                               // 21: dvote.router.v1.ProcessStats.VotesPerBlockEntry
+	nil,                               // 22: dvote.router.v1.StateProof.RootsEntry
+	(*models.EnvelopeType)(nil),       // 23: dvote.types.v1.EnvelopeType
+	(*models.ProcessMode)(nil),        // 24: dvote.types.v1.ProcessMode
+	(*models.ProcessVoteOptions)(nil), // 25: dvote.types.v1.ProcessVoteOptions
+}
+var file_router_proto_depIdxs = []int32{
+	1,  // 0: dvote.router.v1.Request.submitEnvelope:type_name -> dvote.router.v1.SubmitEnvelope
+	2,  // 1: dvote.router.v1.Request.envelopeStatus:type_name -> dvote.router.v1.EnvelopeStatusRequest
+	3,  // 2: dvote.router.v1.Request.call:type_name -> dvote.router.v1.Call
+	4,  // 3: dvote.router.v1.Request.subscribe:type_name -> dvote.router.v1.Subscription
+	4,  // 4: dvote.router.v1.Request.unsubscribe:type_name -> dvote.router.v1.Subscription
+	6,  // 5: dvote.router.v1.Response.submitEnvelope:type_name -> dvote.router.v1.SubmitEnvelopeReply
+	7,  // 6: dvote.router.v1.Response.envelopeStatus:type_name -> dvote.router.v1.EnvelopeStatus
+	8,  // 7: dvote.router.v1.Response.call:type_name -> dvote.router.v1.CallReply
+	9,  // 8: dvote.router.v1.Response.event:type_name -> dvote.router.v1.Event
+	4,  // 9: dvote.router.v1.Response.subscription:type_name -> dvote.router.v1.Subscription
+	13, // 10: dvote.router.v1.CallReply.response:type_name -> dvote.router.v1.MetaResponse
+	10, // 11: dvote.router.v1.Event.results:type_name -> dvote.router.v1.Question
+	12, // 12: dvote.router.v1.MetaRequest.searchFilter:type_name -> dvote.router.v1.ProcessSearchFilter
+	14, // 13: dvote.router.v1.MetaResponse.commitmentKeys:type_name -> dvote.router.v1.Key
+	14, // 14: dvote.router.v1.MetaResponse.encryptionPrivKeys:type_name -> dvote.router.v1.Key
+	14, // 15: dvote.router.v1.MetaResponse.encryptionPubKeys:type_name -> dvote.router.v1.Key
+	15, // 16: dvote.router.v1.MetaResponse.process:type_name -> dvote.router.v1.ProcessDetails
+	16, // 17: dvote.router.v1.MetaResponse.processStats:type_name -> dvote.router.v1.ProcessStats
+	17, // 18: dvote.router.v1.MetaResponse.processes:type_name -> dvote.router.v1.ProcessSummary
+	10, // 19: dvote.router.v1.MetaResponse.results:type_name -> dvote.router.v1.Question
+	18, // 20: dvote.router.v1.MetaResponse.resultsSeries:type_name -> dvote.router.v1.ResultsSnapshot
+	14, // 21: dvote.router.v1.MetaResponse.revealKeys:type_name -> dvote.router.v1.Key
+	19, // 22: dvote.router.v1.MetaResponse.rootHistory:type_name -> dvote.router.v1.CensusRoot
+	20, // 23: dvote.router.v1.MetaResponse.stateProof:type_name -> dvote.router.v1.StateProof
+	23, // 24: dvote.router.v1.ProcessDetails.envelopeType:type_name -> dvote.types.v1.EnvelopeType
+	24, // 25: dvote.router.v1.ProcessDetails.mode:type_name -> dvote.types.v1.ProcessMode
+	25, // 26: dvote.router.v1.ProcessDetails.voteOptions:type_name -> dvote.types.v1.ProcessVoteOptions
+	21, // 27: dvote.router.v1.ProcessStats.votesPerBlock:type_name -> dvote.router.v1.ProcessStats.VotesPerBlockEntry
+	10, // 28: dvote.router.v1.ResultsSnapshot.results:type_name -> dvote.router.v1.Question
+	22, // 29: dvote.router.v1.StateProof.roots:type_name -> dvote.router.v1.StateProof.RootsEntry
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_router_proto_init() }
+func file_router_proto_init() {
+	if File_router_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_router_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitEnvelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnvelopeStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Call); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subscription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitEnvelopeReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnvelopeStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CallReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Question); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessSearchFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Key); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResultsSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CensusRoot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_router_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_router_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Request_SubmitEnvelope)(nil),
+		(*Request_EnvelopeStatus)(nil),
+		(*Request_Call)(nil),
+		(*Request_Subscribe)(nil),
+		(*Request_Unsubscribe)(nil),
+	}
+	file_router_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*Response_SubmitEnvelope)(nil),
+		(*Response_EnvelopeStatus)(nil),
+		(*Response_Call)(nil),
+		(*Response_Event)(nil),
+		(*Response_Subscription)(nil),
+	}
+	file_router_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_router_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_router_proto_msgTypes[16].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_router_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_router_proto_goTypes,
+		DependencyIndexes: file_router_proto_depIdxs,
+		MessageInfos:      file_router_proto_msgTypes,
+	}.Build()
+	File_router_proto = out.File
+	file_router_proto_rawDesc = nil
+	file_router_proto_goTypes = nil
+	file_router_proto_depIdxs = nil
+}
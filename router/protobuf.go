@@ -0,0 +1,548 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/router/pb"
+	"go.vocdoni.io/dvote/types"
+)
+
+const (
+	// pbMaxMessageSize is the maximum size of a protobuf transport message
+	pbMaxMessageSize = 1 << 20
+	// pbMaxBodySize is the maximum size of an HTTP/1 request body
+	pbMaxBodySize = 64 << 20
+	// pbResponseSignature is the field number of the signature of a Response
+	pbResponseSignature = 15
+)
+
+// pbStream is the response side of a protobuf transport connection. Writes
+// are serialized since subscription events are pushed concurrently.
+type pbStream struct {
+	lock sync.Mutex
+	w    http.ResponseWriter
+	peer string
+}
+
+// write sends a length-prefixed message and flushes it to the client
+func (s *pbStream) write(msg []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(msg)))
+	if _, err := s.w.Write(size[:]); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(msg); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// readPBMessage reads a length-prefixed message
+func readPBMessage(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > pbMaxMessageSize {
+		return nil, fmt.Errorf("message too big: %d bytes", n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ProtobufHandler returns the HTTP handler of the length-prefixed protobuf
+// transport. The request and response bodies are streams of messages, which
+// are read and replied in order. With HTTP/2 the replies are sent while the
+// request body is still being read, and the handler keeps pushing the
+// subscription events until the client goes away.
+func (r *Router) ProtobufHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		stream := &pbStream{w: w, peer: remoteHost(req.RemoteAddr)}
+		if r.subs != nil {
+			defer r.subs.delConn(stream)
+		}
+		var body io.Reader = bufio.NewReader(req.Body)
+		if req.ProtoMajor < 2 {
+			// HTTP/1 does not allow writing the response while the request
+			// body is being read, so read all the requests first
+			data, err := ioutil.ReadAll(io.LimitReader(req.Body, pbMaxBodySize))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("cannot read body: %s", err), http.StatusBadRequest)
+				return
+			}
+			body = bytes.NewReader(data)
+		}
+		for {
+			msg, err := readPBMessage(body)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Debugf("cannot read protobuf request: (%s)", err)
+				return
+			}
+			if err := stream.write(r.servePB(req.Context(), stream, msg)); err != nil {
+				log.Debugf("cannot write protobuf response: (%s)", err)
+				return
+			}
+		}
+		if r.subs != nil && r.subs.count(stream) > 0 {
+			<-req.Context().Done()
+		}
+	}
+}
+
+// servePB runs a protobuf transport request and returns the encoded response
+func (r *Router) servePB(ctx context.Context, stream *pbStream, msg []byte) []byte {
+	pbreq := new(pb.Request)
+	if err := proto.Unmarshal(msg, pbreq); err != nil {
+		return r.pbResponse(&pb.Response{}, fmt.Errorf("cannot decode request: (%s)", err))
+	}
+	response := &pb.Response{Id: pbreq.Id}
+	var err error
+	switch m := pbreq.Method.(type) {
+	case *pb.Request_SubmitEnvelope:
+		var reply *pb.SubmitEnvelopeReply
+		reply, err = r.pbSubmitEnvelope(ctx, stream, pbreq.Id, m.SubmitEnvelope)
+		response.Result = &pb.Response_SubmitEnvelope{SubmitEnvelope: reply}
+	case *pb.Request_EnvelopeStatus:
+		var reply *pb.EnvelopeStatus
+		reply, err = r.pbEnvelopeStatus(ctx, stream, pbreq.Id, m.EnvelopeStatus)
+		response.Result = &pb.Response_EnvelopeStatus{EnvelopeStatus: reply}
+	case *pb.Request_Call:
+		var reply *pb.CallReply
+		reply, err = r.pbCallMethod(ctx, stream, pbreq.Id, m.Call)
+		response.Result = &pb.Response_Call{Call: reply}
+	case *pb.Request_Subscribe:
+		var reply *pb.Subscription
+		reply, err = r.pbSubscription(stream, pbreq.Id, "subscribe", m.Subscribe)
+		response.Result = &pb.Response_Subscription{Subscription: reply}
+	case *pb.Request_Unsubscribe:
+		var reply *pb.Subscription
+		reply, err = r.pbSubscription(stream, pbreq.Id, "unsubscribe", m.Unsubscribe)
+		response.Result = &pb.Response_Subscription{Subscription: reply}
+	default:
+		err = fmt.Errorf("request has no call")
+	}
+	return r.pbResponse(response, err)
+}
+
+// pbCall runs an API request in the worker pool, as the JSON transport does,
+// and returns its response. signed is the encoded request and signature its
+// signature, which are only checked for the private methods.
+func (r *Router) pbCall(ctx context.Context, stream *pbStream, id string, meta types.MetaRequest,
+	signed, signature []byte) (*types.MetaResponse, error) {
+	reply := &restContext{peer: stream.peer}
+	request := routerRequest{id: id, MessageContext: reply, peer: stream.peer}
+	method, err := r.validateRequest(request, r.authenticate(&request, meta, signed, signature))
+	if err == nil {
+		err = r.rateLimit(request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.countRequest(request)
+	if err := r.call(ctx, request, method); err != nil {
+		return nil, err
+	}
+	if reply.reply == nil {
+		return nil, fmt.Errorf("no response")
+	}
+	var respOuter types.ResponseMessage
+	if err := json.Unmarshal(reply.reply.Data, &respOuter); err != nil {
+		return nil, fmt.Errorf("%s", reply.reply.Data)
+	}
+	response := new(types.MetaResponse)
+	if err := json.Unmarshal(respOuter.MetaResponse, response); err != nil {
+		return nil, err
+	}
+	if !response.Ok {
+		return nil, fmt.Errorf("%s", response.Message)
+	}
+	return response, nil
+}
+
+func (r *Router) pbSubmitEnvelope(ctx context.Context, stream *pbStream, id string,
+	req *pb.SubmitEnvelope) (*pb.SubmitEnvelopeReply, error) {
+	response, err := r.pbCall(ctx, stream, id, types.MetaRequest{
+		Method:        "submitEnvelope",
+		Payload:       req.Envelope,
+		Signature:     req.Signature,
+		WaitForCommit: req.WaitForCommit,
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.SubmitEnvelopeReply{
+		BlockTimestamp: response.BlockTimestamp,
+		Message:        response.Message,
+	}
+	if reply.Nullifier, err = hex.DecodeString(response.Nullifier); err != nil {
+		return nil, err
+	}
+	if response.Height != nil {
+		reply.Height = *response.Height
+	}
+	if response.Registered != nil {
+		reply.Registered = *response.Registered
+	}
+	return reply, nil
+}
+
+func (r *Router) pbEnvelopeStatus(ctx context.Context, stream *pbStream, id string,
+	req *pb.EnvelopeStatusRequest) (*pb.EnvelopeStatus, error) {
+	response, err := r.pbCall(ctx, stream, id, types.MetaRequest{
+		Method:    "getEnvelopeStatus",
+		ProcessID: req.ProcessId,
+		Nullifier: req.Nullifier,
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.EnvelopeStatus{BlockTimestamp: response.BlockTimestamp}
+	if response.Registered != nil {
+		reply.Registered = *response.Registered
+	}
+	if response.Height != nil {
+		reply.Height = *response.Height
+	}
+	return reply, nil
+}
+
+// pbCallMethod runs any API method with a MetaRequest
+func (r *Router) pbCallMethod(ctx context.Context, stream *pbStream, id string,
+	req *pb.Call) (*pb.CallReply, error) {
+	meta := new(pb.MetaRequest)
+	if err := proto.Unmarshal(req.Request, meta); err != nil {
+		return nil, fmt.Errorf("cannot decode call request: (%s)", err)
+	}
+	response, err := r.pbCall(ctx, stream, id, metaRequestFromPB(meta), req.Request, req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CallReply{Response: metaResponseToPB(response)}, nil
+}
+
+// pbSubscription subscribes or unsubscribes the stream to a topic, name is
+// the API method
+func (r *Router) pbSubscription(stream *pbStream, id, name string, req *pb.Subscription) (*pb.Subscription, error) {
+	if _, ok := r.methods[name]; !ok {
+		return nil, fmt.Errorf("router has no method %q", name)
+	}
+	request := routerRequest{method: name, peer: stream.peer}
+	if err := r.rateLimit(request); err != nil {
+		return nil, err
+	}
+	r.countRequest(request)
+	topic, err := parseTopic(req.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("cannot %s: (%s)", name, err)
+	}
+	if name == "unsubscribe" {
+		r.subs.del(topic, stream)
+		return &pb.Subscription{Topic: topic}, nil
+	}
+	// The request ID of the subscribe call is used as the ID of the events
+	sub := &subscriber{conn: stream, send: func(response *types.MetaResponse) error {
+		return stream.write(r.pbResponse(&pb.Response{
+			Id:     id,
+			Result: &pb.Response_Event{Event: pbEvent(response)},
+		}, nil))
+	}}
+	if err := r.subs.add(topic, sub); err != nil {
+		return nil, fmt.Errorf("cannot subscribe: (%s)", err)
+	}
+	return &pb.Subscription{Topic: topic}, nil
+}
+
+// pbResponse encodes and signs a Response. If err is not nil the response is
+// not ok and has no result. The signature is the last field, so the signed
+// message is the encoded response without it.
+func (r *Router) pbResponse(response *pb.Response, err error) []byte {
+	if err != nil {
+		log.Warn(err)
+		response = &pb.Response{Id: response.Id, Message: err.Error()}
+	} else {
+		response.Ok = true
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(response)
+	if err != nil {
+		// This should never happen
+		log.Error(err)
+		return nil
+	}
+	signature, err := r.signer.Sign(b)
+	if err != nil {
+		log.Error(err)
+		// continue without the signature
+		return b
+	}
+	// proto.Marshal does not keep the field order, so the signature is
+	// appended to the signed bytes
+	b = protowire.AppendTag(b, pbResponseSignature, protowire.BytesType)
+	return protowire.AppendBytes(b, signature)
+}
+
+// pbEvent returns the Event of a subscription push message
+func pbEvent(response *types.MetaResponse) *pb.Event {
+	event := &pb.Event{
+		Topic:     response.Topic,
+		ProcessId: response.ProcessID,
+		Status:    response.State,
+		Results:   pbQuestions(response.Results),
+	}
+	if response.Height != nil {
+		event.Height = *response.Height
+	}
+	if nullifier, err := hex.DecodeString(response.Nullifier); err == nil {
+		event.Nullifier = nullifier
+	}
+	if eid, err := hex.DecodeString(response.EntityID); err == nil {
+		event.EntityId = eid
+	}
+	if response.Finished != nil {
+		event.Final = *response.Finished
+	}
+	return event
+}
+
+// metaRequestFromPB returns the request fields of a protobuf MetaRequest
+func metaRequestFromPB(m *pb.MetaRequest) types.MetaRequest {
+	req := types.MetaRequest{
+		BaseRoot:      m.BaseRoot,
+		CensusID:      m.CensusId,
+		CensusURI:     m.CensusUri,
+		CensusKey:     m.CensusKey,
+		CensusKeys:    m.CensusKeys,
+		CensusValue:   m.CensusValue,
+		CensusValues:  hexBytesList(m.CensusValues),
+		CensusDump:    m.CensusDump,
+		Chunked:       m.Chunked,
+		Content:       m.Content,
+		Digested:      m.Digested,
+		EntityId:      m.EntityId,
+		From:          m.From,
+		FromID:        m.FromId,
+		Height:        m.Height,
+		ListSize:      m.ListSize,
+		Method:        m.Method,
+		Name:          m.Name,
+		Nullifier:     m.Nullifier,
+		Payload:       m.Payload,
+		ProcessID:     m.ProcessId,
+		ProofData:     m.ProofData,
+		Proofs:        hexBytesList(m.Proofs),
+		PubKeys:       m.PubKeys,
+		RootHash:      m.RootHash,
+		Signature:     m.Signature,
+		Timestamp:     m.Timestamp,
+		To:            m.To,
+		Topic:         m.Topic,
+		Type:          m.Type,
+		URI:           m.Uri,
+		WaitForCommit: m.WaitForCommit,
+	}
+	if f := m.SearchFilter; f != nil {
+		req.SearchFilter = &types.ProcessSearchFilter{
+			Anonymous:      f.Anonymous,
+			CensusOrigin:   f.CensusOrigin,
+			EncryptedVotes: f.EncryptedVotes,
+			EndBlockFrom:   f.EndBlockFrom,
+			EndBlockTo:     f.EndBlockTo,
+			Serial:         f.Serial,
+			StartBlockFrom: f.StartBlockFrom,
+			StartBlockTo:   f.StartBlockTo,
+			Status:         f.Status,
+			UniqueValues:   f.UniqueValues,
+		}
+	}
+	return req
+}
+
+// metaResponseToPB returns the protobuf MetaResponse of a response
+func metaResponseToPB(r *types.MetaResponse) *pb.MetaResponse {
+	m := &pb.MetaResponse{
+		ApiList:            r.APIList,
+		BlockTimestamp:     r.BlockTimestamp,
+		CensusId:           r.CensusID,
+		CensusList:         r.CensusList,
+		CensusKeys:         r.CensusKeys,
+		CensusValues:       bytesList(r.CensusValues),
+		CensusDump:         r.CensusDump,
+		CommitmentKeys:     pbKeys(r.CommitmentKeys),
+		Content:            r.Content,
+		DocumentSignature:  r.DocumentSignature,
+		EncryptionPrivKeys: pbKeys(r.EncryptionPrivKeys),
+		EncryptionPubKeys:  pbKeys(r.EncryptionPublicKeys),
+		EntityId:           r.EntityID,
+		EntityIds:          r.EntityIDs,
+		Files:              r.Files,
+		Finished:           r.Finished,
+		Health:             r.Health,
+		Height:             r.Height,
+		Message:            r.Message,
+		Nullifier:          r.Nullifier,
+		Ok:                 r.Ok,
+		Paused:             r.Paused,
+		Payload:            r.Payload,
+		ProcessId:          r.ProcessID,
+		ProcessIds:         r.ProcessIDs,
+		ProcessList:        r.ProcessList,
+		Proofs:             bytesList(r.Proofs),
+		PubKeys:            r.PubKeys,
+		Registered:         r.Registered,
+		Request:            r.Request,
+		Results:            pbQuestions(r.Results),
+		RevealKeys:         pbKeys(r.RevealKeys),
+		Root:               r.Root,
+		Siblings:           r.Siblings,
+		SignedHeader:       r.SignedHeader,
+		Size:               r.Size,
+		State:              r.State,
+		Timestamp:          r.Timestamp,
+		Topic:              r.Topic,
+		Type:               r.Type,
+		Uri:                r.URI,
+		ValidProof:         r.ValidProof,
+		ValidProofs:        r.ValidProofs,
+	}
+	if r.BlockTime != nil {
+		m.BlockTime = r.BlockTime[:]
+	}
+	for _, c := range r.InvalidClaims {
+		m.InvalidClaims = append(m.InvalidClaims, int64(c))
+	}
+	if r.Nullifiers != nil {
+		m.Nullifiers = *r.Nullifiers
+	}
+	if p := r.Process; p != nil {
+		m.Process = &pb.ProcessDetails{
+			BlockCount:       p.BlockCount,
+			CensusOrigin:     p.CensusOrigin,
+			CensusRoot:       p.CensusRoot,
+			CensusUri:        p.CensusURI,
+			EndBlock:         p.EndBlock,
+			EntityId:         p.EntityID,
+			EnvelopeType:     p.EnvelopeType,
+			KeyIndex:         p.KeyIndex,
+			Mode:             p.Mode,
+			Namespace:        p.Namespace,
+			ProcessId:        p.ProcessID,
+			QuestionCount:    p.QuestionCount,
+			QuestionIndex:    p.QuestionIndex,
+			ResultsAvailable: p.ResultsAvailable,
+			StartBlock:       p.StartBlock,
+			Status:           p.Status,
+			VoteCount:        p.VoteCount,
+			VoteOptions:      p.VoteOptions,
+		}
+	}
+	if s := r.ProcessStats; s != nil {
+		m.ProcessStats = &pb.ProcessStats{
+			CensusSize:       s.CensusSize,
+			Envelopes:        s.Envelopes,
+			InvalidEnvelopes: s.InvalidEnvelopes,
+			TotalWeight:      s.TotalWeight,
+			Turnout:          s.Turnout,
+			VotesPerBlock:    s.VotesPerBlock,
+		}
+	}
+	for _, p := range r.Processes {
+		m.Processes = append(m.Processes, &pb.ProcessSummary{
+			Anonymous:      p.Anonymous,
+			BlockCount:     p.BlockCount,
+			CensusOrigin:   p.CensusOrigin,
+			EncryptedVotes: p.EncryptedVotes,
+			EndBlock:       p.EndBlock,
+			EntityId:       p.EntityID,
+			ProcessId:      p.ProcessID,
+			Serial:         p.Serial,
+			StartBlock:     p.StartBlock,
+			Status:         p.Status,
+			UniqueValues:   p.UniqueValues,
+		})
+	}
+	for _, s := range r.ResultsSeries {
+		m.ResultsSeries = append(m.ResultsSeries, &pb.ResultsSnapshot{
+			Height:  s.Height,
+			Results: pbQuestions(s.Results),
+		})
+	}
+	for _, root := range r.RootHistory {
+		m.RootHistory = append(m.RootHistory, &pb.CensusRoot{Root: root.Root, Timestamp: root.Timestamp})
+	}
+	if p := r.StateProof; p != nil {
+		m.StateProof = &pb.StateProof{
+			Height: p.Height,
+			Key:    p.Key,
+			Proof:  p.Proof,
+			Roots:  make(map[string][]byte, len(p.Roots)),
+			Tree:   p.Tree,
+			Value:  p.Value,
+		}
+		for name, root := range p.Roots {
+			m.StateProof.Roots[name] = root
+		}
+	}
+	return m
+}
+
+func pbQuestions(results [][]string) []*pb.Question {
+	var questions []*pb.Question
+	for _, options := range results {
+		questions = append(questions, &pb.Question{Options: options})
+	}
+	return questions
+}
+
+func pbKeys(keys []types.Key) []*pb.Key {
+	var pbkeys []*pb.Key
+	for _, k := range keys {
+		pbkeys = append(pbkeys, &pb.Key{Idx: int32(k.Idx), Key: k.Key})
+	}
+	return pbkeys
+}
+
+func hexBytesList(list [][]byte) []types.HexBytes {
+	var hl []types.HexBytes
+	for _, b := range list {
+		hl = append(hl, b)
+	}
+	return hl
+}
+
+func bytesList(list []types.HexBytes) [][]byte {
+	var bl [][]byte
+	for _, b := range list {
+		bl = append(bl, b)
+	}
+	return bl
+}
@@ -0,0 +1,172 @@
+package router
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vocdoni/multirpc/transports"
+	"github.com/vocdoni/multirpc/transports/mhttp"
+)
+
+// rateLimitSweepInterval is how often the idle buckets are removed
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit is the budget of a token bucket: Rate tokens are added every
+// second, up to Burst tokens
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter limits the requests of each client with token buckets. Every
+// remote peer and every authenticated address has a bucket for the methods
+// without their own budget, and a separate bucket for each method in Methods.
+type RateLimiter struct {
+	Default RateLimit
+	Methods map[string]RateLimit
+
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter returns a RateLimiter with the default budget and the per
+// method budgets defined in methods, formatted as method:rate:burst items
+// separated by commas.
+func NewRateLimiter(rate float64, burst int, methods string) (*RateLimiter, error) {
+	l := &RateLimiter{
+		Default: RateLimit{Rate: rate, Burst: burst},
+		Methods: make(map[string]RateLimit),
+		buckets: make(map[string]*bucket),
+	}
+	for _, item := range strings.Split(methods, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid method rate limit %q, expected method:rate:burst", item)
+		}
+		rate, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid method rate limit %q: %w", item, err)
+		}
+		burst, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid method rate limit %q: %w", item, err)
+		}
+		l.Methods[fields[0]] = RateLimit{Rate: rate, Burst: burst}
+	}
+	return l, nil
+}
+
+// Allow takes a token of the client bucket for the method. If there are no
+// tokens left it returns false and the time until the next one is available.
+func (l *RateLimiter) Allow(client, method string) (bool, time.Duration) {
+	limit, ok := l.Methods[method]
+	if !ok {
+		limit = l.Default
+		method = ""
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	key := client + "/" + method
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * limit.Rate
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep removes the buckets which have been idle long enough to be full
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		method := key[strings.LastIndex(key, "/")+1:]
+		limit, ok := l.Methods[method]
+		if !ok {
+			limit = l.Default
+		}
+		if limit.Rate <= 0 || b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// EnableRateLimit limits the requests of each client with the given limiter
+func (r *Router) EnableRateLimit(limiter *RateLimiter) {
+	r.limiter = limiter
+}
+
+// rateLimit checks the budget of the peer and, if the request is signed, of
+// the address of the signer
+func (r *Router) rateLimit(request routerRequest) error {
+	if r.limiter == nil {
+		return nil
+	}
+	clients := [][2]string{{"peer", request.peer}}
+	if request.address != (ethcommon.Address{}) {
+		clients = append(clients, [2]string{"address", request.address.Hex()})
+	}
+	for _, client := range clients {
+		if ok, wait := r.limiter.Allow(client[0]+":"+client[1], request.method); !ok {
+			if r.metricsagent != nil {
+				RouterRateLimited.With(prometheus.Labels{"method": request.method, "client": client[0]}).Inc()
+			}
+			return fmt.Errorf("rate limited: too many requests for %q, retry in %s",
+				request.method, wait.Round(time.Millisecond))
+		}
+	}
+	return nil
+}
+
+// peerOf returns the identifier of the remote peer of a message, its remote IP
+func peerOf(ctx transports.MessageContext) string {
+	switch c := ctx.(type) {
+	case *httpContext:
+		return remoteHost(c.request.RemoteAddr)
+	case *wsContext:
+		return c.peer
+	case *mhttp.HttpContext:
+		return remoteHost(c.Request.RemoteAddr)
+	case *restContext:
+		return c.peer
+	}
+	return ""
+}
+
+// remoteHost returns the host of a remote address
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,124 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vocdoni/multirpc/transports"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l, err := NewRateLimiter(1, 3, "genProof:10:1, getStats:0:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("peer:1.2.3.4", "getBlockHeight"); !ok {
+			t.Fatalf("request %d within the burst was limited", i)
+		}
+	}
+	ok, wait := l.Allow("peer:1.2.3.4", "getBlockHeight")
+	if ok || wait <= 0 || wait > time.Second {
+		t.Fatalf("request over the burst allowed %t, wait %s", ok, wait)
+	}
+	// the methods without their own budget share the default bucket
+	if ok, _ := l.Allow("peer:1.2.3.4", "getProcessList"); ok {
+		t.Errorf("method without its own budget has a separate bucket")
+	}
+	// each client and each method with its own budget have a bucket
+	if ok, _ := l.Allow("peer:5.6.7.8", "getBlockHeight"); !ok {
+		t.Errorf("another client was limited")
+	}
+	if ok, _ := l.Allow("peer:1.2.3.4", "genProof"); !ok {
+		t.Errorf("method with its own budget was limited")
+	}
+	if ok, _ := l.Allow("peer:1.2.3.4", "genProof"); ok {
+		t.Errorf("method over its own burst was allowed")
+	}
+	// a zero rate is not limited
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Allow("peer:1.2.3.4", "getStats"); !ok {
+			t.Fatalf("method without limit was limited")
+		}
+	}
+
+	// the tokens are added back at the rate, up to the burst
+	l.lock.Lock()
+	l.buckets["peer:1.2.3.4/"].last = time.Now().Add(-2 * time.Second)
+	l.lock.Unlock()
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("peer:1.2.3.4", "getBlockHeight"); !ok {
+			t.Fatalf("refilled request %d was limited", i)
+		}
+	}
+	if ok, _ := l.Allow("peer:1.2.3.4", "getBlockHeight"); ok {
+		t.Errorf("more tokens than the refilled ones were allowed")
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	l, err := NewRateLimiter(1, 10, "genProof:1:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Allow("peer:1.2.3.4", "getBlockHeight")
+	l.Allow("peer:5.6.7.8", "getBlockHeight")
+	l.Allow("peer:1.2.3.4", "genProof")
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	// the buckets idle long enough to be full are removed, the rest are kept
+	now := time.Now()
+	l.buckets["peer:1.2.3.4/"].last = now.Add(-time.Minute)
+	l.buckets["peer:1.2.3.4/genProof"].last = now.Add(-2 * time.Second)
+	l.sweep(now)
+	if _, ok := l.buckets["peer:1.2.3.4/"]; ok {
+		t.Errorf("idle bucket was not removed")
+	}
+	if _, ok := l.buckets["peer:1.2.3.4/genProof"]; ok {
+		t.Errorf("idle method bucket was not removed")
+	}
+	if _, ok := l.buckets["peer:5.6.7.8/"]; !ok {
+		t.Errorf("bucket in use was removed")
+	}
+	if !l.lastSweep.Equal(now) {
+		t.Errorf("last sweep time not updated")
+	}
+}
+
+func TestNewRateLimiterInvalid(t *testing.T) {
+	for _, methods := range []string{"genProof", "genProof:x:1", "genProof:1:x"} {
+		if _, err := NewRateLimiter(1, 1, methods); err == nil {
+			t.Errorf("invalid method limits %q accepted", methods)
+		}
+	}
+}
+
+func TestHTTPReadLimit(t *testing.T) {
+	receiver := make(chan transports.Message, 1)
+	tr := NewHTTPTransport(nil, true, false, 16)
+	tr.Listen(receiver)
+	handler := tr.handler("/dvote")
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/dvote", strings.NewReader(strings.Repeat("x", 17))))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("request over the limit got status %d", w.Code)
+	}
+	if len(receiver) != 0 {
+		t.Fatalf("request over the limit was received")
+	}
+
+	w = httptest.NewRecorder()
+	go func() {
+		msg := <-receiver
+		msg.Context.Send(transports.Message{Data: msg.Data})
+	}()
+	handler(w, httptest.NewRequest("POST", "/dvote", strings.NewReader(strings.Repeat("x", 16))))
+	if w.Code != http.StatusOK || w.Body.String() != strings.Repeat("x", 16)+"\n" {
+		t.Errorf("request within the limit got status %d and %q", w.Code, w.Body.String())
+	}
+}
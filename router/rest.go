@@ -0,0 +1,264 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vocdoni/multirpc/transports"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+const (
+	// restCacheMaxAge is the max-age in seconds of the successful GET replies
+	restCacheMaxAge = 10
+	// restMaxBodySize is the maximum size of a REST request body
+	restMaxBodySize = 1 << 20
+)
+
+// restRoute maps an HTTP method and path to an API method. Path parameters
+// are written between braces and use the JSON name of the MetaRequest field
+// they fill, like the query parameters and the JSON body do.
+type restRoute struct {
+	method      string
+	path        string
+	apiMethod   string
+	query       []string
+	description string
+}
+
+var restRoutes = []restRoute{
+	{"GET", "/info", "getInfo", nil, "Node information and enabled APIs"},
+	{"GET", "/blocks/height", "getBlockHeight", nil, "Current Vochain height"},
+	{"GET", "/blocks/status", "getBlockStatus", nil, "Block time statistics"},
+	{"GET", "/blocks/{height}/header", "getSignedHeader", nil, "Signed header of a block"},
+	{"GET", "/entities/{entityId}/processes", "getProcessList", []string{"fromId", "listSize"},
+		"Processes of an entity"},
+	{"GET", "/processes/count", "getProcessCount", nil, "Number of processes"},
+	{"GET", "/processes/{processId}", "getProcess", nil, "Process details"},
+	{"GET", "/processes/{processId}/keys", "getProcessKeys", nil, "Encryption keys of a process"},
+	{"GET", "/processes/{processId}/proof", "getProcessProof", nil, "State proof of a process"},
+	{"GET", "/processes/{processId}/envelopes", "getEnvelopeList", []string{"from", "listSize"},
+		"Nullifiers of the envelopes of a process"},
+	{"GET", "/processes/{processId}/envelopes/count", "getEnvelopeHeight", nil,
+		"Number of envelopes of a process"},
+	{"GET", "/processes/{processId}/results", "getResults", nil, "Results of a process"},
+	{"GET", "/processes/{processId}/results/series", "getResultsSeries", []string{"from", "to", "listSize"},
+		"Live results snapshots of a process"},
+	{"GET", "/processes/{processId}/results/document", "getResultsDocument", []string{"type"},
+		"Signed results document of a process"},
+	{"GET", "/processes/{processId}/stats", "getProcessStats", nil, "Participation statistics of a process"},
+	{"GET", "/envelopes/{processId}/{nullifier}", "getEnvelope", nil, "Envelope content"},
+	{"GET", "/envelopes/{processId}/{nullifier}/status", "getEnvelopeStatus", nil, "Envelope status"},
+	{"GET", "/envelopes/{processId}/{nullifier}/proof", "getEnvelopeProof", nil, "State proof of an envelope"},
+	{"POST", "/envelopes", "submitEnvelope", nil, "Submit a vote envelope"},
+	{"GET", "/census/{censusId}/root", "getRoot", nil, "Root of a census"},
+	{"GET", "/census/{censusId}/roots", "getRootHistory", nil, "Roots a census has had, oldest first"},
+	{"GET", "/census/{censusId}/size", "getSize", nil, "Size of a census"},
+	{"POST", "/census/{censusId}/proof", "genProof", nil, "Merkle proof of a census key"},
+	{"POST", "/census/proof/check", "checkProof", nil, "Check a census merkle proof"},
+	{"POST", "/census/{censusId}/proofs", "genProofBatch", nil, "Merkle proofs of a list of census keys"},
+	{"POST", "/census/proofs/check", "checkProofBatch", nil, "Check a list of census merkle proofs"},
+	{"GET", "/files", "fetchFile", []string{"uri"}, "Fetch a file from the storage"},
+}
+
+// restContext is the MessageContext of the REST requests. Handlers reply
+// synchronously, so the reply is kept to be written as the HTTP response.
+type restContext struct {
+	reply *transports.Message
+	peer  string
+}
+
+func (c *restContext) ConnectionType() string {
+	return "REST"
+}
+
+func (c *restContext) Send(msg transports.Message) error {
+	c.reply = &msg
+	return nil
+}
+
+// match returns the path parameters if the request path matches the route
+func (rt *restRoute) match(method string, path []string) (map[string]string, bool) {
+	if method != rt.method {
+		return nil, false
+	}
+	parts := strings.Split(strings.Trim(rt.path, "/"), "/")
+	if len(parts) != len(path) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") {
+			params[strings.Trim(part, "{}")] = path[i]
+		} else if part != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// RESTHandler returns the HTTP handler of the REST facade, to be served under
+// prefix. Only the public methods enabled in the router are available, and the
+// OpenAPI description is served at prefix/openapi.json.
+func (r *Router) RESTHandler(prefix string) http.HandlerFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	var routes []restRoute
+	for _, rt := range restRoutes {
+		if method, ok := r.methods[rt.apiMethod]; ok && method.public {
+			routes = append(routes, rt)
+		}
+	}
+	openAPI, err := json.Marshal(openAPIDocument(prefix, routes))
+	if err != nil {
+		// This should never happen
+		log.Fatal(err)
+	}
+	log.Infof("REST API available at %s", prefix)
+	return func(w http.ResponseWriter, req *http.Request) {
+		path := strings.Trim(strings.TrimPrefix(req.URL.Path, prefix), "/")
+		if path == "openapi.json" && req.Method == "GET" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(openAPI)
+			return
+		}
+		for i := range routes {
+			if params, ok := routes[i].match(req.Method, strings.Split(path, "/")); ok {
+				r.serveREST(w, req, &routes[i], params)
+				return
+			}
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (r *Router) serveREST(w http.ResponseWriter, req *http.Request, rt *restRoute, params map[string]string) {
+	for _, name := range rt.query {
+		if value := req.URL.Query().Get(name); value != "" {
+			params[name] = value
+		}
+	}
+	var body []byte
+	if req.Method == "POST" {
+		var err error
+		if body, err = ioutil.ReadAll(http.MaxBytesReader(w, req.Body, restMaxBodySize)); err != nil {
+			http.Error(w, fmt.Sprintf("cannot read body: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+	metaRequest, err := restMetaRequest(params, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	metaRequest.Method = rt.apiMethod
+	ctx := &restContext{peer: remoteHost(req.RemoteAddr)}
+	request := routerRequest{
+		MetaRequest:    *metaRequest,
+		MessageContext: ctx,
+		method:         rt.apiMethod,
+		id:             "rest",
+		authenticated:  true,
+		peer:           ctx.peer,
+	}
+	if err := r.rateLimit(request); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	r.countRequest(request)
+	if err := r.call(req.Context(), request, r.methods[rt.apiMethod]); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if ctx.reply == nil {
+		http.Error(w, "no response", http.StatusInternalServerError)
+		return
+	}
+
+	var respOuter types.ResponseMessage
+	if err := json.Unmarshal(ctx.reply.Data, &respOuter); err != nil {
+		// buildReply returns plain text on marshaling errors
+		http.Error(w, string(ctx.reply.Data), http.StatusInternalServerError)
+		return
+	}
+	var status struct {
+		Ok bool `json:"ok"`
+	}
+	if err := json.Unmarshal(respOuter.MetaResponse, &status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Signature", fmt.Sprintf("%x", respOuter.Signature))
+	if !status.Ok {
+		w.WriteHeader(http.StatusBadRequest)
+	} else if req.Method == "GET" {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", restCacheMaxAge))
+	}
+	w.Write(respOuter.MetaResponse)
+}
+
+// restMetaRequest builds the MetaRequest from the JSON body and the path and
+// query parameters, which are converted to the type of the matching field.
+func restMetaRequest(params map[string]string, body []byte) (*types.MetaRequest, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+	}
+	t := reflect.TypeOf(types.MetaRequest{})
+	for name, value := range params {
+		field, ok := metaField(t, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64:
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s: %q", name, value)
+			}
+			fields[name] = json.RawMessage(value)
+		case reflect.Bool:
+			if _, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %q", name, value)
+			}
+			fields[name] = json.RawMessage(value)
+		default:
+			quoted, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			fields[name] = quoted
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	metaRequest := new(types.MetaRequest)
+	if err := json.Unmarshal(data, metaRequest); err != nil {
+		return nil, err
+	}
+	return metaRequest, nil
+}
+
+// metaField returns the struct field whose JSON name is name
+func metaField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func jsonName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
@@ -2,9 +2,13 @@
 package router
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -111,6 +115,14 @@ type Router struct {
 	vocinfo      *vochaininfo.VochainInfo
 	allowPrivate bool
 	Scrutinizer  *scrutinizer.Scrutinizer
+	subs         *subscriptions
+	limiter      *RateLimiter
+	pool         *workerPool
+	acl          *ACL
+	audit        io.Writer
+	auditLock    sync.Mutex
+	// PrivateCalls and PublicCalls are updated atomically, since the requests
+	// of a batch are counted concurrently
 	PrivateCalls uint64
 	PublicCalls  uint64
 	APIs         []string
@@ -131,6 +143,7 @@ func NewRouter(inbound <-chan transports.Message, storage data.Storage,
 	if metricsagent != nil {
 		r.registerMetrics(metricsagent)
 	}
+	r.EnableWorkerPool(DefaultWorkers, DefaultQueueSize, DefaultRequestTimeout)
 	return r
 }
 
@@ -143,6 +156,10 @@ type routerRequest struct {
 	authenticated bool
 	address       ethcommon.Address
 	private       bool
+	peer          string
+	role          string
+	// ctx is canceled when the request times out
+	ctx context.Context
 }
 
 // semi-unmarshalls message, returns method name
@@ -153,36 +170,60 @@ func (r *Router) getRequest(payload []byte, context transports.MessageContext) (
 	if err := json.Unmarshal(payload, &reqOuter); err != nil {
 		return request, err
 	}
+	return r.parseRequest(&reqOuter, context)
+}
+
+// parseRequest unmarshals the signed request and authenticates it if the
+// method is private
+func (r *Router) parseRequest(reqOuter *types.RequestMessage, context transports.MessageContext) (request routerRequest, err error) {
 	request.id = reqOuter.ID
 	request.MessageContext = context
+	request.peer = peerOf(context)
 
 	var reqInner types.MetaRequest
 	if err := json.Unmarshal(reqOuter.MetaRequest, &reqInner); err != nil {
 		return request, err
 	}
+	err = r.authenticate(&request, reqInner, reqOuter.MetaRequest, reqOuter.Signature)
+	return request, err
+}
+
+// authenticate sets the method of the request and, if it is private, checks
+// that signature is a valid signature of the signed request by an allowed key
+func (r *Router) authenticate(request *routerRequest, reqInner types.MetaRequest,
+	signed, signature []byte) (err error) {
 	request.MetaRequest = reqInner
 	request.method = reqInner.Method
 	if request.method == "" {
-		return request, fmt.Errorf("method is empty")
+		return fmt.Errorf("method is empty")
 	}
 
 	method, ok := r.methods[request.method]
 	if !ok {
-		return request, fmt.Errorf("method not valid [%s]", request.method)
+		return fmt.Errorf("method not valid [%s]", request.method)
 	}
 	if method.public {
 		request.private = false
 		request.authenticated = true
 		request.address = ethcommon.Address{}
+	} else if r.acl != nil {
+		request.private = true
+		request.address, err = ethereum.AddrFromSignature(signed, signature)
+		if err != nil {
+			return err
+		}
+		if request.role, request.authenticated = r.acl.Allowed(request.address, request.method); !request.authenticated {
+			err = fmt.Errorf("address %s has no role allowed to call %q", request.address.Hex(), request.method)
+		}
 	} else {
 		request.private = true
-		request.authenticated, request.address, err = r.signer.VerifySender(reqOuter.MetaRequest, reqOuter.Signature)
+		request.authenticated, request.address, err = r.signer.VerifySender(signed, signature)
 		// if no authrized keys, authenticate all requests if allowPrivate=true
 		if r.allowPrivate && !request.authenticated && len(r.signer.Authorized) == 0 {
 			request.authenticated = true
 		}
 	}
-	return request, err
+	return err
 }
 
 // InitRouter sets up a Router object which can then be used to route requests
@@ -228,17 +269,27 @@ func (r *Router) EnableCensusAPI(cm *census.Manager) {
 		cm.RemoteStorage = r.storage
 	}
 	r.registerPublic("getRoot", r.censusLocal)
+	r.registerPublic("getRootHistory", r.censusLocal)
 	r.registerPrivate("dump", r.censusLocal)
 	r.registerPrivate("dumpPlain", r.censusLocal)
 	r.registerPublic("getSize", r.censusLocal)
 	r.registerPublic("genProof", r.censusLocal)
 	r.registerPublic("checkProof", r.censusLocal)
+	r.registerPublic("genProofBatch", r.censusLocal)
+	r.registerPublic("checkProofBatch", r.censusLocal)
 	r.registerPrivate("addCensus", r.censusLocal)
 	r.registerPrivate("addClaim", r.censusLocal)
 	r.registerPrivate("addClaimBulk", r.censusLocal)
+	r.registerPrivate("delClaim", r.censusLocal)
+	r.registerPrivate("delClaimBulk", r.censusLocal)
+	r.registerPrivate("updateClaim", r.censusLocal)
+	r.registerPrivate("updateClaimBulk", r.censusLocal)
 	r.registerPrivate("publish", r.censusLocal)
 	r.registerPrivate("importRemote", r.censusLocal)
 	r.registerPrivate("getCensusList", r.censusLocal)
+	r.registerPrivate("addCensusKeys", r.censusLocal)
+	r.registerPrivate("delCensusKeys", r.censusLocal)
+	r.registerPrivate("transferCensus", r.censusLocal)
 }
 
 // EnableVoteAPI enabled the Vote API in the Router
@@ -255,15 +306,29 @@ func (r *Router) EnableVoteAPI(vocapp *vochain.BaseApplication, vocInfo *vochain
 	r.registerPublic("getEnvelopeList", r.getEnvelopeList)
 	r.registerPublic("getBlockHeight", r.getBlockHeight)
 	r.registerPublic("getProcessKeys", r.getProcessKeys)
+	r.registerPublic("getProcess", r.getProcess)
 	r.registerPublic("getBlockStatus", r.getBlockStatus)
 	r.registerPublic("getProcessCount", r.getProcessCount)
+	r.registerPublic("getProcessProof", r.getProcessProof)
+	r.registerPublic("getEnvelopeProof", r.getEnvelopeProof)
+	r.registerPublic("getSignedHeader", r.getSignedHeader)
+	r.subs = newSubscriptions(r)
+	vocapp.State.AddEventListener(r.subs)
+	r.registerPublic("subscribe", r.subscribe)
+	r.registerPublic("unsubscribe", r.unsubscribe)
 	if r.Scrutinizer != nil {
 		r.APIs = append(r.APIs, "results")
 		r.registerPublic("getResults", r.getResults)
 		r.registerPublic("getProcListResults", r.getProcListResults)
 		r.registerPublic("getProcListLiveResults", r.getProcListLiveResults)
+		r.registerPublic("searchProcesses", r.searchProcesses)
+		r.registerPublic("getProcessStats", r.getProcessStats)
+		r.registerPublic("getResultsSeries", r.getResultsSeries)
+		r.registerPublic("getResultsDocument", r.getResultsDocument)
+		r.registerPrivate("publishResultsDocument", r.publishResultsDocument)
 		r.registerPublic("getScrutinizerEntities", r.getScrutinizerEntities)
 		r.registerPublic("getScrutinizerEntityCount", r.getScrutinizerEntityCount)
+		r.Scrutinizer.AddEventListener(r.subs)
 	}
 }
 
@@ -276,38 +341,56 @@ func (r *Router) Route() {
 	}
 	for {
 		msg := <-r.inbound
-		request, err := r.getRequest(msg.Data, msg.Context)
-		if !request.authenticated && err != nil {
-			go r.sendError(request, err.Error())
+		if isBatch(msg.Data) {
+			go r.routeBatch(msg)
 			continue
 		}
-		method, ok := r.methods[request.method]
-		if !ok {
-			errMsg := fmt.Sprintf("router has no method %q", request.method)
-			go r.sendError(request, errMsg)
-			continue
+		request, err := r.getRequest(msg.Data, msg.Context)
+		method, err := r.validateRequest(request, err)
+		if err == nil {
+			err = r.rateLimit(request)
 		}
-		if !method.public && !request.authenticated {
-			errMsg := fmt.Sprintf("authentication is required for %q", request.method)
-			go r.sendError(request, errMsg)
+		if err != nil {
+			go r.sendError(request, err.Error())
 			continue
 		}
-		log.Debugf("api query %s", request.MetaRequest.String())
+		r.countRequest(request)
+		r.dispatch(request, method)
+	}
+}
+
+// validateRequest checks that the method of a request returned by getRequest
+// exists and that the request is authorized to call it
+func (r *Router) validateRequest(request routerRequest, err error) (method registeredMethod, rerr error) {
+	defer func() { r.auditRequest(request, rerr) }()
+	if !request.authenticated && err != nil {
+		return registeredMethod{}, err
+	}
+	method, ok := r.methods[request.method]
+	if !ok {
+		return registeredMethod{}, fmt.Errorf("router has no method %q", request.method)
+	}
+	if !method.public && !request.authenticated {
+		return registeredMethod{}, fmt.Errorf("authentication is required for %q", request.method)
+	}
+	return method, nil
+}
+
+// countRequest updates the router call counters and metrics
+func (r *Router) countRequest(request routerRequest) {
+	log.Debugf("api query %s", request.MetaRequest.String())
+	if request.private {
+		atomic.AddUint64(&r.PrivateCalls, 1)
+	} else {
+		atomic.AddUint64(&r.PublicCalls, 1)
+	}
+
+	if r.metricsagent != nil {
 		if request.private {
-			r.PrivateCalls++
+			RouterPrivateReqs.With(prometheus.Labels{"method": request.method}).Inc()
 		} else {
-			r.PublicCalls++
-		}
-
-		if r.metricsagent != nil {
-			if request.private {
-				RouterPrivateReqs.With(prometheus.Labels{"method": request.method}).Inc()
-			} else {
-				RouterPublicReqs.With(prometheus.Labels{"method": request.method}).Inc()
-			}
+			RouterPublicReqs.With(prometheus.Labels{"method": request.method}).Inc()
 		}
-
-		go method.handler(request)
 	}
 }
 
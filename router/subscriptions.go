@@ -0,0 +1,368 @@
+package router
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.vocdoni.io/proto/build/go/models"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+)
+
+const (
+	// maxSubscriptionsPerConn is the maximum number of topics a single
+	// websocket connection can be subscribed to
+	maxSubscriptionsPerConn = 64
+	// subscriberQueueSize is the number of pushed messages waiting to be
+	// written to a connection. The subscriptions of a connection which falls
+	// behind are dropped.
+	subscriberQueueSize = 64
+)
+
+// subscriber is a connection subscribed to a topic. conn identifies the
+// connection, and send writes a pushed message to it.
+type subscriber struct {
+	conn interface{}
+	send func(*types.MetaResponse) error
+}
+
+// subscriptions keeps the connections subscribed to each topic and
+// pushes them the updates once the Vochain commits a block. The supported
+// topics are:
+//
+//	newBlock                      a new block has been committed
+//	results:{processId}           the results of the process have changed
+//	envelope:{processId}:{nullif} the envelope has been included in a block
+//	process:{entityId}            a process of the entity has been created or
+//	                              its status has changed
+type subscriptions struct {
+	router *Router
+
+	lock   sync.RWMutex
+	topics map[string]map[interface{}]*subscriber
+	conns  map[interface{}]int
+	// queues holds the messages waiting to be written to each connection
+	queues map[interface{}]chan func() error
+
+	// events received during the current block, cleared on Rollback
+	poolLock    sync.Mutex
+	votePool    []*models.Vote
+	processPool [][]byte
+	statusPool  [][]byte
+}
+
+func newSubscriptions(r *Router) *subscriptions {
+	return &subscriptions{
+		router: r,
+		topics: make(map[string]map[interface{}]*subscriber),
+		conns:  make(map[interface{}]int),
+		queues: make(map[interface{}]chan func() error),
+	}
+}
+
+// parseTopic validates a topic and returns it in its canonical form, with
+// lowercase hexadecimal identifiers without the 0x prefix
+func parseTopic(topic string) (string, error) {
+	parts := strings.Split(topic, ":")
+	// the valid sizes of each identifier of the topic
+	sizes := map[string][][]int{
+		"newBlock": nil,
+		"results":  {{types.ProcessIDsize}},
+		"envelope": {{types.ProcessIDsize}, {types.VoteNullifierSize}},
+		"process":  {{types.EntityIDsize, types.EntityIDsizeV2}},
+	}
+	expected, ok := sizes[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown topic %q", parts[0])
+	}
+	if len(parts)-1 != len(expected) {
+		return "", fmt.Errorf("malformed topic %q", topic)
+	}
+	for i, valid := range expected {
+		id, err := hex.DecodeString(util.TrimHex(parts[i+1]))
+		if err != nil || !validSize(len(id), valid) {
+			return "", fmt.Errorf("malformed topic %q", topic)
+		}
+		parts[i+1] = hex.EncodeToString(id)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+func validSize(size int, valid []int) bool {
+	for _, v := range valid {
+		if size == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *subscriptions) add(topic string, sub *subscriber) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.topics[topic][sub.conn]; ok {
+		return nil
+	}
+	if s.conns[sub.conn] >= maxSubscriptionsPerConn {
+		return fmt.Errorf("too many subscriptions")
+	}
+	if s.topics[topic] == nil {
+		s.topics[topic] = make(map[interface{}]*subscriber)
+	}
+	if s.conns[sub.conn] == 0 {
+		queue := make(chan func() error, subscriberQueueSize)
+		s.queues[sub.conn] = queue
+		go s.write(sub.conn, queue)
+	}
+	s.topics[topic][sub.conn] = sub
+	s.conns[sub.conn]++
+	return nil
+}
+
+// write sends the queued messages to a connection until its queue is closed.
+// If a message cannot be written the connection is considered closed and
+// unsubscribed.
+func (s *subscriptions) write(conn interface{}, queue chan func() error) {
+	for send := range queue {
+		if err := send(); err != nil {
+			log.Debugf("removing subscriptions of closed connection: (%s)", err)
+			s.delConn(conn)
+			// discard the messages queued until the queue is closed
+			for range queue {
+			}
+			return
+		}
+	}
+}
+
+func (s *subscriptions) del(topic string, conn interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.delLocked(topic, conn)
+}
+
+func (s *subscriptions) delLocked(topic string, conn interface{}) {
+	if _, ok := s.topics[topic][conn]; !ok {
+		return
+	}
+	delete(s.topics[topic], conn)
+	if len(s.topics[topic]) == 0 {
+		delete(s.topics, topic)
+	}
+	if s.conns[conn]--; s.conns[conn] <= 0 {
+		delete(s.conns, conn)
+		close(s.queues[conn])
+		delete(s.queues, conn)
+	}
+}
+
+// delConn removes all the subscriptions of a connection
+func (s *subscriptions) delConn(conn interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for topic := range s.topics {
+		s.delLocked(topic, conn)
+	}
+}
+
+// count returns the number of topics a connection is subscribed to
+func (s *subscriptions) count(conn interface{}) int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.conns[conn]
+}
+
+// subscribed returns true if there is some subscriber for the topic
+func (s *subscriptions) subscribed(topic string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.topics[topic]) > 0
+}
+
+// push queues the response for all the subscribers of the topic, so a slow
+// connection does not delay the rest. Connections whose queue is full are
+// unsubscribed.
+func (s *subscriptions) push(topic string, response *types.MetaResponse) {
+	response.Topic = topic
+	var slow []interface{}
+	s.lock.RLock()
+	for _, sub := range s.topics[topic] {
+		sub := sub
+		// each subscriber gets its own copy, as sending sets the request ID
+		// and the timestamp of the response
+		send := func() error {
+			r := *response
+			return sub.send(&r)
+		}
+		select {
+		case s.queues[sub.conn] <- send:
+		default:
+			slow = append(slow, sub.conn)
+		}
+	}
+	s.lock.RUnlock()
+	for _, conn := range slow {
+		log.Debugf("removing subscriptions of slow connection")
+		s.delConn(conn)
+	}
+}
+
+func (r *Router) subscribe(request routerRequest) {
+	ctx, ok := request.transportContext().(*wsContext)
+	if !ok {
+		r.sendError(request, "cannot subscribe: (a websocket connection is required)")
+		return
+	}
+	topic, err := parseTopic(request.Topic)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot subscribe: (%s)", err))
+		return
+	}
+	// The request ID of the subscribe call is used as the ID of the pushed messages
+	sub := &subscriber{conn: ctx.Conn, send: func(response *types.MetaResponse) error {
+		return ctx.Send(r.buildReply(routerRequest{id: request.id, MessageContext: ctx}, response))
+	}}
+	if err := r.subs.add(topic, sub); err != nil {
+		r.sendError(request, fmt.Sprintf("cannot subscribe: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.Topic = topic
+	request.Send(r.buildReply(request, &response))
+}
+
+func (r *Router) unsubscribe(request routerRequest) {
+	ctx, ok := request.transportContext().(*wsContext)
+	if !ok {
+		r.sendError(request, "cannot unsubscribe: (a websocket connection is required)")
+		return
+	}
+	topic, err := parseTopic(request.Topic)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot unsubscribe: (%s)", err))
+		return
+	}
+	r.subs.del(topic, ctx.Conn)
+	var response types.MetaResponse
+	response.Topic = topic
+	request.Send(r.buildReply(request, &response))
+}
+
+// Commit pushes the events of the committed block to the subscribers. It runs
+// after the scrutinizer has processed the block, so live results are updated.
+func (s *subscriptions) Commit(height int64) {
+	s.poolLock.Lock()
+	votes, processes, statuses := s.votePool, s.processPool, s.statusPool
+	s.votePool, s.processPool, s.statusPool = nil, nil, nil
+	s.poolLock.Unlock()
+	// don't block the block processing while writing to the connections
+	go s.notify(height, votes, processes, statuses)
+}
+
+func (s *subscriptions) notify(height int64, votes []*models.Vote, processes, statuses [][]byte) {
+	h := uint32(height)
+	s.push("newBlock", &types.MetaResponse{Height: &h})
+
+	results := make(map[string]bool)
+	for _, v := range votes {
+		topic := fmt.Sprintf("envelope:%x:%x", v.ProcessId, v.Nullifier)
+		if s.subscribed(topic) {
+			height := v.Height
+			s.push(topic, &types.MetaResponse{
+				ProcessID:  v.ProcessId,
+				Nullifier:  fmt.Sprintf("%x", v.Nullifier),
+				Registered: types.True,
+				Height:     &height,
+			})
+			// an envelope is only included once
+			s.lock.Lock()
+			for conn := range s.topics[topic] {
+				s.delLocked(topic, conn)
+			}
+			s.lock.Unlock()
+		}
+		results[string(v.ProcessId)] = true
+	}
+	for pid := range results {
+		if s.router.Scrutinizer == nil || !s.subscribed(fmt.Sprintf("results:%x", pid)) {
+			continue
+		}
+		vr, err := s.router.Scrutinizer.VoteResult([]byte(pid))
+		if err != nil {
+			// encrypted processes have no results until the keys are revealed
+			log.Debugf("cannot get results of process %x for subscribers: (%s)", pid, err)
+			continue
+		}
+		s.pushResults([]byte(pid), vr, false)
+	}
+	for _, pid := range append(processes, statuses...) {
+		p, err := s.router.vocapp.State.Process(pid, true)
+		if err != nil {
+			log.Warnf("cannot get process %x for subscribers: (%s)", pid, err)
+			continue
+		}
+		topic := fmt.Sprintf("process:%x", p.EntityId)
+		if !s.subscribed(topic) {
+			continue
+		}
+		s.push(topic, &types.MetaResponse{
+			EntityID:  fmt.Sprintf("%x", p.EntityId),
+			ProcessID: p.ProcessId,
+			State:     p.Status.String(),
+		})
+	}
+}
+
+// pushResults sends the results of a process to its subscribers
+func (s *subscriptions) pushResults(pid []byte, vr *models.ProcessResult, final bool) {
+	response := &types.MetaResponse{
+		ProcessID: pid,
+		Results:   s.router.Scrutinizer.GetFriendlyResults(vr),
+	}
+	if final {
+		response.Finished = types.True
+	}
+	s.push(fmt.Sprintf("results:%x", pid), response)
+}
+
+func (s *subscriptions) Rollback() {
+	s.poolLock.Lock()
+	defer s.poolLock.Unlock()
+	s.votePool = nil
+	s.processPool = nil
+	s.statusPool = nil
+}
+
+func (s *subscriptions) OnVote(v *models.Vote) {
+	s.poolLock.Lock()
+	defer s.poolLock.Unlock()
+	s.votePool = append(s.votePool, v)
+}
+
+func (s *subscriptions) OnProcess(pid, eid []byte, censusRoot, censusURI string) {
+	s.poolLock.Lock()
+	defer s.poolLock.Unlock()
+	s.processPool = append(s.processPool, pid)
+}
+
+func (s *subscriptions) OnProcessStatusChange(pid []byte, status models.ProcessStatus) {
+	s.poolLock.Lock()
+	defer s.poolLock.Unlock()
+	s.statusPool = append(s.statusPool, pid)
+}
+
+// OnComputeResults pushes the final results computed by the scrutinizer
+func (s *subscriptions) OnComputeResults(results *models.ProcessResult) {
+	if s.subscribed(fmt.Sprintf("results:%x", results.ProcessId)) {
+		go s.pushResults(results.ProcessId, results, true)
+	}
+}
+
+// NOT USED but required for implementing the interface
+func (s *subscriptions) OnCancel(pid []byte)                                        {}
+func (s *subscriptions) OnProcessKeys(pid []byte, encryptionPub, commitment string) {}
+func (s *subscriptions) OnRevealKeys(pid []byte, encryptionPriv, reveal string)     {}
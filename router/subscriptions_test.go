@@ -0,0 +1,74 @@
+package router
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/types"
+)
+
+func TestPushSubscribers(t *testing.T) {
+	signer := ethereum.NewSignKeys()
+	if err := signer.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	r := &Router{signer: signer}
+	subs := newSubscriptions(r)
+
+	// no more than fit in the queues, so no subscriber is dropped
+	const pushes = subscriberQueueSize
+	var wg sync.WaitGroup
+	wg.Add(2 * pushes)
+	errs := make(chan error, 2*pushes)
+	// both connections reply to each push at the same time
+	barriers := make([]sync.WaitGroup, pushes)
+	for i := range barriers {
+		barriers[i].Add(2)
+	}
+	for _, id := range []string{"conn1", "conn2"} {
+		id := id
+		pushed := 0
+		// the subscribe request ID is the ID of the pushed messages
+		send := func(response *types.MetaResponse) error {
+			defer wg.Done()
+			barriers[pushed].Done()
+			barriers[pushed].Wait()
+			pushed++
+			msg := r.buildReply(routerRequest{id: id}, response)
+			var outer types.ResponseMessage
+			if err := json.Unmarshal(msg.Data, &outer); err != nil {
+				errs <- err
+				return nil
+			}
+			var inner types.MetaResponse
+			if err := json.Unmarshal(outer.MetaResponse, &inner); err != nil {
+				errs <- err
+				return nil
+			}
+			if outer.ID != id || inner.Request != id {
+				t.Errorf("connection %s got a message for %s (%s)", id, inner.Request, outer.ID)
+			}
+			// the signature must be computed over this connection's ID
+			if addr, err := ethereum.AddrFromSignature(outer.MetaResponse, outer.Signature); err != nil {
+				errs <- err
+			} else if addr != signer.Address() {
+				t.Errorf("connection %s got a wrong signature", id)
+			}
+			return nil
+		}
+		if err := subs.add("newBlock", &subscriber{conn: id, send: send}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < pushes; i++ {
+		h := uint32(i)
+		subs.push("newBlock", &types.MetaResponse{Height: &h})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,190 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/vocdoni/multirpc/transports"
+	"github.com/vocdoni/multirpc/transports/mhttp"
+	"nhooyr.io/websocket"
+
+	"go.vocdoni.io/dvote/log"
+)
+
+// HTTPTransport is the HTTP and websocket transport of the API. It works as
+// the mhttp ones, but its message contexts keep the remote address of the
+// client, so websocket clients are rate limited by IP as HTTP ones are. The
+// mhttp websocket context has no remote address to take it from.
+type HTTPTransport struct {
+	proxy      *mhttp.Proxy
+	http       bool
+	websockets bool
+	// readLimit is the maximum size of a websocket message or an HTTP
+	// request body
+	readLimit int64
+	receiver  chan<- transports.Message
+}
+
+// NewHTTPTransport returns a transport serving the API namespaces on proxy
+// over HTTP, websockets or both, reading messages of up to readLimit bytes
+func NewHTTPTransport(proxy *mhttp.Proxy, http, websockets bool, readLimit int64) *HTTPTransport {
+	return &HTTPTransport{proxy: proxy, http: http, websockets: websockets, readLimit: readLimit}
+}
+
+// httpContext is the MessageContext of an HTTP request
+type httpContext struct {
+	writer  http.ResponseWriter
+	request *http.Request
+	sent    chan struct{}
+}
+
+func (c *httpContext) ConnectionType() string {
+	return "HTTP"
+}
+
+func (c *httpContext) Send(msg transports.Message) error {
+	defer close(c.sent)
+	if c.request.Context().Err() != nil {
+		// The connection was closed, so don't try to write to it.
+		return fmt.Errorf("connection is closed")
+	}
+	c.writer.Header().Set("Content-Length", fmt.Sprintf("%d", len(msg.Data)+1))
+	c.writer.Header().Set("Content-Type", "application/json")
+	if _, err := c.writer.Write(msg.Data); err != nil {
+		return err
+	}
+	// Ensure we end the response with a newline, to be nice.
+	_, err := c.writer.Write([]byte("\n"))
+	return err
+}
+
+// wsContext is the MessageContext of a websocket message, with the remote
+// address of the connection
+type wsContext struct {
+	mhttp.WebsocketContext
+	peer string
+}
+
+func (t *HTTPTransport) Init(c *transports.Connection) error {
+	if t.readLimit == 0 {
+		t.readLimit = 32768 // default by ws client
+	}
+	return nil
+}
+
+func (t *HTTPTransport) ConnectionType() string {
+	switch {
+	case t.http && t.websockets:
+		return "HTTPWS"
+	case t.websockets:
+		return "Websocket"
+	}
+	return "HTTP"
+}
+
+// Listen sets the channel where the received messages are written
+func (t *HTTPTransport) Listen(receiver chan<- transports.Message) {
+	t.receiver = receiver
+}
+
+func (t *HTTPTransport) Send(msg transports.Message) error {
+	return msg.Context.Send(msg)
+}
+
+func (t *HTTPTransport) SendUnicast(address string, msg transports.Message) error {
+	// HTTP is not p2p so SendUnicast makes the same of Send()
+	return t.Send(msg)
+}
+
+// AddNamespace serves the namespace path on the proxy
+func (t *HTTPTransport) AddNamespace(path string) error {
+	if t.proxy == nil {
+		return fmt.Errorf("no proxy set")
+	}
+	t.proxy.AddHandler(path, t.handler(path))
+	return nil
+}
+
+func (t *HTTPTransport) Address() string {
+	return ""
+}
+
+// SetBootnodes does nothing, there are no bootnodes on HTTP
+func (t *HTTPTransport) SetBootnodes(bootnodes []string) {}
+
+// AddPeer does nothing, there are no peers on HTTP
+func (t *HTTPTransport) AddPeer(peer string) error {
+	return nil
+}
+
+func (t *HTTPTransport) String() string {
+	return t.proxy.Addr.String()
+}
+
+func (t *HTTPTransport) handler(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			if !t.websockets {
+				http.Error(w, "websockets are not enabled", http.StatusBadRequest)
+				return
+			}
+			t.serveWebsocket(path, w, r)
+			return
+		}
+		if !t.http {
+			http.Error(w, "only websockets are enabled", http.StatusBadRequest)
+			return
+		}
+		t.serveHTTP(path, w, r)
+	}
+}
+
+func (t *HTTPTransport) serveHTTP(path string, w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, t.readLimit))
+	if err != nil {
+		log.Warnf("cannot read HTTP request: (%s)", err)
+		// the body is too large, or the connection is closed and the
+		// error is not written anyway
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	ctx := &httpContext{writer: w, request: r, sent: make(chan struct{})}
+	t.receiver <- transports.Message{
+		Data:      body,
+		TimeStamp: int32(time.Now().Unix()),
+		Context:   ctx,
+		Namespace: path,
+	}
+	// The contract is that every handled request must send a
+	// response, even when they fail or time out.
+	<-ctx.sent
+}
+
+// serveWebsocket reads the messages of a websocket connection until it is
+// closed
+func (t *HTTPTransport) serveWebsocket(path string, w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: []string{"*"}})
+	if err != nil {
+		log.Errorf("failed to set websocket upgrade: %s", err)
+		return
+	}
+	conn.SetReadLimit(t.readLimit)
+	peer := remoteHost(r.RemoteAddr)
+	for {
+		_, payload, err := conn.Read(context.TODO())
+		if err != nil {
+			conn.Close(websocket.StatusAbnormalClosure, "ws closed by client")
+			return
+		}
+		t.receiver <- transports.Message{
+			Data:      payload,
+			TimeStamp: int32(time.Now().Unix()),
+			Context:   &wsContext{WebsocketContext: mhttp.WebsocketContext{Conn: conn}, peer: peer},
+			Namespace: path,
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package router
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"go.vocdoni.io/dvote/log"
 	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/vochain"
 	"go.vocdoni.io/dvote/vochain/scrutinizer"
 	models "go.vocdoni.io/proto/build/go/models"
 	"google.golang.org/protobuf/proto"
@@ -14,6 +19,10 @@ import (
 const MaxListSize = 256
 const MaxListIterations = int64(64)
 
+// commitTimeout is the maximum time submitEnvelope waits for the envelope to
+// be included in a block if waitForCommit is set
+const commitTimeout = time.Minute
+
 func (r *Router) submitRawTx(request routerRequest) {
 	res, err := r.vocapp.SendTX(request.Payload)
 	if err != nil {
@@ -35,46 +44,103 @@ func (r *Router) submitRawTx(request routerRequest) {
 }
 
 func (r *Router) submitEnvelope(request routerRequest) {
-	var err error
 	if request.Payload == nil {
 		r.sendError(request, "payload is empty")
 		return
 	}
-	// Decode vote envelope
-	tx := &models.VoteEnvelope{}
-	if err := proto.Unmarshal(request.Payload, tx); err != nil {
-		r.sendError(request, fmt.Sprintf("cannot unmarshal payload: (%s)", err))
+	if request.WaitForCommit {
+		r.submitEnvelopeCommit(request)
 		return
 	}
-
-	// Prepare Vote transaction
-	vtx := models.Tx{
-		Payload:   &models.Tx_Vote{Vote: tx},
-		Signature: request.Signature,
+	nullifier, err := r.broadcastEnvelope(request.Payload, request.Signature)
+	if err != nil {
+		r.sendError(request, err.Error())
+		return
 	}
+	var response types.MetaResponse
+	response.Nullifier = fmt.Sprintf("%x", nullifier)
+	request.Send(r.buildReply(request, &response))
+}
 
-	// Encode and forward the transaction to the Vochain mempool
-	txBytes, err := proto.Marshal(&vtx)
+// submitEnvelopeCommit sends a vote envelope and replies once it is included
+// in a block, with its height and timestamp. If the transaction fails on
+// DeliverTx, Registered is false and Message holds the error.
+func (r *Router) submitEnvelopeCommit(request routerRequest) {
+	txBytes, err := voteTx(request.Payload, request.Signature)
 	if err != nil {
-		r.sendError(request, fmt.Sprintf("cannot marshal vote transaction: (%s)", err))
+		r.sendError(request, err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(request.reqContext(), commitTimeout)
+	defer cancel()
+	res, err := r.vocapp.SendTxCommit(ctx, txBytes)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot broadcast transaction: (%s)", err))
+		return
+	}
+	if res.CheckTx.Code != 0 {
+		r.sendError(request, string(res.CheckTx.Data))
 		return
 	}
+	log.Infof("vochain tx hash:%s committed at height %d code:%d", res.Hash, res.Height, res.DeliverTx.Code)
+	var response types.MetaResponse
+	response.Nullifier = fmt.Sprintf("%x", res.CheckTx.Data)
+	height := uint32(res.Height)
+	response.Height = &height
+	if block := r.vocapp.Node.BlockStore().LoadBlockMeta(res.Height); block != nil {
+		response.BlockTimestamp = int32(block.Header.Time.Unix())
+	}
+	response.Registered = types.True
+	if res.DeliverTx.Code != 0 {
+		response.Registered = types.False
+		response.Message = string(res.DeliverTx.Data)
+		if response.Message == "" {
+			response.Message = res.DeliverTx.Log
+		}
+	}
+	request.Send(r.buildReply(request, &response))
+}
+
+// broadcastEnvelope sends a vote envelope transaction to the Vochain mempool
+// and returns its nullifier
+func (r *Router) broadcastEnvelope(payload, signature []byte) ([]byte, error) {
+	txBytes, err := voteTx(payload, signature)
+	if err != nil {
+		return nil, err
+	}
 
+	// Forward the transaction to the Vochain mempool
 	res, err := r.vocapp.SendTX(txBytes)
 	if err != nil || res == nil {
-		r.sendError(request, fmt.Sprintf("cannot broadcast transaction: (%s)", err))
-		return
+		return nil, fmt.Errorf("cannot broadcast transaction: (%s)", err)
 	}
 
 	// Get mempool checkTx reply
 	if res.Code != 0 {
-		r.sendError(request, string(res.Data))
-		return
+		return nil, fmt.Errorf("%s", res.Data)
 	}
 	log.Infof("broadcasting vochain tx hash:%s code:%d", res.Hash, res.Code)
-	var response types.MetaResponse
-	response.Nullifier = fmt.Sprintf("%x", res.Data)
-	request.Send(r.buildReply(request, &response))
+	return res.Data, nil
+}
+
+// voteTx encodes a vote transaction with a serialized vote envelope
+func voteTx(payload, signature []byte) ([]byte, error) {
+	// Decode vote envelope
+	tx := &models.VoteEnvelope{}
+	if err := proto.Unmarshal(payload, tx); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal payload: (%s)", err)
+	}
+
+	// Prepare Vote transaction
+	vtx := models.Tx{
+		Payload:   &models.Tx_Vote{Vote: tx},
+		Signature: signature,
+	}
+	txBytes, err := proto.Marshal(&vtx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal vote transaction: (%s)", err)
+	}
+	return txBytes, nil
 }
 
 func (r *Router) getEnvelopeStatus(request routerRequest) {
@@ -93,22 +159,35 @@ func (r *Router) getEnvelopeStatus(request routerRequest) {
 	var response types.MetaResponse
 	response.Registered = types.False
 
-	e, err := r.vocapp.State.Envelope(request.ProcessID, request.Nullifier, true)
-	// Warning, error is ignored. We should find a better way to check the envelope status
-	if err == nil && e != nil {
+	e, timestamp, err := r.envelopeStatus(request.ProcessID, request.Nullifier)
+	if err != nil {
+		r.sendError(request, err.Error())
+		return
+	}
+	if e != nil {
 		response.Registered = types.True
 		response.Nullifier = fmt.Sprintf("%x", e.Nullifier)
 		response.Height = &e.Height
-		block := r.vocapp.Node.BlockStore().LoadBlock(int64(e.Height))
-		if block == nil {
-			r.sendError(request, "failed getting envelope block timestamp")
-			return
-		}
-		response.BlockTimestamp = int32(block.Time.Unix())
+		response.BlockTimestamp = timestamp
 	}
 	request.Send(r.buildReply(request, &response))
 }
 
+// envelopeStatus returns the envelope and the timestamp of the block which
+// includes it, or a nil envelope if it is not registered yet
+func (r *Router) envelopeStatus(pid, nullifier []byte) (*models.Vote, int32, error) {
+	e, err := r.vocapp.State.Envelope(pid, nullifier, true)
+	// Warning, error is ignored. We should find a better way to check the envelope status
+	if err != nil || e == nil {
+		return nil, 0, nil
+	}
+	block := r.vocapp.Node.BlockStore().LoadBlock(int64(e.Height))
+	if block == nil {
+		return nil, 0, fmt.Errorf("failed getting envelope block timestamp")
+	}
+	return e, int32(block.Time.Unix()), nil
+}
+
 func (r *Router) getEnvelope(request routerRequest) {
 	// check pid
 	if len(request.ProcessID) != types.ProcessIDsize {
@@ -244,6 +323,47 @@ func (r *Router) getProcessKeys(request routerRequest) {
 	request.Send(r.buildReply(request, &response))
 }
 
+func (r *Router) getProcess(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get process: (malformed processId)")
+		return
+	}
+	p, err := r.vocapp.State.Process(request.ProcessID, true)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get process: (%s)", err))
+		return
+	}
+	resultsAvailable := p.Results != nil
+	if r.Scrutinizer != nil && !resultsAvailable {
+		if resultsAvailable, err = r.Scrutinizer.ResultsAvailable(request.ProcessID); err != nil {
+			r.sendError(request, fmt.Sprintf("cannot get process: (%s)", err))
+			return
+		}
+	}
+	var response types.MetaResponse
+	response.Process = &types.ProcessDetails{
+		BlockCount:       p.BlockCount,
+		CensusOrigin:     p.CensusOrigin.String(),
+		CensusRoot:       p.CensusRoot,
+		CensusURI:        p.GetCensusURI(),
+		EndBlock:         p.StartBlock + p.BlockCount,
+		EntityID:         p.EntityId,
+		EnvelopeType:     p.EnvelopeType,
+		KeyIndex:         p.GetKeyIndex(),
+		Mode:             p.Mode,
+		Namespace:        p.Namespace,
+		ProcessID:        p.ProcessId,
+		QuestionCount:    p.GetQuestionCount(),
+		QuestionIndex:    p.GetQuestionIndex(),
+		ResultsAvailable: resultsAvailable,
+		StartBlock:       p.StartBlock,
+		Status:           p.Status.String(),
+		VoteCount:        r.vocapp.State.CountVotes(request.ProcessID, true),
+		VoteOptions:      p.VoteOptions,
+	}
+	request.Send(r.buildReply(request, &response))
+}
+
 func (r *Router) getEnvelopeList(request routerRequest) {
 	// check pid
 	if len(request.ProcessID) != types.ProcessIDsize {
@@ -345,6 +465,226 @@ func (r *Router) getProcListLiveResults(request routerRequest) {
 	request.Send(r.buildReply(request, &response))
 }
 
+func (r *Router) getProcessStats(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get process stats: (malformed processId)")
+		return
+	}
+	process, err := r.Scrutinizer.ProcessInfo(request.ProcessID)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get process stats: (%s)", err))
+		return
+	}
+	stats, err := r.Scrutinizer.ProcessStats(request.ProcessID)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get process stats: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.ProcessStats = &types.ProcessStats{
+		Envelopes:        stats.Envelopes,
+		InvalidEnvelopes: stats.InvalidEnvelopes,
+		TotalWeight:      stats.TotalWeight.String(),
+		VotesPerBlock:    stats.VotesPerBlock,
+	}
+	if size, ok := r.censusSize(process.CensusRoot); ok {
+		turnout := stats.Turnout(size)
+		response.ProcessStats.CensusSize = &size
+		response.ProcessStats.Turnout = &turnout
+	}
+	request.Send(r.buildReply(request, &response))
+}
+
+// censusSize returns the size of the census identified by root, if it is
+// available on the local census manager
+func (r *Router) censusSize(root []byte) (int64, bool) {
+	if r.census == nil || len(root) == 0 {
+		return 0, false
+	}
+	name := hex.EncodeToString(root)
+	tr, err := r.census.AcquireTree(name)
+	if err != nil {
+		return 0, false
+	}
+	defer r.census.ReleaseTree(name)
+	size, err := tr.Size(tr.Root())
+	if err != nil {
+		log.Warnf("cannot get census %x size: (%s)", root, err)
+		return 0, false
+	}
+	return size, true
+}
+
+// getResultsSeries returns the live results snapshots of a process between
+// the heights From and To
+func (r *Router) getResultsSeries(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get results series: (malformed processId)")
+		return
+	}
+	if request.From < 0 || request.To < 0 || (request.To > 0 && request.To < request.From) {
+		r.sendError(request, "cannot get results series: (invalid height range)")
+		return
+	}
+	if request.ListSize > MaxListSize {
+		r.sendError(request, fmt.Sprintf("listSize overflow, maximum is %d", MaxListSize))
+		return
+	}
+	if request.ListSize == 0 {
+		request.ListSize = MaxListSize
+	}
+	series, err := r.Scrutinizer.ResultsSeries(request.ProcessID,
+		uint32(request.From), uint32(request.To), int(request.ListSize))
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get results series: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.ResultsSeries = make([]*types.ResultsSnapshot, len(series))
+	for i, snapshot := range series {
+		response.ResultsSeries[i] = &types.ResultsSnapshot{
+			Height:  snapshot.Height,
+			Results: r.Scrutinizer.GetFriendlyResults(snapshot.Results),
+		}
+	}
+	request.Send(r.buildReply(request, &response))
+}
+
+// getResultsDocument returns the results document of a process, in JSON or CSV
+// format, along with the signature of the node
+func (r *Router) getResultsDocument(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get results document: (malformed processId)")
+		return
+	}
+	format := request.Type
+	if format == "" {
+		format = scrutinizer.DocumentJSON
+	}
+	doc, err := r.Scrutinizer.ResultsDocument(request.ProcessID)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get results document: (%s)", err))
+		return
+	}
+	content, signature, err := doc.Sign(r.signer, format)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get results document: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.Content = content
+	response.DocumentSignature = signature
+	request.Send(r.buildReply(request, &response))
+}
+
+// publishResultsDocument publishes the signed results document of a process to
+// the storage and returns its URI
+func (r *Router) publishResultsDocument(request routerRequest) {
+	if r.storage == nil {
+		r.sendError(request, "cannot publish results document: (storage not available)")
+		return
+	}
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot publish results document: (malformed processId)")
+		return
+	}
+	doc, err := r.Scrutinizer.ResultsDocument(request.ProcessID)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot publish results document: (%s)", err))
+		return
+	}
+	signed, err := doc.Signed(r.signer)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot publish results document: (%s)", err))
+		return
+	}
+	content, err := json.Marshal(signed)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot publish results document: (%s)", err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(request.reqContext(), storageTimeout)
+	defer cancel()
+	cid, err := r.storage.Publish(ctx, content)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot publish results document: (%s)", err))
+		return
+	}
+	log.Infof("published results document for process %x: %s", request.ProcessID, cid)
+	var response types.MetaResponse
+	response.DocumentSignature = signed.Signature
+	response.URI = r.storage.URIprefix() + cid
+	request.Send(r.buildReply(request, &response))
+}
+
+// search processes by status, census origin, envelope type, block range and entity
+func (r *Router) searchProcesses(request routerRequest) {
+	if len(request.EntityId) > 0 && len(request.EntityId) != types.EntityIDsize &&
+		len(request.EntityId) != types.EntityIDsizeV2 {
+		r.sendError(request, "cannot search processes: (malformed entityId)")
+		return
+	}
+	if len(request.FromID) > 0 && len(request.FromID) != types.ProcessIDsize {
+		r.sendError(request, "cannot search processes: (malformed fromId)")
+		return
+	}
+	filter := &scrutinizer.ProcessFilter{EntityID: request.EntityId}
+	if f := request.SearchFilter; f != nil {
+		if f.Status != "" {
+			status, ok := models.ProcessStatus_value[f.Status]
+			if !ok {
+				r.sendError(request, fmt.Sprintf("cannot search processes: (unknown status %s)", f.Status))
+				return
+			}
+			filter.Status = models.ProcessStatus(status)
+		}
+		if f.CensusOrigin != "" {
+			origin, ok := models.CensusOrigin_value[f.CensusOrigin]
+			if !ok {
+				r.sendError(request, fmt.Sprintf("cannot search processes: (unknown census origin %s)", f.CensusOrigin))
+				return
+			}
+			filter.CensusOrigin = models.CensusOrigin(origin)
+		}
+		filter.Serial = f.Serial
+		filter.Anonymous = f.Anonymous
+		filter.EncryptedVotes = f.EncryptedVotes
+		filter.UniqueValues = f.UniqueValues
+		filter.StartBlockFrom = f.StartBlockFrom
+		filter.StartBlockTo = f.StartBlockTo
+		filter.EndBlockFrom = f.EndBlockFrom
+		filter.EndBlockTo = f.EndBlockTo
+	}
+	if request.ListSize > MaxListIterations || request.ListSize <= 0 {
+		request.ListSize = MaxListIterations
+	}
+	processes, err := r.Scrutinizer.SearchProcesses(filter, request.FromID, request.ListSize)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot search processes: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	for _, p := range processes {
+		et := p.GetEnvelopeType()
+		response.Processes = append(response.Processes, &types.ProcessSummary{
+			Anonymous:      et.GetAnonymous(),
+			BlockCount:     p.BlockCount,
+			CensusOrigin:   p.CensusOrigin.String(),
+			EncryptedVotes: et.GetEncryptedVotes(),
+			EndBlock:       p.StartBlock + p.BlockCount,
+			EntityID:       p.EntityId,
+			ProcessID:      p.ProcessId,
+			Serial:         et.GetSerial(),
+			StartBlock:     p.StartBlock,
+			Status:         p.Status.String(),
+			UniqueValues:   et.GetUniqueValues(),
+		})
+	}
+	response.Size = new(int64)
+	*response.Size = int64(len(response.Processes))
+	request.Send(r.buildReply(request, &response))
+}
+
 // known entities
 func (r *Router) getScrutinizerEntities(request routerRequest) {
 	var response types.MetaResponse
@@ -363,3 +703,70 @@ func (r *Router) getBlockStatus(request routerRequest) {
 	response.BlockTimestamp = int32(r.vocapp.State.Header(true).Timestamp)
 	request.Send(r.buildReply(request, &response))
 }
+
+func (r *Router) getProcessProof(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get process proof: (malformed processId)")
+		return
+	}
+	sp, err := r.vocapp.State.ProcessProof(request.ProcessID)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get process proof: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.StateProof = stateProofToAPI(sp)
+	request.Send(r.buildReply(request, &response))
+}
+
+func (r *Router) getEnvelopeProof(request routerRequest) {
+	if len(request.ProcessID) != types.ProcessIDsize {
+		r.sendError(request, "cannot get envelope proof: (malformed processId)")
+		return
+	}
+	if len(request.Nullifier) != types.VoteNullifierSize {
+		r.sendError(request, "cannot get envelope proof: (malformed nullifier)")
+		return
+	}
+	sp, err := r.vocapp.State.EnvelopeProof(request.ProcessID, request.Nullifier)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get envelope proof: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	response.StateProof = stateProofToAPI(sp)
+	request.Send(r.buildReply(request, &response))
+}
+
+func (r *Router) getSignedHeader(request routerRequest) {
+	if request.Height <= 0 {
+		r.sendError(request, "cannot get signed header: (invalid height)")
+		return
+	}
+	sh, err := r.vocapp.SignedHeader(request.Height)
+	if err != nil {
+		r.sendError(request, fmt.Sprintf("cannot get signed header: (%s)", err))
+		return
+	}
+	var response types.MetaResponse
+	if response.SignedHeader, err = sh.ToProto().Marshal(); err != nil {
+		r.sendError(request, fmt.Sprintf("cannot marshal signed header: (%s)", err))
+		return
+	}
+	request.Send(r.buildReply(request, &response))
+}
+
+func stateProofToAPI(sp *vochain.StateProof) *types.StateProof {
+	roots := make(map[string]types.HexBytes, len(sp.Roots))
+	for name, root := range sp.Roots {
+		roots[name] = root
+	}
+	return &types.StateProof{
+		Height: sp.Height,
+		Key:    sp.Key,
+		Proof:  sp.Proof,
+		Roots:  roots,
+		Tree:   sp.Tree,
+		Value:  sp.Value,
+	}
+}
@@ -0,0 +1,326 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vocdoni/multirpc/transports"
+
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// DefaultWorkers is the default number of requests handled concurrently
+	DefaultWorkers = 64
+	// DefaultQueueSize is the default number of requests waiting for a worker
+	DefaultQueueSize = 1024
+	// DefaultRequestTimeout is the default time a request may wait and run.
+	// A handler still running then is replied with a timeout error, and it
+	// stops holding a worker.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// Priority classes of the API methods. Queued requests of a higher class are
+// always handled before the ones of a lower class.
+const (
+	PriorityHigh = iota
+	PriorityNormal
+	PriorityLow
+	priorityClasses
+)
+
+var priorityNames = [priorityClasses]string{"high", "normal", "low"}
+
+// methodClass defines how the requests of a method are scheduled
+type methodClass struct {
+	priority int
+	// concurrency is the maximum number of requests of the method running at
+	// the same time, 0 means only the number of workers limits them
+	concurrency int
+	// timeout overrides the default request timeout of the pool
+	timeout time.Duration
+}
+
+// methodClasses are the scheduling classes of the methods which are not
+// PriorityNormal without limits. Votes go first, while the slow methods which
+// fetch or dump large data can only take a few workers. submitEnvelopeCommit
+// is submitEnvelope with waitForCommit, see classOf.
+var methodClasses = map[string]methodClass{
+	"submitEnvelope":         {priority: PriorityHigh},
+	"submitEnvelopeCommit":   {priority: PriorityHigh, concurrency: 16, timeout: commitTimeout},
+	"submitRawTx":            {priority: PriorityHigh},
+	"getEnvelopeStatus":      {priority: PriorityHigh},
+	"getBlockHeight":         {priority: PriorityHigh},
+	"getInfo":                {priority: PriorityHigh},
+	"fetchFile":              {priority: PriorityLow, concurrency: 8, timeout: storageTimeout},
+	"addFile":                {priority: PriorityLow, concurrency: 4, timeout: storageTimeout},
+	"pinList":                {priority: PriorityLow, concurrency: 2, timeout: storageTimeout},
+	"pinFile":                {priority: PriorityLow, concurrency: 4, timeout: storageTimeout},
+	"dump":                   {priority: PriorityLow, concurrency: 2, timeout: time.Minute},
+	"dumpPlain":              {priority: PriorityLow, concurrency: 2, timeout: time.Minute},
+	"importRemote":           {priority: PriorityLow, concurrency: 2, timeout: time.Minute},
+	"publish":                {priority: PriorityLow, concurrency: 2, timeout: time.Minute},
+	"addClaimBulk":           {priority: PriorityLow, concurrency: 4, timeout: time.Minute},
+	"delClaimBulk":           {priority: PriorityLow, concurrency: 4, timeout: time.Minute},
+	"updateClaimBulk":        {priority: PriorityLow, concurrency: 4, timeout: time.Minute},
+	"genProofBatch":          {priority: PriorityLow, concurrency: 8},
+	"checkProofBatch":        {priority: PriorityLow, concurrency: 8},
+	"searchProcesses":        {priority: PriorityLow, concurrency: 8},
+	"getResultsSeries":       {priority: PriorityLow, concurrency: 8},
+	"publishResultsDocument": {priority: PriorityLow, concurrency: 2, timeout: storageTimeout},
+}
+
+// job is a request waiting for a worker
+type job struct {
+	request routerRequest
+	method  registeredMethod
+	// name is the class name the running requests are counted under
+	name   string
+	class  methodClass
+	cancel context.CancelFunc
+	queued time.Time
+	// done is closed once the request is replied, by the handler or with a
+	// timeout error
+	done chan struct{}
+}
+
+// replyOnce is the MessageContext of a request handled by the pool. Only its
+// first reply is sent, either the handler one or the timeout error.
+type replyOnce struct {
+	transports.MessageContext
+	lock sync.Mutex
+	sent bool
+}
+
+func (c *replyOnce) Send(msg transports.Message) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.sent {
+		return fmt.Errorf("request already replied")
+	}
+	c.sent = true
+	return c.MessageContext.Send(msg)
+}
+
+// transportContext returns the MessageContext of the transport the request
+// arrived from
+func (request routerRequest) transportContext() transports.MessageContext {
+	if c, ok := request.MessageContext.(*replyOnce); ok {
+		return c.MessageContext
+	}
+	return request.MessageContext
+}
+
+// workerPool runs the API handlers in a bounded number of goroutines. Queued
+// requests are taken by priority and, within the same class, in arrival
+// order, skipping the methods which already reached their concurrency limit.
+type workerPool struct {
+	workers   int
+	queueSize int
+	timeout   time.Duration
+	metrics   bool
+	// expired replies to the requests which time out while queued or running
+	expired func(routerRequest, error)
+
+	once    sync.Once
+	lock    sync.Mutex
+	cond    *sync.Cond
+	queues  [priorityClasses][]*job
+	queued  int
+	running map[string]int
+}
+
+func newWorkerPool(workers, queueSize int, timeout time.Duration, metrics bool,
+	expired func(routerRequest, error)) *workerPool {
+	p := &workerPool{
+		workers:   workers,
+		queueSize: queueSize,
+		timeout:   timeout,
+		metrics:   metrics,
+		expired:   expired,
+		running:   make(map[string]int),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	return p
+}
+
+// EnableWorkerPool sets the number of workers, the maximum number of queued
+// requests and the default timeout of the requests, which are DefaultWorkers,
+// DefaultQueueSize and DefaultRequestTimeout otherwise. It must be called
+// before Route.
+func (r *Router) EnableWorkerPool(workers, queueSize int, timeout time.Duration) {
+	r.pool = newWorkerPool(workers, queueSize, timeout, r.metricsagent != nil, r.sendExpired)
+}
+
+// sendExpired replies to a request which expired before its handler replied
+func (r *Router) sendExpired(request routerRequest, err error) {
+	r.sendError(request, fmt.Sprintf("request not handled in time: (%s)", err))
+}
+
+func (p *workerPool) start() {
+	log.Infof("starting %d API workers, queue size %d", p.workers, p.queueSize)
+	for i := 0; i < p.workers; i++ {
+		go p.work()
+	}
+}
+
+// classOf returns the name and the scheduling class of a request. Waiting
+// for an envelope to be committed holds a worker for up to commitTimeout, so
+// those requests have their own class with a limited concurrency.
+func classOf(request routerRequest) (string, methodClass) {
+	name := request.method
+	if name == "submitEnvelope" && request.WaitForCommit {
+		name = "submitEnvelopeCommit"
+	}
+	class, ok := methodClasses[name]
+	if !ok {
+		class = methodClass{priority: PriorityNormal}
+	}
+	return name, class
+}
+
+// submit queues a request. The request context is derived from parent and
+// expires after the method timeout, including the time spent in the queue.
+func (p *workerPool) submit(parent context.Context, request routerRequest, method registeredMethod) (*job, error) {
+	p.once.Do(p.start)
+	name, class := classOf(request)
+	timeout := p.timeout
+	if class.timeout > 0 {
+		timeout = class.timeout
+	}
+	j := &job{
+		request: request,
+		method:  method,
+		name:    name,
+		class:   class,
+		queued:  time.Now(),
+		done:    make(chan struct{}),
+	}
+	j.request.ctx, j.cancel = context.WithTimeout(parent, timeout)
+	j.request.MessageContext = &replyOnce{MessageContext: request.MessageContext}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.queued >= p.queueSize {
+		j.cancel()
+		if p.metrics {
+			RouterRejectedReqs.With(prometheus.Labels{"method": request.method}).Inc()
+		}
+		return nil, fmt.Errorf("server busy: too many pending requests")
+	}
+	p.queues[class.priority] = append(p.queues[class.priority], j)
+	p.queued++
+	if p.metrics {
+		RouterQueueDepth.With(prometheus.Labels{"priority": priorityNames[class.priority]}).Inc()
+	}
+	p.cond.Signal()
+	return j, nil
+}
+
+// next waits for a queued request which can run and removes it from the queue
+func (p *workerPool) next() *job {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for {
+		for priority := range p.queues {
+			for i, j := range p.queues[priority] {
+				if j.class.concurrency > 0 && p.running[j.name] >= j.class.concurrency {
+					continue
+				}
+				p.queues[priority] = append(p.queues[priority][:i], p.queues[priority][i+1:]...)
+				p.queued--
+				p.running[j.name]++
+				if p.metrics {
+					RouterQueueDepth.With(prometheus.Labels{"priority": priorityNames[priority]}).Dec()
+				}
+				return j
+			}
+		}
+		p.cond.Wait()
+	}
+}
+
+// release frees the slot of the method of a request whose handler returned
+func (p *workerPool) release(j *job) {
+	p.lock.Lock()
+	if p.running[j.name]--; p.running[j.name] == 0 {
+		delete(p.running, j.name)
+	}
+	// A slot of the method is free, so a request skipped before may run now
+	p.cond.Broadcast()
+	p.lock.Unlock()
+	j.cancel()
+}
+
+func (p *workerPool) work() {
+	for {
+		j := p.next()
+		if p.metrics {
+			RouterQueueTime.With(prometheus.Labels{"priority": priorityNames[j.class.priority]}).
+				Observe(time.Since(j.queued).Seconds())
+		}
+		if err := j.request.ctx.Err(); err != nil {
+			p.expire(j, err)
+			p.release(j)
+			continue
+		}
+		handled := make(chan struct{})
+		go func() {
+			j.method.handler(j.request)
+			close(handled)
+		}()
+		select {
+		case <-handled:
+			p.release(j)
+			close(j.done)
+		case <-j.request.ctx.Done():
+			// The worker is released, but the handler keeps the slot of its
+			// method until it returns, so the concurrency limits still hold
+			p.expire(j, j.request.ctx.Err())
+			go func() {
+				<-handled
+				p.release(j)
+			}()
+		}
+	}
+}
+
+// expire replies to a request with a timeout error, unless its handler
+// already replied
+func (p *workerPool) expire(j *job, err error) {
+	if p.metrics {
+		RouterTimeouts.With(prometheus.Labels{"method": j.request.method}).Inc()
+	}
+	p.expired(j.request, err)
+	close(j.done)
+}
+
+// dispatch queues a request to be handled by the worker pool, or replies with
+// an error if the queue is full
+func (r *Router) dispatch(request routerRequest, method registeredMethod) {
+	if _, err := r.pool.submit(context.Background(), request, method); err != nil {
+		go r.sendError(request, err.Error())
+	}
+}
+
+// call runs a request in the worker pool and waits until it is handled
+func (r *Router) call(ctx context.Context, request routerRequest, method registeredMethod) error {
+	j, err := r.pool.submit(ctx, request, method)
+	if err != nil {
+		return err
+	}
+	<-j.done
+	return nil
+}
+
+// reqContext returns the context of a request, which is canceled when the
+// request times out
+func (request routerRequest) reqContext() context.Context {
+	if request.ctx == nil {
+		return context.Background()
+	}
+	return request.ctx
+}
@@ -0,0 +1,189 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vocdoni/multirpc/transports"
+
+	"go.vocdoni.io/dvote/types"
+)
+
+// testMethod returns a method whose handler signals started with the request
+// method and replies once release is closed
+func testMethod(started chan<- string, release <-chan struct{}) registeredMethod {
+	return registeredMethod{handler: func(request routerRequest) {
+		started <- request.method
+		<-release
+		request.Send(transports.Message{Data: []byte("handled")})
+	}}
+}
+
+func testRequest(method string) routerRequest {
+	return routerRequest{method: method, MessageContext: &restContext{}}
+}
+
+func reply(request routerRequest) string {
+	c := request.transportContext().(*restContext)
+	if c.reply == nil {
+		return ""
+	}
+	return string(c.reply.Data)
+}
+
+func TestClassOf(t *testing.T) {
+	request := routerRequest{method: "submitEnvelope"}
+	if name, class := classOf(request); name != "submitEnvelope" || class.priority != PriorityHigh {
+		t.Errorf("wrong class %s %+v", name, class)
+	}
+	request.MetaRequest = types.MetaRequest{WaitForCommit: true}
+	if name, class := classOf(request); name != "submitEnvelopeCommit" || class.concurrency == 0 {
+		t.Errorf("wrong class %s %+v", name, class)
+	}
+	if name, class := classOf(routerRequest{method: "getProcessList"}); name != "getProcessList" ||
+		class.priority != PriorityNormal || class.concurrency != 0 {
+		t.Errorf("wrong class %s %+v", name, class)
+	}
+}
+
+func TestWorkerPoolScheduling(t *testing.T) {
+	methodClasses["testHigh"] = methodClass{priority: PriorityHigh}
+	methodClasses["testLimited"] = methodClass{priority: PriorityLow, concurrency: 1}
+	defer delete(methodClasses, "testHigh")
+	defer delete(methodClasses, "testLimited")
+
+	started := make(chan string, 10)
+	release := make(chan struct{})
+	defer close(release)
+	method := testMethod(started, release)
+	p := newWorkerPool(2, 10, time.Hour, false, func(routerRequest, error) {})
+	ctx := context.Background()
+
+	// the limited method takes a single worker, so the second request of
+	// it waits while a later request of another method runs
+	for _, name := range []string{"testLimited", "testLimited", "getInfo"} {
+		if _, err := p.submit(ctx, testRequest(name), method); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a, b := <-started, <-started; !(a == "testLimited" && b == "getInfo" || a == "getInfo" && b == "testLimited") {
+		t.Fatalf("expected testLimited and getInfo to run, got %s and %s", a, b)
+	}
+	select {
+	case name := <-started:
+		t.Fatalf("%s run over its concurrency limit", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.lock.Lock()
+	running, queued := p.running["testLimited"], p.queued
+	p.lock.Unlock()
+	if running != 1 || queued != 1 {
+		t.Fatalf("wrong pool state, %d running and %d queued", running, queued)
+	}
+
+	// the queued requests of a higher priority are handled first
+	p2 := newWorkerPool(1, 10, time.Hour, false, func(routerRequest, error) {})
+	release2 := make(chan struct{})
+	method2 := testMethod(started, release2)
+	for _, name := range []string{"getProcessList", "testLimited", "getBlockCount", "testHigh"} {
+		if _, err := p2.submit(ctx, testRequest(name), method2); err != nil {
+			t.Fatal(err)
+		}
+		if name == "getProcessList" {
+			<-started
+		}
+	}
+	close(release2)
+	for _, expected := range []string{"testHigh", "getBlockCount", "testLimited"} {
+		if name := <-started; name != expected {
+			t.Fatalf("expected %s to run, got %s", expected, name)
+		}
+	}
+}
+
+func TestWorkerPoolQueueFull(t *testing.T) {
+	started := make(chan string, 10)
+	release := make(chan struct{})
+	defer close(release)
+	method := testMethod(started, release)
+	p := newWorkerPool(1, 1, time.Hour, false, func(routerRequest, error) {})
+	ctx := context.Background()
+	if _, err := p.submit(ctx, testRequest("getInfo"), method); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if _, err := p.submit(ctx, testRequest("getInfo"), method); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.submit(ctx, testRequest("getInfo"), method); err == nil {
+		t.Fatalf("request accepted with the queue full")
+	}
+}
+
+func TestWorkerPoolTimeout(t *testing.T) {
+	started := make(chan string, 10)
+	release := make(chan struct{})
+	method := testMethod(started, release)
+	expired := func(request routerRequest, err error) {
+		request.Send(transports.Message{Data: []byte("timeout")})
+	}
+	p := newWorkerPool(1, 10, 50*time.Millisecond, false, expired)
+	ctx := context.Background()
+
+	// a running handler which times out is replied with an error and
+	// releases the worker, but keeps the slot of its method
+	j, err := p.submit(ctx, testRequest("getInfo"), method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	<-j.done
+	if got := reply(j.request); got != "timeout" {
+		t.Fatalf("expected a timeout reply, got %q", got)
+	}
+	j2, err := p.submit(ctx, testRequest("getStats"), method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := <-started; name != "getStats" {
+		t.Fatalf("expected getStats to run, got %s", name)
+	}
+	running := func() int {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		return p.running["getInfo"]
+	}
+	if running() != 1 {
+		t.Errorf("the timed out handler released its method slot")
+	}
+	// the late replies of the handlers are dropped
+	close(release)
+	<-j2.done
+	for i := 0; running() != 0; i++ {
+		if i == 100 {
+			t.Fatalf("the handler did not release its method slot once it returned")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := reply(j.request); got != "timeout" {
+		t.Errorf("the timed out request was replied again with %q", got)
+	}
+
+	// a request which expires while queued is not handled
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	j3, err := p.submit(canceled, testRequest("getInfo"), method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-j3.done
+	if got := reply(j3.request); got != "timeout" {
+		t.Errorf("expected a timeout reply, got %q", got)
+	}
+	select {
+	case name := <-started:
+		t.Errorf("the expired request %s was handled", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -2,8 +2,13 @@ package service
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
 	"go.vocdoni.io/dvote/census"
 	"go.vocdoni.io/dvote/config"
 	"go.vocdoni.io/dvote/crypto/ethereum"
@@ -27,22 +32,10 @@ func API(apiconfig *config.API, pxy *mhttp.Proxy, storage data.Storage, cm *cens
 	// API Endpoint initialization
 	listenerOutput := make(chan transports.Message)
 
-	var htransport transports.Transport
-
-	if apiconfig.Websockets && apiconfig.HTTP {
-		htransport = mhttp.NewHttpWsHandleWithWsReadLimit(apiconfig.WebsocketsReadLimit)
-		htransport.(*mhttp.HttpWsHandler).SetProxy(pxy)
-	} else {
-		if apiconfig.Websockets {
-			htransport = mhttp.NewWebSocketHandleWithReadLimit(apiconfig.WebsocketsReadLimit)
-			htransport.(*mhttp.WebsocketHandle).SetProxy(pxy)
-		} else if apiconfig.HTTP {
-			htransport = new(mhttp.HttpHandler)
-			htransport.(*mhttp.HttpHandler).SetProxy(pxy)
-		} else {
-			return fmt.Errorf("no transports available. At least one of HTTP and WS should be enabled")
-		}
+	if !apiconfig.Websockets && !apiconfig.HTTP {
+		return fmt.Errorf("no transports available. At least one of HTTP and WS should be enabled")
 	}
+	htransport := router.NewHTTPTransport(pxy, apiconfig.HTTP, apiconfig.Websockets, apiconfig.WebsocketsReadLimit)
 
 	if err := htransport.Init(new(transports.Connection)); err != nil {
 		return err
@@ -52,6 +45,26 @@ func API(apiconfig *config.API, pxy *mhttp.Proxy, storage data.Storage, cm *cens
 	log.Infof("%s API available at %s", htransport.ConnectionType(), apiconfig.Route+"dvote")
 
 	routerAPI := router.InitRouter(listenerOutput, storage, signer, ma, apiconfig.AllowPrivate)
+	if len(apiconfig.Roles) > 0 {
+		acl, err := apiACL(apiconfig)
+		if err != nil {
+			return err
+		}
+		log.Infof("enabling %d API roles", len(apiconfig.Roles))
+		routerAPI.EnableACL(acl)
+	}
+	if apiconfig.AuditLog != "" {
+		audit, err := os.OpenFile(apiconfig.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("cannot open audit log: %w", err)
+		}
+		log.Infof("logging private API calls to %s", apiconfig.AuditLog)
+		routerAPI.EnableAuditLog(audit)
+	}
+	if apiconfig.Workers > 0 {
+		routerAPI.EnableWorkerPool(apiconfig.Workers, apiconfig.QueueSize,
+			time.Duration(apiconfig.RequestTimeout)*time.Second)
+	}
 	if apiconfig.File {
 		log.Info("enabling file API")
 		routerAPI.EnableFileAPI()
@@ -67,13 +80,75 @@ func API(apiconfig *config.API, pxy *mhttp.Proxy, storage data.Storage, cm *cens
 		routerAPI.EnableVoteAPI(vapp, vi)
 	}
 
+	if apiconfig.RateLimit > 0 || apiconfig.RateLimitMethods != "" {
+		limiter, err := router.NewRateLimiter(apiconfig.RateLimit, apiconfig.RateLimitBurst, apiconfig.RateLimitMethods)
+		if err != nil {
+			return err
+		}
+		log.Infof("enabling rate limit of %g requests per second per client", apiconfig.RateLimit)
+		routerAPI.EnableRateLimit(limiter)
+	}
+
+	if apiconfig.REST {
+		pxy.AddHandler(apiconfig.Route+"api/*", routerAPI.RESTHandler(apiconfig.Route+"api"))
+	}
+	if apiconfig.Protobuf {
+		pxy.AddHandler(apiconfig.Route+"pb", routerAPI.ProtobufHandler())
+		log.Infof("protobuf API available at %s", apiconfig.Route+"pb")
+	}
+
 	go routerAPI.Route()
 
 	go func() {
 		for {
 			time.Sleep(60 * time.Second)
-			log.Infof("[router info] privateReqs:%d publicReqs:%d", routerAPI.PrivateCalls, routerAPI.PublicCalls)
+			log.Infof("[router info] privateReqs:%d publicReqs:%d", atomic.LoadUint64(&routerAPI.PrivateCalls), atomic.LoadUint64(&routerAPI.PublicCalls))
 		}
 	}()
 	return nil
 }
+
+// apiACL builds the ACL of the API roles. The addresses of AllowedAddrs keep
+// access to all the private methods.
+func apiACL(apiconfig *config.API) (*router.ACL, error) {
+	acl := router.NewACL()
+	for _, role := range apiconfig.Roles {
+		if role.Name == router.AllowedAddrsRole {
+			return nil, fmt.Errorf("role name %q is reserved", role.Name)
+		}
+		addresses, err := roleAddresses(role.Name, role.Addresses)
+		if err != nil {
+			return nil, err
+		}
+		var expiry time.Time
+		if role.Expiry != "" {
+			if expiry, err = time.Parse(time.RFC3339, role.Expiry); err != nil {
+				return nil, fmt.Errorf("invalid expiry of role %q: %w", role.Name, err)
+			}
+		}
+		if err := acl.AddRole(role.Name, role.Methods, addresses, expiry); err != nil {
+			return nil, err
+		}
+	}
+	if apiconfig.AllowedAddrs != "" {
+		addresses, err := roleAddresses(router.AllowedAddrsRole, strings.Split(apiconfig.AllowedAddrs, ","))
+		if err != nil {
+			return nil, err
+		}
+		if err := acl.AddRole(router.AllowedAddrsRole, []string{router.AllMethods}, addresses, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	return acl, nil
+}
+
+func roleAddresses(role string, hexAddresses []string) ([]ethcommon.Address, error) {
+	var addresses []ethcommon.Address
+	for _, a := range hexAddresses {
+		if !ethcommon.IsHexAddress(a) {
+			return nil, fmt.Errorf("invalid address %q of role %q", a, role)
+		}
+		addresses = append(addresses, ethcommon.HexToAddress(a))
+	}
+	return addresses, nil
+}
@@ -1,28 +1,51 @@
 package service
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"time"
 
 	"go.vocdoni.io/dvote/census"
+	"go.vocdoni.io/dvote/censustree"
 	"go.vocdoni.io/dvote/censustree/gravitontree"
+	"go.vocdoni.io/dvote/censustree/iden3tree"
+	"go.vocdoni.io/dvote/censustree/poseidontree"
+	"go.vocdoni.io/dvote/config"
 	"go.vocdoni.io/dvote/log"
 	"go.vocdoni.io/dvote/metrics"
 )
 
-func Census(datadir string, ma *metrics.Agent) (*census.Manager, error) {
-	log.Info("creating census service")
+// censusTrees are the census tree implementations selectable by name
+var censusTrees = map[string]func(name, storageDir string) (censustree.Tree, error){
+	"graviton": gravitontree.NewTree,
+	"iden3":    iden3tree.NewTree,
+	"poseidon": poseidontree.NewTree,
+}
+
+func Census(datadir string, cfg *config.API, ma *metrics.Agent) (*census.Manager, error) {
+	log.Infof("creating census service with %s trees", cfg.CensusTree)
+	newTree, ok := censusTrees[cfg.CensusTree]
+	if !ok {
+		return nil, fmt.Errorf("unknown census tree %q", cfg.CensusTree)
+	}
 	var censusManager census.Manager
+	censusManager.TreeIdleTimeout = time.Duration(cfg.CensusIdleTimeout) * time.Minute
+	censusManager.MaxLoadedTrees = cfg.CensusMaxLoaded
 	stdir := path.Join(datadir, "census")
 	if _, err := os.Stat(stdir); os.IsNotExist(err) {
 		if err := os.MkdirAll(stdir, os.ModePerm); err != nil {
 			return nil, err
 		}
 	}
-	if err := censusManager.Init(stdir, "", gravitontree.NewTree); err != nil {
+	if err := censusManager.Init(stdir, "", newTree); err != nil {
+		return nil, err
+	}
+	keyAudit, err := os.OpenFile(path.Join(stdir, "keys-audit.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
 		return nil, err
 	}
+	censusManager.EnableKeyAuditLog(keyAudit)
 
 	// Collect metrics for prometheus
 	go censusManager.CollectMetrics(ma)
@@ -110,10 +110,17 @@ func Vochain(vconfig *config.VochainCfg, results, waitForSync bool, ma *metrics.
 		if err != nil {
 			return
 		}
+		if vconfig.ResultsSnapshotInterval > 0 {
+			sc.SnapshotInterval = vconfig.ResultsSnapshotInterval
+		}
 	}
 	if cm != nil {
 		log.Infof("starting census downloader service")
-		censusdownloader.NewCensusDownloader(vnode, cm, !vconfig.ImportPreviousCensus)
+		cd := censusdownloader.NewCensusDownloader(vnode, cm, !vconfig.ImportPreviousCensus)
+		if vconfig.CensusGCInterval > 0 {
+			go cd.GCDaemon(time.Duration(vconfig.CensusGCInterval)*time.Minute,
+				time.Duration(vconfig.CensusGCGracePeriod)*time.Minute, vconfig.CensusGCDryRun)
+		}
 	}
 
 	// Vochain info
@@ -371,14 +371,28 @@ func (t *GravitonTree) Add(key, value []byte) error {
 	if v, err := t.tree.Get(key); err == nil && string(v) == string(value) {
 		return nil
 	}
-	// if it does not exist, add, increase size counter and return
+	_, getErr := t.tree.Get(key)
+	// add and, if it did not exist, increase size counter and return
 	err := t.tree.Put(key, value)
-	if err == nil {
+	if err == nil && getErr != nil {
 		atomic.AddUint64(&t.size, 1)
 	}
 	return err
 }
 
+func (t *GravitonTree) Delete(key []byte) error {
+	if _, err := t.tree.Get(key); err != nil {
+		return fmt.Errorf("key %x not found", key)
+	}
+	if err := t.tree.Delete(key); err != nil {
+		return err
+	}
+	if atomic.LoadUint64(&t.size) > 0 {
+		atomic.AddUint64(&t.size, ^uint64(0))
+	}
+	return nil
+}
+
 func (t *GravitonTree) Version() uint64 {
 	return t.version
 }
@@ -466,6 +480,21 @@ func (t *GravitonTree) Verify(key, proof, root []byte) bool {
 	return p.VerifyMembership(r, key)
 }
 
+// ProofValue returns the value of the key proven by a membership proof
+func ProofValue(proof []byte) (value []byte, err error) {
+	var p graviton.Proof
+	// Unmarshal() will generate a panic if the proof size is incorrect
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid proof: %v", r)
+		}
+	}()
+	if err := p.Unmarshal(proof); err != nil {
+		return nil, err
+	}
+	return p.Value(), nil
+}
+
 func Verify(key, proof, root []byte) (bool, error) {
 	var p graviton.Proof
 	var r [32]byte
@@ -1,12 +1,14 @@
 package iavlstate
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"strconv"
 	"sync"
 
 	"github.com/cosmos/iavl"
+	iavlproto "github.com/cosmos/iavl/proto"
 	tmdb "github.com/tendermint/tm-db"
 	"go.vocdoni.io/dvote/crypto/ethereum"
 	"go.vocdoni.io/dvote/statedb"
@@ -209,17 +211,29 @@ func (i *IavlState) Rollback() error {
 }
 
 func (i *IavlState) getHash() []byte {
-	var hash string
-	keys := make([]string, 0, len(i.trees))
-	for k := range i.trees {
+	roots := make(map[string][]byte, len(i.trees))
+	for name, t := range i.trees {
+		roots[name] = t.tree.Hash()
+	}
+	return RootsHash(roots)
+}
+
+// RootsHash computes the state hash from the root of each tree, indexed by
+// tree name. The result is the same as the one returned by Hash and Commit,
+// so it can be used by third parties to check the state roots against a
+// block header AppHash.
+func RootsHash(roots map[string][]byte) []byte {
+	keys := make([]string, 0, len(roots))
+	for k := range roots {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
-	for _, t := range keys {
-		hash = fmt.Sprintf("%s%s", hash, i.trees[t].tree.Hash())
+	var hash []byte
+	for _, k := range keys {
+		hash = append(hash, roots[k]...)
 	}
-	return ethereum.HashRaw([]byte(hash))
+	return ethereum.HashRaw(hash)
 }
 
 func (i *IavlState) Hash() []byte {
@@ -252,9 +266,22 @@ func (t *IavlTree) Add(key, value []byte) error {
 	return nil
 }
 
+func (t *IavlTree) Delete(key []byte) error {
+	if t.isImmutable {
+		return fmt.Errorf("cannot delete values from a immutable tree")
+	}
+	if _, removed := t.tree.Remove(key); !removed {
+		return fmt.Errorf("key %x not found", key)
+	}
+	return nil
+}
+
 func (t *IavlTree) Iterate(prefix []byte, callback func(key, value []byte) bool) {
-	until := make([]byte, len(prefix))
-	copy(until, prefix)
+	var until []byte // an empty prefix iterates over the whole tree
+	if len(prefix) > 0 {
+		until = make([]byte, len(prefix))
+		copy(until, prefix)
+	}
 	// Set until to the next prefix: 0xABCDEF => 0xABCDFF
 	for i := len(until) - 1; i >= 0; i-- {
 		if until[i] != byte(0xFF) {
@@ -307,10 +334,49 @@ func (t *IavlTree) Proof(key []byte) ([]byte, error) {
 		_, p, err = t.tree.GetWithProof(key)
 	}
 
-	return []byte(p.String()), err
+	if err != nil {
+		return nil, err
+	}
+	return p.ToProto().Marshal()
 }
 
 func (t *IavlTree) Verify(key, proof, root []byte) bool {
-	// TO-DO
+	p, err := decodeProof(proof)
+	if err != nil {
+		return false
+	}
+	if err := p.Verify(root); err != nil {
+		return false
+	}
+	for _, k := range p.Keys() {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
 	return false
 }
+
+// VerifyProof checks that the key/value pair is included in the tree
+// identified by root, using a proof generated by IavlTree.Proof.
+func VerifyProof(key, value, proof, root []byte) error {
+	p, err := decodeProof(proof)
+	if err != nil {
+		return err
+	}
+	if err := p.Verify(root); err != nil {
+		return err
+	}
+	return p.VerifyItem(key, value)
+}
+
+func decodeProof(proof []byte) (*iavl.RangeProof, error) {
+	pb := new(iavlproto.RangeProof)
+	if err := pb.Unmarshal(proof); err != nil {
+		return nil, fmt.Errorf("cannot decode proof: %w", err)
+	}
+	p, err := iavl.RangeProofFromProto(pb)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
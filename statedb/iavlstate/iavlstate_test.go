@@ -180,3 +180,53 @@ func TestOrder(t *testing.T) {
 		}
 	*/
 }
+
+func TestProof(t *testing.T) {
+	t.Parallel()
+
+	s := &IavlState{}
+	if err := s.Init(t.TempDir(), "mem"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddTree("t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddTree("t2"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		s.Tree("t1").Add([]byte(fmt.Sprintf("%d", i)), []byte(fmt.Sprintf("number %d", i)))
+		s.Tree("t2").Add([]byte(fmt.Sprintf("%d", i)), []byte(fmt.Sprintf("value %d", i)))
+	}
+	h, err := s.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The state hash must be reproducible from the tree roots
+	roots := map[string][]byte{
+		"t1": s.ImmutableTree("t1").Hash(),
+		"t2": s.ImmutableTree("t2").Hash(),
+	}
+	if string(RootsHash(roots)) != string(h) {
+		t.Fatalf("roots hash %x does not match state hash %x", RootsHash(roots), h)
+	}
+
+	tr := s.ImmutableTree("t1")
+	proof, err := tr.Proof([]byte("5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Verify([]byte("5"), proof, roots["t1"]) {
+		t.Errorf("proof verification failed")
+	}
+	if err := VerifyProof([]byte("5"), []byte("number 5"), proof, roots["t1"]); err != nil {
+		t.Errorf("proof verification failed: %v", err)
+	}
+	if err := VerifyProof([]byte("5"), []byte("number 6"), proof, roots["t1"]); err == nil {
+		t.Errorf("proof verification with a wrong value must fail")
+	}
+	if err := VerifyProof([]byte("5"), []byte("number 5"), proof, roots["t2"]); err == nil {
+		t.Errorf("proof verification with a wrong root must fail")
+	}
+}
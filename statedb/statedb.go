@@ -18,6 +18,7 @@ type StateDB interface {
 type StateTree interface {
 	Get(key []byte) []byte
 	Add(key, value []byte) error
+	Delete(key []byte) error // returns an error if the key does not exist
 	Iterate(prefix []byte, callback func(key, value []byte) bool)
 	Hash() []byte
 	Count() uint64
@@ -47,9 +47,8 @@ func (d *DvoteAPIServer) Start(tb testing.TB, apis ...string) {
 	d.PxyAddr = fmt.Sprintf("ws://%s/dvote", pxy.Addr)
 
 	// Create WebSocket endpoint
-	ws := new(mhttp.WebsocketHandle)
+	ws := router.NewHTTPTransport(pxy, false, true, 0)
 	ws.Init(new(transports.Connection))
-	ws.SetProxy(pxy)
 
 	// Create the listener for routing messages
 	listenerOutput := make(chan transports.Message)
@@ -101,7 +100,7 @@ func (d *DvoteAPIServer) Start(tb testing.TB, apis ...string) {
 	}
 
 	go routerAPI.Route()
-	ws.AddProxyHandler("/dvote")
+	ws.AddNamespace("/dvote")
 }
 
 // NewMockProxy creates a new testing proxy with predefined valudes
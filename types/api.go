@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+
+	"go.vocdoni.io/proto/build/go/models"
 )
 
 // MessageRequest holds a decoded request but does not decode the body
@@ -18,31 +20,39 @@ type RequestMessage struct {
 // MetaRequest contains all of the possible request fields.
 // Fields must be in alphabetical order
 type MetaRequest struct {
-	CensusID     string     `json:"censusId,omitempty"`
-	CensusURI    string     `json:"censusUri,omitempty"`
-	CensusKey    []byte     `json:"censusKey,omitempty"`
-	CensusKeys   [][]byte   `json:"censusKeys,omitempty"`
-	CensusValue  HexBytes   `json:"censusValue,omitempty"`
-	CensusValues []HexBytes `json:"censusValues,omitempty"`
-	CensusDump   []byte     `json:"censusDump,omitempty"`
-	Content      []byte     `json:"content,omitempty"`
-	Digested     bool       `json:"digested,omitempty"`
-	EntityId     HexBytes   `json:"entityId,omitempty"`
-	From         int64      `json:"from,omitempty"`
-	FromID       HexBytes   `json:"fromId,omitempty"`
-	ListSize     int64      `json:"listSize,omitempty"`
-	Method       string     `json:"method"`
-	Name         string     `json:"name,omitempty"`
-	Nullifier    HexBytes   `json:"nullifier,omitempty"`
-	Payload      []byte     `json:"payload,omitempty"`
-	ProcessID    HexBytes   `json:"processId,omitempty"`
-	ProofData    HexBytes   `json:"proofData,omitempty"`
-	PubKeys      []string   `json:"pubKeys,omitempty"`
-	RootHash     HexBytes   `json:"rootHash,omitempty"`
-	Signature    HexBytes   `json:"signature,omitempty"`
-	Timestamp    int32      `json:"timestamp"`
-	Type         string     `json:"type,omitempty"`
-	URI          string     `json:"uri,omitempty"`
+	BaseRoot      HexBytes             `json:"baseRoot,omitempty"`
+	CensusID      string               `json:"censusId,omitempty"`
+	CensusURI     string               `json:"censusUri,omitempty"`
+	CensusKey     []byte               `json:"censusKey,omitempty"`
+	CensusKeys    [][]byte             `json:"censusKeys,omitempty"`
+	CensusValue   HexBytes             `json:"censusValue,omitempty"`
+	CensusValues  []HexBytes           `json:"censusValues,omitempty"`
+	CensusDump    []byte               `json:"censusDump,omitempty"`
+	Chunked       bool                 `json:"chunked,omitempty"`
+	Content       []byte               `json:"content,omitempty"`
+	Digested      bool                 `json:"digested,omitempty"`
+	EntityId      HexBytes             `json:"entityId,omitempty"`
+	From          int64                `json:"from,omitempty"`
+	FromID        HexBytes             `json:"fromId,omitempty"`
+	Height        int64                `json:"height,omitempty"`
+	ListSize      int64                `json:"listSize,omitempty"`
+	Method        string               `json:"method"`
+	Name          string               `json:"name,omitempty"`
+	Nullifier     HexBytes             `json:"nullifier,omitempty"`
+	Payload       []byte               `json:"payload,omitempty"`
+	ProcessID     HexBytes             `json:"processId,omitempty"`
+	ProofData     HexBytes             `json:"proofData,omitempty"`
+	Proofs        []HexBytes           `json:"proofs,omitempty"`
+	PubKeys       []string             `json:"pubKeys,omitempty"`
+	RootHash      HexBytes             `json:"rootHash,omitempty"`
+	SearchFilter  *ProcessSearchFilter `json:"searchFilter,omitempty"`
+	Signature     HexBytes             `json:"signature,omitempty"`
+	Timestamp     int32                `json:"timestamp"`
+	To            int64                `json:"to,omitempty"`
+	Topic         string               `json:"topic,omitempty"`
+	Type          string               `json:"type,omitempty"`
+	URI           string               `json:"uri,omitempty"`
+	WaitForCommit bool                 `json:"waitForCommit,omitempty"`
 }
 
 func (r MetaRequest) String() string {
@@ -92,45 +102,58 @@ type ResponseMessage struct {
 // Fields must be in alphabetical order
 // Those fields with valid zero-values (such as bool) must be pointers
 type MetaResponse struct {
-	APIList              []string   `json:"apiList,omitempty"`
-	BlockTime            *[5]int32  `json:"blockTime,omitempty"`
-	BlockTimestamp       int32      `json:"blockTimestamp,omitempty"`
-	CensusID             string     `json:"censusId,omitempty"`
-	CensusList           []string   `json:"censusList,omitempty"`
-	CensusKeys           [][]byte   `json:"censusKeys,omitempty"`
-	CensusValues         []HexBytes `json:"censusValues,omitempty"`
-	CensusDump           []byte     `json:"censusDump,omitempty"`
-	CommitmentKeys       []Key      `json:"commitmentKeys,omitempty"`
-	Content              []byte     `json:"content,omitempty"`
-	EncryptionPrivKeys   []Key      `json:"encryptionPrivKeys,omitempty"`
-	EncryptionPublicKeys []Key      `json:"encryptionPubKeys,omitempty"`
-	EntityID             string     `json:"entityId,omitempty"`
-	EntityIDs            []string   `json:"entityIds,omitempty"`
-	Files                []byte     `json:"files,omitempty"`
-	Finished             *bool      `json:"finished,omitempty"`
-	Health               int32      `json:"health,omitempty"`
-	Height               *uint32    `json:"height,omitempty"`
-	InvalidClaims        []int      `json:"invalidClaims,omitempty"`
-	Message              string     `json:"message,omitempty"`
-	Nullifier            string     `json:"nullifier,omitempty"`
-	Nullifiers           *[]string  `json:"nullifiers,omitempty"`
-	Ok                   bool       `json:"ok"`
-	Paused               *bool      `json:"paused,omitempty"`
-	Payload              string     `json:"payload,omitempty"` // TODO: sometimes hex, sometimes base64 - consolidate with protobuf
-	ProcessIDs           []string   `json:"processIds,omitempty"`
-	ProcessList          []string   `json:"processList,omitempty"`
-	Registered           *bool      `json:"registered,omitempty"`
-	Request              string     `json:"request"`
-	Results              [][]string `json:"results,omitempty"`
-	RevealKeys           []Key      `json:"revealKeys,omitempty"`
-	Root                 HexBytes   `json:"root,omitempty"`
-	Siblings             HexBytes   `json:"siblings,omitempty"`
-	Size                 *int64     `json:"size,omitempty"`
-	State                string     `json:"state,omitempty"`
-	Timestamp            int32      `json:"timestamp"`
-	Type                 string     `json:"type,omitempty"`
-	URI                  string     `json:"uri,omitempty"`
-	ValidProof           *bool      `json:"validProof,omitempty"`
+	APIList              []string           `json:"apiList,omitempty"`
+	BlockTime            *[5]int32          `json:"blockTime,omitempty"`
+	BlockTimestamp       int32              `json:"blockTimestamp,omitempty"`
+	CensusID             string             `json:"censusId,omitempty"`
+	CensusList           []string           `json:"censusList,omitempty"`
+	CensusKeys           [][]byte           `json:"censusKeys,omitempty"`
+	CensusValues         []HexBytes         `json:"censusValues,omitempty"`
+	CensusDump           []byte             `json:"censusDump,omitempty"`
+	CommitmentKeys       []Key              `json:"commitmentKeys,omitempty"`
+	Content              []byte             `json:"content,omitempty"`
+	DocumentSignature    HexBytes           `json:"documentSignature,omitempty"`
+	EncryptionPrivKeys   []Key              `json:"encryptionPrivKeys,omitempty"`
+	EncryptionPublicKeys []Key              `json:"encryptionPubKeys,omitempty"`
+	EntityID             string             `json:"entityId,omitempty"`
+	EntityIDs            []string           `json:"entityIds,omitempty"`
+	Files                []byte             `json:"files,omitempty"`
+	Finished             *bool              `json:"finished,omitempty"`
+	Health               int32              `json:"health,omitempty"`
+	Height               *uint32            `json:"height,omitempty"`
+	InvalidClaims        []int              `json:"invalidClaims,omitempty"`
+	Message              string             `json:"message,omitempty"`
+	Nullifier            string             `json:"nullifier,omitempty"`
+	Nullifiers           *[]string          `json:"nullifiers,omitempty"`
+	Ok                   bool               `json:"ok"`
+	Paused               *bool              `json:"paused,omitempty"`
+	Payload              string             `json:"payload,omitempty"` // TODO: sometimes hex, sometimes base64 - consolidate with protobuf
+	Process              *ProcessDetails    `json:"process,omitempty"`
+	ProcessID            HexBytes           `json:"processId,omitempty"`
+	ProcessIDs           []string           `json:"processIds,omitempty"`
+	ProcessList          []string           `json:"processList,omitempty"`
+	ProcessStats         *ProcessStats      `json:"processStats,omitempty"`
+	Processes            []*ProcessSummary  `json:"processes,omitempty"`
+	Proofs               []HexBytes         `json:"proofs,omitempty"`
+	PubKeys              []string           `json:"pubKeys,omitempty"`
+	Registered           *bool              `json:"registered,omitempty"`
+	Request              string             `json:"request"`
+	Results              [][]string         `json:"results,omitempty"`
+	ResultsSeries        []*ResultsSnapshot `json:"resultsSeries,omitempty"`
+	RevealKeys           []Key              `json:"revealKeys,omitempty"`
+	Root                 HexBytes           `json:"root,omitempty"`
+	RootHistory          []CensusRoot       `json:"rootHistory,omitempty"`
+	Siblings             HexBytes           `json:"siblings,omitempty"`
+	SignedHeader         []byte             `json:"signedHeader,omitempty"`
+	Size                 *int64             `json:"size,omitempty"`
+	State                string             `json:"state,omitempty"`
+	StateProof           *StateProof        `json:"stateProof,omitempty"`
+	Timestamp            int32              `json:"timestamp"`
+	Topic                string             `json:"topic,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	URI                  string             `json:"uri,omitempty"`
+	ValidProof           *bool              `json:"validProof,omitempty"`
+	ValidProofs          []bool             `json:"validProofs,omitempty"`
 }
 
 func (r MetaResponse) String() string {
@@ -169,9 +192,118 @@ func (r *MetaResponse) SetError(v interface{}) {
 	r.Message = fmt.Sprintf("%s", v)
 }
 
+// ProcessSearchFilter holds the criteria of a process search.
+// Empty fields match any process. Block ranges are inclusive.
+type ProcessSearchFilter struct {
+	Anonymous      *bool  `json:"anonymous,omitempty"`
+	CensusOrigin   string `json:"censusOrigin,omitempty"`
+	EncryptedVotes *bool  `json:"encryptedVotes,omitempty"`
+	EndBlockFrom   uint32 `json:"endBlockFrom,omitempty"`
+	EndBlockTo     uint32 `json:"endBlockTo,omitempty"`
+	Serial         *bool  `json:"serial,omitempty"`
+	StartBlockFrom uint32 `json:"startBlockFrom,omitempty"`
+	StartBlockTo   uint32 `json:"startBlockTo,omitempty"`
+	Status         string `json:"status,omitempty"`
+	UniqueValues   *bool  `json:"uniqueValues,omitempty"`
+}
+
+// ProcessSummary holds the main information of a process
+type ProcessSummary struct {
+	Anonymous      bool     `json:"anonymous"`
+	BlockCount     uint32   `json:"blockCount"`
+	CensusOrigin   string   `json:"censusOrigin"`
+	EncryptedVotes bool     `json:"encryptedVotes"`
+	EndBlock       uint32   `json:"endBlock"`
+	EntityID       HexBytes `json:"entityId"`
+	ProcessID      HexBytes `json:"processId"`
+	Serial         bool     `json:"serial"`
+	StartBlock     uint32   `json:"startBlock"`
+	Status         string   `json:"status"`
+	UniqueValues   bool     `json:"uniqueValues"`
+}
+
+// ProcessDetails holds a process as stored in the Vochain state, with its end
+// block, current number of votes and whether results are available
+type ProcessDetails struct {
+	BlockCount       uint32                     `json:"blockCount"`
+	CensusOrigin     string                     `json:"censusOrigin"`
+	CensusRoot       HexBytes                   `json:"censusRoot"`
+	CensusURI        string                     `json:"censusUri,omitempty"`
+	EndBlock         uint32                     `json:"endBlock"`
+	EntityID         HexBytes                   `json:"entityId"`
+	EnvelopeType     *models.EnvelopeType       `json:"envelopeType"`
+	KeyIndex         uint32                     `json:"keyIndex"`
+	Mode             *models.ProcessMode        `json:"mode"`
+	Namespace        uint32                     `json:"namespace"`
+	ProcessID        HexBytes                   `json:"processId"`
+	QuestionCount    uint32                     `json:"questionCount"`
+	QuestionIndex    uint32                     `json:"questionIndex"`
+	ResultsAvailable bool                       `json:"resultsAvailable"`
+	StartBlock       uint32                     `json:"startBlock"`
+	Status           string                     `json:"status"`
+	VoteCount        uint32                     `json:"voteCount"`
+	VoteOptions      *models.ProcessVoteOptions `json:"voteOptions"`
+}
+
+// ProcessStats holds the participation statistics of a process.
+// CensusSize and Turnout are only provided if the census size is known.
+type ProcessStats struct {
+	CensusSize       *int64            `json:"censusSize,omitempty"`
+	Envelopes        uint64            `json:"envelopes"`
+	InvalidEnvelopes uint64            `json:"invalidEnvelopes"`
+	TotalWeight      string            `json:"totalWeight"`
+	Turnout          *float64          `json:"turnout,omitempty"`
+	VotesPerBlock    map[uint32]uint64 `json:"votesPerBlock"`
+}
+
+// ResultsSnapshot holds the live results of a process at a given height
+type ResultsSnapshot struct {
+	Height  uint32     `json:"height"`
+	Results [][]string `json:"results"`
+}
+
+// StateProof is a merkle proof of a key in one of the Vochain state trees.
+// Roots contains the root of every state tree, which are committed in the
+// AppHash of the block header at Height+1.
+type StateProof struct {
+	Height int64               `json:"height"`
+	Key    HexBytes            `json:"key"`
+	Proof  []byte              `json:"proof"`
+	Roots  map[string]HexBytes `json:"roots"`
+	Tree   string              `json:"tree"`
+	Value  []byte              `json:"value"`
+}
+
+// CensusDump is a published census. A delta dump has no Data, instead it
+// contains the claims added and removed since the census published with
+// BaseRoot at BaseURI, which might be a delta dump too. A chunked dump has
+// no Data either, it is the manifest of the Chunks holding its claims.
 type CensusDump struct {
-	RootHash []byte `json:"rootHash"`
-	Data     []byte `json:"data"`
+	RootHash []byte        `json:"rootHash"`
+	Data     []byte        `json:"data"`
+	BaseRoot []byte        `json:"baseRoot,omitempty"`
+	BaseURI  string        `json:"baseURI,omitempty"`
+	Added    []CensusClaim `json:"added,omitempty"`
+	Removed  [][]byte      `json:"removed,omitempty"`
+	Chunks   []CensusChunk `json:"chunks,omitempty"`
+}
+
+// CensusChunk is a part of a chunked census dump
+type CensusChunk struct {
+	URI  string `json:"uri,omitempty"` // Remote storage URI
+	Hash []byte `json:"hash"`          // Keccak256 hash of the uncompressed chunk
+}
+
+// CensusClaim is a key and value of a census merkle tree
+type CensusClaim struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+// CensusRoot is a root a census merkle tree had since Timestamp (unix seconds)
+type CensusRoot struct {
+	Root      HexBytes `json:"root"`
+	Timestamp int64    `json:"timestamp"`
 }
 
 // VotePackage represents the payload of a vote (usually base64 encoded)
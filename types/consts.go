@@ -64,6 +64,20 @@ const (
 	ScrutinizerResultsPrefix = byte(0x24)
 	// ScrutinizerProcessEndingPrefix is the prefix for keep track of the processes ending on a specific block
 	ScrutinizerProcessEndingPrefix = byte(0x25)
+	// ScrutinizerProcessPrefix is the prefix of the storage process summary keys, used for searching
+	ScrutinizerProcessPrefix = byte(0x26)
+	// ScrutinizerStatsPrefix is the prefix of the storage process statistics keys
+	ScrutinizerStatsPrefix = byte(0x27)
+	// ScrutinizerDocumentPrefix is the prefix of the storage results document keys
+	ScrutinizerDocumentPrefix = byte(0x28)
+	// ScrutinizerSnapshotPrefix is the prefix of the storage live results snapshot keys
+	ScrutinizerSnapshotPrefix = byte(0x29)
+	// ScrutinizerProcessIndexPrefix is the prefix of the storage process search index keys
+	ScrutinizerProcessIndexPrefix = byte(0x2a)
+	// ScrutinizerFinalHeaderPrefix is the prefix of the storage process final block keys
+	ScrutinizerFinalHeaderPrefix = byte(0x2b)
+	// ScrutinizerBlockVotesPrefix is the prefix of the storage keys with the number of votes of a process on a block
+	ScrutinizerBlockVotesPrefix = byte(0x2c)
 
 	// Vochain
 
@@ -69,6 +69,9 @@ type QueryData struct {
 type GenesisAppState struct {
 	Validators []GenesisValidator `json:"validators"`
 	Oracles    []string           `json:"oracles"`
+	// WeightedCensusHeight is the height from which the votes of the
+	// OFF_CHAIN_TREE_WEIGHTED processes are accepted, zero disables them
+	WeightedCensusHeight uint32 `json:"weightedCensusHeight,omitempty"`
 }
 
 // The rest of these genesis app state types are copied from
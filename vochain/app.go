@@ -1,10 +1,12 @@
 package vochain
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	abcitypes "github.com/tendermint/tendermint/abci/types"
@@ -58,6 +60,77 @@ func (app *BaseApplication) SendTX(tx []byte) (*ctypes.ResultBroadcastTx, error)
 	}, nil
 }
 
+// txSubscribers counts the event bus subscriptions of SendTxCommit
+var txSubscribers uint64
+
+// SendTxCommit sends a transaction to the mempool and waits until it is
+// included in a block, or ctx is done. If CheckTx fails the result has no
+// height. The block inclusion is notified by the Tendermint event bus.
+func (app *BaseApplication) SendTxCommit(ctx context.Context, tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+	if app.Node == nil {
+		return nil, fmt.Errorf("vochain node not available")
+	}
+	hash := tmtypes.Tx(tx).Hash()
+	// Subscribe before sending the transaction, so the event can't be missed.
+	// The same transaction may be sent again, so each call has its own
+	// subscriber.
+	subscriber := fmt.Sprintf("sendtx-%x-%d", hash, atomic.AddUint64(&txSubscribers, 1))
+	query := tmtypes.EventQueryTxFor(tx)
+	sub, err := app.Node.EventBus().Subscribe(ctx, subscriber, query)
+	if err != nil {
+		return nil, fmt.Errorf("cannot subscribe to transaction: %w", err)
+	}
+	defer func() {
+		if err := app.Node.EventBus().Unsubscribe(context.Background(), subscriber, query); err != nil {
+			log.Warnf("cannot unsubscribe from transaction: (%s)", err)
+		}
+	}()
+
+	res, err := app.SendTX(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &ctypes.ResultBroadcastTxCommit{
+		CheckTx: abcitypes.ResponseCheckTx{Code: res.Code, Data: res.Data, Log: res.Log},
+		Hash:    hash,
+	}
+	if res.Code != abcitypes.CodeTypeOK {
+		return result, nil
+	}
+	select {
+	case msg := <-sub.Out():
+		data := msg.Data().(tmtypes.EventDataTx)
+		result.DeliverTx = data.Result
+		result.Height = data.Height
+		return result, nil
+	case <-sub.Cancelled():
+		return nil, fmt.Errorf("transaction subscription canceled: (%v)", sub.Err())
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the transaction to be committed")
+	}
+}
+
+// SignedHeader returns the block header at the given height along with the
+// commit signed by the validators for that block.
+func (app *BaseApplication) SignedHeader(height int64) (*tmtypes.SignedHeader, error) {
+	if app.Node == nil {
+		return nil, fmt.Errorf("vochain node not available")
+	}
+	meta := app.Node.BlockStore().LoadBlockMeta(height)
+	if meta == nil {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	// The commit for the last block is not yet included in any block
+	commit := app.Node.BlockStore().LoadBlockCommit(height)
+	if commit == nil {
+		commit = app.Node.BlockStore().LoadSeenCommit(height)
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("commit for block %d not found", height)
+	}
+	return &tmtypes.SignedHeader{Header: &meta.Header, Commit: commit}, nil
+}
+
 // Info Return information about the application state.
 // Used to sync Tendermint with the application during a handshake that happens on startup.
 // The returned AppVersion will be included in the Header of every block.
@@ -116,6 +189,15 @@ func (app *BaseApplication) InitChain(req abcitypes.RequestInitChain) abcitypes.
 		}
 	}
 
+	// the weighted censuses are enabled by the genesis, so all the nodes
+	// accept their votes from the same height
+	if h := genesisAppState.WeightedCensusHeight; h > 0 {
+		log.Infof("enabling weighted censuses from height %d", h)
+		if err := app.State.SetWeightedCensusHeight(h); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	var header models.TendermintHeader
 	header.Height = 0
 	header.AppHash = []byte{}
@@ -35,15 +35,28 @@ import (
 // In case of weighted proof, this function will return the weight as second parameter.
 func checkProof(proof *models.Proof, censusOrigin models.CensusOrigin, censusRoot, processID, key []byte) (bool, *big.Int, error) {
 	switch censusOrigin {
-	case models.CensusOrigin_OFF_CHAIN_TREE:
+	case models.CensusOrigin_OFF_CHAIN_TREE, models.CensusOrigin_OFF_CHAIN_TREE_WEIGHTED:
 		switch proof.Payload.(type) {
 		case *models.Proof_Graviton:
 			p := proof.GetGraviton()
 			if p == nil {
 				return false, nil, fmt.Errorf("graviton proof is empty")
 			}
-			valid, err := gravitontree.CheckProof(key, []byte{}, censusRoot, p.Siblings)
-			return valid, big.NewInt(1), err
+			if censusOrigin == models.CensusOrigin_OFF_CHAIN_TREE {
+				valid, err := gravitontree.CheckProof(key, []byte{}, censusRoot, p.Siblings)
+				return valid, big.NewInt(1), err
+			}
+			// the claim value is the weight, as a big-endian unsigned integer
+			value, err := gravitontree.ProofValue(p.Siblings)
+			if err != nil {
+				return false, nil, fmt.Errorf("cannot get the weight from the proof: %w", err)
+			}
+			weight := new(big.Int).SetBytes(value)
+			if weight.Sign() == 0 {
+				return false, nil, fmt.Errorf("census weight is zero")
+			}
+			valid, err := gravitontree.CheckProof(key, value, censusRoot, p.Siblings)
+			return valid, weight, err
 		case *models.Proof_Iden3:
 			// NOT IMPLEMENTED
 			return false, nil, fmt.Errorf("iden3 proof not implemented")
@@ -13,7 +13,6 @@ import (
 )
 
 // TBD: A startup process for importing on-going processe census
-// TBD: a mechanism for removing alyready finished census?
 
 // CensusDownloader is a Vochain event handler aimed to fetch and import census when a new process is created
 type CensusDownloader struct {
@@ -23,6 +22,7 @@ type CensusDownloader struct {
 	queueLock     sync.RWMutex
 	importOnlyNew bool
 	isFastSync    bool
+	gc            censusGC
 }
 
 // NewCensusDownloader creates a new instance of the census downloader daemon.
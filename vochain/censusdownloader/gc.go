@@ -0,0 +1,124 @@
+package censusdownloader
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/proto/build/go/models"
+)
+
+// GCReport is the result of a census garbage collection
+type GCReport struct {
+	// Removed are the census removed, or that would be removed on a dry run
+	Removed []string
+	// Pending are the unused census still within the grace period
+	Pending []string
+	// InUse is the number of imported census used by a process
+	InUse int
+}
+
+// censusGC tracks since when the imported census are not used
+type censusGC struct {
+	lock   sync.Mutex
+	unused map[string]time.Time
+}
+
+// usedRoots returns the census roots of the processes which have not finished
+func (c *CensusDownloader) usedRoots() (map[string]bool, error) {
+	roots := make(map[string]bool)
+	err := c.vochain.State.IterateProcesses(func(p *models.Process) bool {
+		switch p.Status {
+		case models.ProcessStatus_READY, models.ProcessStatus_PAUSED:
+			roots[hex.EncodeToString(p.CensusRoot)] = true
+		}
+		return false
+	}, true)
+	return roots, err
+}
+
+// CollectGarbage removes the imported census not used by any process which
+// has not finished for at least gracePeriod. If dryRun is true, nothing is
+// removed and the report lists what would be removed.
+func (c *CensusDownloader) CollectGarbage(gracePeriod time.Duration, dryRun bool) (*GCReport, error) {
+	roots, err := c.usedRoots()
+	if err != nil {
+		return nil, err
+	}
+	var imported []string
+	c.census.TreesMu.RLock()
+	for _, ns := range c.census.Census.Namespaces {
+		// local census and the published copies of local census, which
+		// have a URI but were not imported, are never removed
+		if ns.Imported {
+			imported = append(imported, ns.Name)
+		}
+	}
+	c.census.TreesMu.RUnlock()
+	sort.Strings(imported)
+
+	c.gc.lock.Lock()
+	defer c.gc.lock.Unlock()
+	if c.gc.unused == nil {
+		c.gc.unused = make(map[string]time.Time)
+	}
+	report := &GCReport{}
+	now := time.Now()
+	known := make(map[string]bool, len(imported))
+	for _, name := range imported {
+		known[name] = true
+		if roots[name] {
+			report.InUse++
+			delete(c.gc.unused, name)
+			continue
+		}
+		since, ok := c.gc.unused[name]
+		if !ok {
+			since = now
+			c.gc.unused[name] = now
+		}
+		if now.Sub(since) < gracePeriod {
+			report.Pending = append(report.Pending, name)
+			continue
+		}
+		report.Removed = append(report.Removed, name)
+		if dryRun {
+			continue
+		}
+		if err := c.census.DelNamespace(name); err != nil {
+			log.Warnf("cannot remove census %s: (%v)", name, err)
+			continue
+		}
+		delete(c.gc.unused, name)
+	}
+	for name := range c.gc.unused {
+		if !known[name] {
+			delete(c.gc.unused, name)
+		}
+	}
+	return report, nil
+}
+
+// GCDaemon collects the census garbage every interval
+func (c *CensusDownloader) GCDaemon(interval, gracePeriod time.Duration, dryRun bool) {
+	log.Infof("starting census garbage collector, grace period %s", gracePeriod)
+	for {
+		time.Sleep(interval)
+		report, err := c.CollectGarbage(gracePeriod, dryRun)
+		if err != nil {
+			log.Warnf("census garbage collection failed: (%v)", err)
+			continue
+		}
+		if dryRun {
+			for _, name := range report.Removed {
+				log.Infof("census garbage collector would remove %s", name)
+			}
+		} else if len(report.Removed) > 0 {
+			log.Infof("census garbage collector removed %d census", len(report.Removed))
+		}
+		log.Debugf("census garbage collection: %d in use, %d within the grace period",
+			report.InUse, len(report.Pending))
+	}
+}
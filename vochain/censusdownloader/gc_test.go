@@ -0,0 +1,93 @@
+package censusdownloader
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"go.vocdoni.io/dvote/census"
+	"go.vocdoni.io/dvote/censustree/gravitontree"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+	"go.vocdoni.io/dvote/vochain"
+	"go.vocdoni.io/proto/build/go/models"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	app, err := vochain.NewBaseApplication(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m census.Manager
+	if err := m.Init(t.TempDir(), "", gravitontree.NewTree); err != nil {
+		t.Fatal(err)
+	}
+	used, unused, published := util.RandomHex(32), util.RandomHex(32), util.RandomHex(32)
+	for _, name := range []string{"0x00/local", used, unused, published} {
+		if _, err := m.AddNamespace(name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range m.Census.Namespaces {
+		switch ns := &m.Census.Namespaces[i]; ns.Name {
+		case used, unused:
+			ns.URI, ns.Imported = "ipfs://"+ns.Name, true
+		case published:
+			// the copy of a local census published by this node
+			ns.URI = "ipfs://" + ns.Name
+		}
+	}
+	root, err := hex.DecodeString(used)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.State.AddProcess(&models.Process{
+		ProcessId:    util.RandomBytes(types.ProcessIDsize),
+		EntityId:     util.RandomBytes(types.EntityIDsize),
+		EnvelopeType: &models.EnvelopeType{},
+		Mode:         &models.ProcessMode{},
+		Status:       models.ProcessStatus_READY,
+		CensusRoot:   root,
+		CensusOrigin: models.CensusOrigin_OFF_CHAIN_TREE,
+		BlockCount:   1024,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	app.State.Save()
+	c := NewCensusDownloader(app, &m, false)
+
+	// the unused census is within the grace period
+	report, err := c.CollectGarbage(time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.InUse != 1 || len(report.Removed) != 0 || len(report.Pending) != 1 || report.Pending[0] != unused {
+		t.Fatalf("wrong report %+v", report)
+	}
+
+	// a dry run reports the census without removing it
+	if report, err = c.CollectGarbage(0, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Removed) != 1 || report.Removed[0] != unused {
+		t.Fatalf("wrong dry run report %+v", report)
+	}
+	if !m.Exists(unused) {
+		t.Fatalf("census removed on a dry run")
+	}
+
+	if report, err = c.CollectGarbage(0, false); err != nil {
+		t.Fatal(err)
+	}
+	if report.InUse != 1 || len(report.Removed) != 1 || report.Removed[0] != unused || len(report.Pending) != 0 {
+		t.Fatalf("wrong report %+v", report)
+	}
+	if m.Exists(unused) {
+		t.Errorf("unused census was not removed")
+	}
+	for _, name := range []string{"0x00/local", used, published} {
+		if !m.Exists(name) {
+			t.Errorf("census %s was removed", name)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"go.vocdoni.io/dvote/statedb"
 	"go.vocdoni.io/dvote/types"
 	"go.vocdoni.io/proto/build/go/models"
 	"google.golang.org/protobuf/proto"
@@ -79,6 +80,29 @@ func (v *State) Process(pid []byte, isQuery bool) (*models.Process, error) {
 	return process, nil
 }
 
+// IterateProcesses calls fn for each process stored in the state, until fn
+// returns true or there are no more processes. The state is locked during the
+// iteration, so fn must not call other State methods.
+func (v *State) IterateProcesses(fn func(p *models.Process) bool, isQuery bool) (err error) {
+	v.RLock()
+	defer v.RUnlock()
+	var tree statedb.StateTree
+	if isQuery {
+		tree = v.Store.ImmutableTree(ProcessTree)
+	} else {
+		tree = v.Store.Tree(ProcessTree)
+	}
+	tree.Iterate(nil, func(key, value []byte) bool {
+		process := new(models.Process)
+		if err = proto.Unmarshal(value, process); err != nil {
+			err = fmt.Errorf("cannot unmarshal process (%x): %w", key, err)
+			return true
+		}
+		return fn(process)
+	})
+	return err
+}
+
 // CountProcesses returns the overall number of processes the vochain has
 func (v *State) CountProcesses(isQuery bool) int64 {
 	v.RLock()
@@ -207,6 +231,9 @@ func (v *State) SetProcessResults(pid []byte, result *models.ProcessResult, comm
 			if err := v.setProcess(process, process.ProcessId); err != nil {
 				return err
 			}
+			for _, l := range v.eventListeners {
+				l.OnProcessStatusChange(process.ProcessId, process.Status)
+			}
 		}
 		return nil
 	}
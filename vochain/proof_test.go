@@ -9,6 +9,7 @@ import (
 
 	blind "github.com/arnaucube/go-blindsecp256k1"
 	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmprototypes "github.com/tendermint/tendermint/proto/tendermint/types"
 	blindca "github.com/vocdoni/blind-ca/blindca"
 	"github.com/vocdoni/storage-proofs-eth-go/ethstorageproof"
 	tree "go.vocdoni.io/dvote/censustree/gravitontree"
@@ -104,6 +105,96 @@ func TestMerkleTreeProof(t *testing.T) {
 	}
 }
 
+func TestWeightedMerkleTreeProof(t *testing.T) {
+	app, err := NewBaseApplication(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := tree.NewTree("testweighted", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := util.CreateEthRandomKeysBatch(10)
+	for i, k := range keys {
+		weight := big.NewInt(int64(i + 1))
+		if err := tr.Add(snarks.Poseidon.Hash(k.PublicKey()), weight.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	censusURI := "ipfs://123456789"
+	pid := util.RandomBytes(types.ProcessIDsize)
+	process := &models.Process{
+		ProcessId:    pid,
+		StartBlock:   0,
+		EnvelopeType: &models.EnvelopeType{EncryptedVotes: false},
+		Mode:         &models.ProcessMode{},
+		Status:       models.ProcessStatus_READY,
+		EntityId:     util.RandomBytes(types.EntityIDsize),
+		CensusRoot:   tr.Root(),
+		CensusURI:    &censusURI,
+		CensusOrigin: models.CensusOrigin_OFF_CHAIN_TREE_WEIGHTED,
+		BlockCount:   1024,
+	}
+	// the genesis enables the weighted censuses from height 10
+	app.InitChain(abcitypes.RequestInitChain{AppStateBytes: []byte(`{"weightedCensusHeight":10}`)})
+	if h := app.State.WeightedCensusHeight(false); h != 10 {
+		t.Fatalf("weighted censuses enabled from height %d, expected 10", h)
+	}
+	app.BeginBlock(abcitypes.RequestBeginBlock{Header: tmprototypes.Header{Height: 9}})
+	app.State.AddProcess(process)
+	app.Commit()
+
+	voteTx := func(k *ethereum.SignKeys) *models.Tx {
+		proof, err := tr.GenProof(snarks.Poseidon.Hash(k.PublicKey()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := &models.VoteEnvelope{
+			Nonce:       util.RandomBytes(32),
+			ProcessId:   pid,
+			Proof:       &models.Proof{Payload: &models.Proof_Graviton{Graviton: &models.ProofGraviton{Siblings: proof}}},
+			VotePackage: []byte("[1,2,3,4]"),
+		}
+		txBytes, err := proto.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vtx := &models.Tx{Payload: &models.Tx_Vote{Vote: tx}}
+		if vtx.Signature, err = k.Sign(txBytes); err != nil {
+			t.Fatal(err)
+		}
+		return vtx
+	}
+	if _, err := VoteTxCheck(voteTx(keys[0]), app.State, [32]byte{}, false); err == nil {
+		t.Fatal("weighted census vote accepted before its activation height")
+	}
+
+	app.BeginBlock(abcitypes.RequestBeginBlock{Header: tmprototypes.Header{Height: 10}})
+	for i, k := range keys {
+		vote, err := VoteTxCheck(voteTx(k), app.State, [32]byte{byte(i)}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := new(big.Int).SetBytes(vote.Weight); w.Int64() != int64(i+1) {
+			t.Errorf("vote weight is %s, expected %d", w, i+1)
+		}
+	}
+
+	// a proof cannot be checked with a different weight
+	key := snarks.Poseidon.Hash(keys[0].PublicKey())
+	proof, err := tr.GenProof(key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := tree.CheckProof(key, big.NewInt(100).Bytes(), tr.Root(), proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Errorf("proof should not be valid for a different weight")
+	}
+}
+
 func TestCAProof(t *testing.T) {
 	app, err := NewBaseApplication(t.TempDir())
 	if err != nil {
@@ -0,0 +1,250 @@
+package scrutinizer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+
+	"go.vocdoni.io/dvote/crypto/ethereum"
+	"go.vocdoni.io/dvote/db"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+const (
+	// DocumentJSON is the JSON format of the results document
+	DocumentJSON = "json"
+	// DocumentCSV is the CSV format of the results document
+	DocumentCSV = "csv"
+)
+
+// ResultsDocument is the canonical record of the results of a process.
+// It is created once, when the results are computed, so its content does not
+// change over time, and it is kept by the scrutinizer reindex.
+type ResultsDocument struct {
+	ProcessID     types.HexBytes             `json:"processId"`
+	EntityID      types.HexBytes             `json:"entityId"`
+	Status        string                     `json:"status"`
+	StartBlock    uint32                     `json:"startBlock"`
+	EndBlock      uint32                     `json:"endBlock"`
+	CensusOrigin  string                     `json:"censusOrigin"`
+	CensusRoot    types.HexBytes             `json:"censusRoot"`
+	CensusURI     string                     `json:"censusUri"`
+	EnvelopeType  *models.EnvelopeType       `json:"envelopeType"`
+	Mode          *models.ProcessMode        `json:"mode"`
+	VoteOptions   *models.ProcessVoteOptions `json:"voteOptions"`
+	QuestionCount uint32                     `json:"questionCount"`
+	VoteCount     uint32                     `json:"voteCount"`
+	Results       [][]string                 `json:"results"`
+	// Height is the final height of the process, its end block or the block
+	// where it was ended or canceled
+	Height int64 `json:"height"`
+	// AppHash is the application hash included in the block header at Height
+	AppHash types.HexBytes `json:"appHash"`
+}
+
+// SignedResultsDocument wraps a results document with its signature
+type SignedResultsDocument struct {
+	Document  json.RawMessage `json:"document"`
+	Signature types.HexBytes  `json:"signature"`
+	Signer    string          `json:"signer"`
+}
+
+// ResultsDocument returns the results document of a process. If the results
+// are computed but the document does not exist yet, it is created.
+func (s *Scrutinizer) ResultsDocument(pid []byte) (*ResultsDocument, error) {
+	data, err := s.Storage.Get(s.Encode("document", pid))
+	if err == nil {
+		doc := new(ResultsDocument)
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	if err != badger.ErrKeyNotFound {
+		return nil, err
+	}
+	return s.createResultsDocument(pid)
+}
+
+// createResultsDocument builds the results document of a process and stores it
+func (s *Scrutinizer) createResultsDocument(pid []byte) (*ResultsDocument, error) {
+	p, err := s.VochainState.Process(pid, false)
+	if err != nil {
+		return nil, err
+	}
+	resultsBytes, err := s.Storage.Get(s.Encode("results", pid))
+	if err == badger.ErrKeyNotFound {
+		return nil, ErrNoResultsYet
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pv models.ProcessResult
+	if err := proto.Unmarshal(resultsBytes, &pv); err != nil {
+		return nil, err
+	}
+	header, err := s.finalHeader(pid)
+	if err != nil {
+		return nil, err
+	}
+	doc := &ResultsDocument{
+		ProcessID:     p.ProcessId,
+		EntityID:      p.EntityId,
+		Status:        p.Status.String(),
+		StartBlock:    p.StartBlock,
+		EndBlock:      p.StartBlock + p.BlockCount,
+		CensusOrigin:  p.CensusOrigin.String(),
+		CensusRoot:    p.CensusRoot,
+		CensusURI:     p.GetCensusURI(),
+		EnvelopeType:  p.EnvelopeType,
+		Mode:          p.Mode,
+		VoteOptions:   p.VoteOptions,
+		QuestionCount: p.GetQuestionCount(),
+		VoteCount:     s.VochainState.CountVotes(pid, false),
+		Results:       s.GetFriendlyResults(&pv),
+		Height:        header.Height,
+		AppHash:       header.AppHash,
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Put(s.Encode("document", pid), data); err != nil {
+		return nil, err
+	}
+	log.Infof("created results document for process %x at height %d", pid, doc.Height)
+	return doc, nil
+}
+
+// finalHeader returns the height and AppHash of the final block of a process
+func (s *Scrutinizer) finalHeader(pid []byte) (*models.TendermintHeader, error) {
+	data, err := s.Storage.Get(s.Encode("finalHeader", pid))
+	if err == badger.ErrKeyNotFound {
+		return nil, fmt.Errorf("final block of process %x not recorded", pid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	header := new(models.TendermintHeader)
+	if err := proto.Unmarshal(data, header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// recordFinalHeaders stores the height and AppHash of the block at height as
+// the final block of the processes ending at height and of the ones ended or
+// canceled in pids, unless one was recorded before. Like the documents, the
+// final blocks cannot be derived from the state, so the reindex keeps them.
+func (s *Scrutinizer) recordFinalHeaders(height int64, pids [][]byte) {
+	header := s.VochainState.Header(false)
+	if header == nil || header.Height != height {
+		log.Errorf("cannot get the vochain header at height %d", height)
+		return
+	}
+	value, err := proto.Marshal(&models.TendermintHeader{Height: header.Height, AppHash: header.AppHash})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	prefix := s.Encode("processIndex", indexEntry(indexPrefix(indexAll, nil), uint32(height), nil))
+	iter := s.Storage.NewIterator().(*db.BadgerIterator) // TODO(mvdan): don't type assert
+	for iter.Iter.Seek(prefix); iter.Iter.ValidForPrefix(prefix); iter.Iter.Next() {
+		pids = append(pids, append([]byte{}, iter.Key()[len(prefix):]...))
+	}
+	iter.Release()
+	for _, pid := range pids {
+		if p, err := s.processSummary(pid); err != nil || (endBlock(p) != uint32(height) &&
+			p.Status != models.ProcessStatus_ENDED && p.Status != models.ProcessStatus_CANCELED) {
+			continue
+		}
+		key := s.Encode("finalHeader", pid)
+		if _, err := s.Storage.Get(key); err == nil {
+			continue
+		}
+		if err := s.Storage.Put(key, value); err != nil {
+			log.Errorf("cannot store the final block of process %x: (%s)", pid, err)
+		}
+	}
+}
+
+// Encode returns the document encoded in the given format (json or csv)
+func (d *ResultsDocument) Encode(format string) ([]byte, error) {
+	switch format {
+	case DocumentJSON:
+		return json.Marshal(d)
+	case DocumentCSV:
+		return d.csv()
+	}
+	return nil, fmt.Errorf("unknown results document format %q", format)
+}
+
+// Sign encodes the document in the given format and signs it. The signer
+// address can be recovered from the returned content and signature.
+func (d *ResultsDocument) Sign(signer *ethereum.SignKeys, format string) (content, signature []byte, err error) {
+	if content, err = d.Encode(format); err != nil {
+		return nil, nil, err
+	}
+	if signature, err = signer.Sign(content); err != nil {
+		return nil, nil, err
+	}
+	return content, signature, nil
+}
+
+// Signed returns the document in JSON format along with its signature
+func (d *ResultsDocument) Signed(signer *ethereum.SignKeys) (*SignedResultsDocument, error) {
+	content, signature, err := d.Sign(signer, DocumentJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedResultsDocument{
+		Document:  content,
+		Signature: signature,
+		Signer:    signer.AddressString(),
+	}, nil
+}
+
+// csv encodes the document as CSV with the columns field,question,option,value.
+// Process fields leave the question and option columns empty, while results
+// have one row per question option.
+func (d *ResultsDocument) csv() ([]byte, error) {
+	et := d.EnvelopeType
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{
+		{"field", "question", "option", "value"},
+		{"processId", "", "", hex.EncodeToString(d.ProcessID)},
+		{"entityId", "", "", hex.EncodeToString(d.EntityID)},
+		{"status", "", "", d.Status},
+		{"startBlock", "", "", strconv.FormatUint(uint64(d.StartBlock), 10)},
+		{"endBlock", "", "", strconv.FormatUint(uint64(d.EndBlock), 10)},
+		{"censusOrigin", "", "", d.CensusOrigin},
+		{"censusRoot", "", "", hex.EncodeToString(d.CensusRoot)},
+		{"censusUri", "", "", d.CensusURI},
+		{"serial", "", "", strconv.FormatBool(et.GetSerial())},
+		{"anonymous", "", "", strconv.FormatBool(et.GetAnonymous())},
+		{"encryptedVotes", "", "", strconv.FormatBool(et.GetEncryptedVotes())},
+		{"uniqueValues", "", "", strconv.FormatBool(et.GetUniqueValues())},
+		{"questionCount", "", "", strconv.FormatUint(uint64(d.QuestionCount), 10)},
+		{"voteCount", "", "", strconv.FormatUint(uint64(d.VoteCount), 10)},
+		{"height", "", "", strconv.FormatInt(d.Height, 10)},
+		{"appHash", "", "", hex.EncodeToString(d.AppHash)},
+	}
+	for q, options := range d.Results {
+		for o, votes := range options {
+			records = append(records, []string{"votes", strconv.Itoa(q), strconv.Itoa(o), votes})
+		}
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
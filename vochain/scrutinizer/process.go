@@ -182,6 +182,20 @@ func (s *Scrutinizer) Encode(t string, data []byte) []byte {
 		return append([]byte{types.ScrutinizerResultsPrefix}, data...)
 	case "processEnding":
 		return append([]byte{types.ScrutinizerProcessEndingPrefix}, data...)
+	case "process":
+		return append([]byte{types.ScrutinizerProcessPrefix}, data...)
+	case "processIndex":
+		return append([]byte{types.ScrutinizerProcessIndexPrefix}, data...)
+	case "stats":
+		return append([]byte{types.ScrutinizerStatsPrefix}, data...)
+	case "document":
+		return append([]byte{types.ScrutinizerDocumentPrefix}, data...)
+	case "snapshot":
+		return append([]byte{types.ScrutinizerSnapshotPrefix}, data...)
+	case "finalHeader":
+		return append([]byte{types.ScrutinizerFinalHeaderPrefix}, data...)
+	case "blockVotes":
+		return append([]byte{types.ScrutinizerBlockVotesPrefix}, data...)
 	}
 	panic("scrutinizer encode type not known")
 }
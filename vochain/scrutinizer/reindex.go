@@ -0,0 +1,411 @@
+package scrutinizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+
+	"go.vocdoni.io/dvote/db"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/util"
+)
+
+// ReindexReport summarizes a reindex (or verify) run of the scrutinizer database
+type ReindexReport struct {
+	Entities    int
+	Processes   int
+	LiveResults int
+	Results     int
+	// Differences lists the entries of the scrutinizer database which do not
+	// match the ones computed from the Vochain state
+	Differences []string
+}
+
+// index holds the scrutinizer database entries computed from the Vochain state
+type index struct {
+	entities  map[string][]byte
+	summaries map[string]*models.Process
+	live      map[string]*models.ProcessResult
+	results   map[string]*models.ProcessResult
+	stats     map[string]*ProcessStats
+}
+
+// Reindex rebuilds the scrutinizer database from the Vochain state: entities,
+// process lists, process summaries, statistics, live results and final
+// results. If verify is true nothing is written and the differences found are
+// reported instead.
+// It must not run while the Vochain is adding new blocks.
+func (s *Scrutinizer) Reindex(verify bool) (*ReindexReport, error) {
+	var processes []*models.Process
+	if err := s.VochainState.IterateProcesses(func(p *models.Process) bool {
+		processes = append(processes, p)
+		return false
+	}, false); err != nil {
+		return nil, err
+	}
+	// Keep the creation order as much as possible for the entity process lists
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].StartBlock < processes[j].StartBlock
+	})
+
+	report := &ReindexReport{Processes: len(processes)}
+	idx := &index{
+		entities:  make(map[string][]byte),
+		summaries: make(map[string]*models.Process),
+		live:      make(map[string]*models.ProcessResult),
+		results:   make(map[string]*models.ProcessResult),
+		stats:     make(map[string]*ProcessStats),
+	}
+	for _, p := range processes {
+		pid := string(p.ProcessId)
+		idx.summaries[pid] = newProcessSummary(p)
+		eid := string(p.EntityId)
+		if _, ok := idx.entities[eid]; !ok {
+			report.Entities++
+		}
+		idx.entities[eid] = append(idx.entities[eid], p.ProcessId...)
+
+		pv, st, err := s.rebuildVotes(p)
+		if err != nil {
+			return nil, err
+		}
+		idx.stats[pid] = st
+		isLive := pv != nil
+		if !resultsAvailable(p) {
+			if isLive {
+				idx.live[pid] = pv
+				report.LiveResults++
+			}
+			continue
+		}
+		if isLive {
+			pv = pruneVoteResult(pv)
+		} else if pv, st.InvalidEnvelopes, err = s.computeNonLiveResults(p); err != nil {
+			return nil, err
+		}
+		idx.results[pid] = pv
+		report.Results++
+	}
+
+	if verify {
+		report.Differences = s.verifyIndex(idx)
+		return report, nil
+	}
+	return report, s.writeIndex(idx)
+}
+
+// resultsAvailable returns true if all the process keys have been revealed,
+// which is when the scrutinizer schedules the results computation.
+func resultsAvailable(p *models.Process) bool {
+	if p.KeyIndex == nil || *p.KeyIndex > 0 {
+		return false
+	}
+	for i := range p.EncryptionPrivateKeys {
+		if p.EncryptionPrivateKeys[i] != "" {
+			return true
+		}
+	}
+	for i := range p.RevealKeys {
+		if p.RevealKeys[i] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildVotes iterates over all the process envelopes computing its
+// statistics and, if the process has live results, adding the votes to a new
+// empty live results entry like addLiveResultsVote does during the block
+// processing. The returned live results are nil for encrypted processes.
+func (s *Scrutinizer) rebuildVotes(p *models.Process) (*models.ProcessResult, *ProcessStats, error) {
+	var pv *models.ProcessResult
+	if !p.GetEnvelopeType().GetEncryptedVotes() {
+		pv = emptyProcess(0, 0)
+	}
+	st := newProcessStats()
+	for _, n := range s.VochainState.EnvelopeList(p.ProcessId, 0, 32<<18, false) {
+		vote, err := s.VochainState.Envelope(p.ProcessId, n, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		st.addEnvelope(vote)
+		if pv == nil {
+			continue
+		}
+		vp, err := unmarshalVote(vote.VotePackage, []string{})
+		if err != nil || len(vp.Votes) > MaxQuestions {
+			log.Debugf("skipping invalid live vote %x on process %x", n, p.ProcessId)
+			st.InvalidEnvelopes++
+			continue
+		}
+		addVote(pv.Votes, vp.Votes, vote.GetWeight())
+	}
+	return pv, st, nil
+}
+
+func (s *Scrutinizer) writeIndex(idx *index) error {
+	batch := s.Storage.NewBatch()
+	// The processEnding entries are kept, they schedule the results
+	// computation, which notifies the event listeners. The results documents
+	// and the final blocks of the processes are kept too, they are official
+	// records and cannot be derived from the state.
+	for _, prefix := range []string{"entity", "liveProcess", "results", "process", "processIndex", "stats", "blockVotes"} {
+		for key := range s.prefixEntries(s.Encode(prefix, nil)) {
+			if err := batch.Del([]byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	for eid, pids := range idx.entities {
+		if err := batch.Put(s.Encode("entity", []byte(eid)), pids); err != nil {
+			return err
+		}
+	}
+	for pid, p := range idx.summaries {
+		value, err := proto.Marshal(p)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(s.Encode("process", []byte(pid)), value); err != nil {
+			return err
+		}
+		for _, key := range processIndexKeys(p) {
+			if err := batch.Put(s.Encode("processIndex", key), nil); err != nil {
+				return err
+			}
+		}
+	}
+	for prefix, m := range map[string]map[string]*models.ProcessResult{
+		"liveProcess": idx.live,
+		"results":     idx.results,
+	} {
+		for pid, pv := range m {
+			value, err := proto.Marshal(pv)
+			if err != nil {
+				return err
+			}
+			if err := batch.Put(s.Encode(prefix, []byte(pid)), value); err != nil {
+				return err
+			}
+		}
+	}
+	for pid, st := range idx.stats {
+		value, err := json.Marshal(st)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(s.Encode("stats", []byte(pid)), value); err != nil {
+			return err
+		}
+		for height, n := range st.VotesPerBlock {
+			value := make([]byte, 8)
+			binary.BigEndian.PutUint64(value, n)
+			if err := batch.Put(s.blockVotesKey([]byte(pid), height), value); err != nil {
+				return err
+			}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	atomic.StoreInt64(&s.entityCount, int64(len(idx.entities)))
+	log.Infof("scrutinizer reindexed: %d entities, %d live results, %d results",
+		len(idx.entities), len(idx.live), len(idx.results))
+	return nil
+}
+
+func (s *Scrutinizer) verifyIndex(idx *index) []string {
+	var diffs []string
+	stored := s.prefixEntries(s.Encode("entity", nil))
+	for eid, pids := range idx.entities {
+		key := string(s.Encode("entity", []byte(eid)))
+		if _, ok := stored[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("entity %x: missing", eid))
+		} else if !samePIDs(stored[key], pids) {
+			diffs = append(diffs, fmt.Sprintf("entity %x: process list differs", eid))
+		}
+		delete(stored, key)
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("entity %x: not found in state", key[1:]))
+	}
+
+	stored = s.prefixEntries(s.Encode("process", nil))
+	for pid, p := range idx.summaries {
+		key := string(s.Encode("process", []byte(pid)))
+		value, ok := stored[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("process %x: missing", pid))
+		} else if sp := new(models.Process); proto.Unmarshal(value, sp) != nil || !proto.Equal(sp, p) {
+			diffs = append(diffs, fmt.Sprintf("process %x: summary differs", pid))
+		}
+		delete(stored, key)
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("process %x: not found in state", key[1:]))
+	}
+
+	// a missing process summary is already reported, so its index entries
+	// are only reported if the summary exists
+	summaries := s.prefixEntries(s.Encode("process", nil))
+	stored = s.prefixEntries(s.Encode("processIndex", nil))
+	for pid, p := range idx.summaries {
+		_, indexed := summaries[string(s.Encode("process", []byte(pid)))]
+		missing := false
+		for _, key := range processIndexKeys(p) {
+			key := string(s.Encode("processIndex", key))
+			if _, ok := stored[key]; !ok {
+				missing = true
+			}
+			delete(stored, key)
+		}
+		if indexed && missing {
+			diffs = append(diffs, fmt.Sprintf("process %x: search index entries missing", pid))
+		}
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("process index %x: not expected", key[1:]))
+	}
+
+	stored = s.prefixEntries(s.Encode("liveProcess", nil))
+	for pid, pv := range idx.live {
+		key := string(s.Encode("liveProcess", []byte(pid)))
+		diffs = append(diffs, diffResults("live results", []byte(pid), stored, key,
+			printLiveResults(pv), printLiveResults)...)
+		delete(stored, key)
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("live results %x: not expected", key[1:]))
+	}
+
+	stored = s.prefixEntries(s.Encode("results", nil))
+	for pid, pv := range idx.results {
+		key := string(s.Encode("results", []byte(pid)))
+		diffs = append(diffs, diffResults("results", []byte(pid), stored, key,
+			PrintResults(pv), PrintResults)...)
+		delete(stored, key)
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("results %x: not expected", key[1:]))
+	}
+	// the documents are kept by the reindex, so only their results are checked
+	for key, value := range s.prefixEntries(s.Encode("document", nil)) {
+		pid := key[1:]
+		var doc ResultsDocument
+		if err := json.Unmarshal(value, &doc); err != nil {
+			diffs = append(diffs, fmt.Sprintf("document %x: cannot unmarshal: %v", pid, err))
+		} else if pv, ok := idx.results[pid]; !ok {
+			diffs = append(diffs, fmt.Sprintf("document %x: process without results", pid))
+		} else if got, expected := fmt.Sprint(doc.Results), fmt.Sprint(s.GetFriendlyResults(pv)); got != expected {
+			diffs = append(diffs, fmt.Sprintf("document %x: got results %s expected %s", pid, got, expected))
+		}
+	}
+	stored = s.prefixEntries(s.Encode("stats", nil))
+	for pid, st := range idx.stats {
+		key := string(s.Encode("stats", []byte(pid)))
+		value, ok := stored[key]
+		if st.Envelopes == 0 && !ok {
+			// stats are only stored once the first envelope arrives
+			continue
+		}
+		expected, err := json.Marshal(st)
+		if err != nil {
+			diffs = append(diffs, fmt.Sprintf("stats %x: %v", pid, err))
+		} else if !ok {
+			diffs = append(diffs, fmt.Sprintf("stats %x: missing", pid))
+		} else if !bytes.Equal(value, expected) {
+			diffs = append(diffs, fmt.Sprintf("stats %x: got %s expected %s", pid, value, expected))
+		}
+		delete(stored, key)
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("stats %x: not expected", key[1:]))
+	}
+
+	stored = s.prefixEntries(s.Encode("blockVotes", nil))
+	for pid, st := range idx.stats {
+		for height, n := range st.VotesPerBlock {
+			key := string(s.blockVotesKey([]byte(pid), height))
+			if value, ok := stored[key]; !ok {
+				diffs = append(diffs, fmt.Sprintf("votes of %x on block %d: missing", pid, height))
+			} else if len(value) != 8 || binary.BigEndian.Uint64(value) != n {
+				diffs = append(diffs, fmt.Sprintf("votes of %x on block %d: got %x expected %d", pid, height, value, n))
+			}
+			delete(stored, key)
+		}
+	}
+	for key := range stored {
+		diffs = append(diffs, fmt.Sprintf("block votes %x: not expected", key[1:]))
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// diffResults compares the stored results under key with the expected ones,
+// both printed with the given function.
+func diffResults(name string, pid []byte, stored map[string][]byte, key, expected string,
+	format func(*models.ProcessResult) string) []string {
+	value, ok := stored[key]
+	if !ok {
+		return []string{fmt.Sprintf("%s %x: missing", name, pid)}
+	}
+	var pv models.ProcessResult
+	if err := proto.Unmarshal(value, &pv); err != nil {
+		return []string{fmt.Sprintf("%s %x: cannot unmarshal: %v", name, pid, err)}
+	}
+	if got := format(&pv); got != expected {
+		return []string{fmt.Sprintf("%s %x: got%s expected%s", name, pid, got, expected)}
+	}
+	return nil
+}
+
+// printLiveResults returns the human friendly live results, which are stored
+// without pruning.
+func printLiveResults(pv *models.ProcessResult) string {
+	if len(pv.Votes) != MaxQuestions {
+		return " malformed"
+	}
+	for _, q := range pv.Votes {
+		if len(q.Question) != MaxOptions {
+			return " malformed"
+		}
+	}
+	return PrintResults(pruneVoteResult(pv))
+}
+
+// samePIDs returns true if both process lists contain the same process ids,
+// regardless of their order.
+func samePIDs(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := util.SplitBytes(a, types.ProcessIDsize)
+	sb := util.SplitBytes(b, types.ProcessIDsize)
+	for _, l := range [][][]byte{sa, sb} {
+		sort.Slice(l, func(i, j int) bool { return bytes.Compare(l[i], l[j]) < 0 })
+	}
+	for i := range sa {
+		if !bytes.Equal(sa[i], sb[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// prefixEntries returns all the database entries whose key starts with prefix
+func (s *Scrutinizer) prefixEntries(prefix []byte) map[string][]byte {
+	entries := make(map[string][]byte)
+	iter := s.Storage.NewIterator().(*db.BadgerIterator) // TODO(mvdan): don't type assert
+	defer iter.Release()
+	for iter.Iter.Seek(prefix); iter.Iter.ValidForPrefix(prefix); iter.Iter.Next() {
+		entries[string(iter.Key())] = iter.Value()
+	}
+	return entries
+}
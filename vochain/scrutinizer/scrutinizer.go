@@ -1,18 +1,25 @@
 package scrutinizer
 
 /*
-	Scrutinizer keeps 4 different database entries (splited by key prefix)
+	Scrutinizer keeps 10 different database entries (splited by key prefix)
 
 	+ ProcessEnding: key is block number. Used for schedule results computing
 	+ LiveProcess: key is processId. Temporary storage for live results (poll-vote)
 	+ Entity: key is entityId: List of known entities
 	+ Results: key is processId: Final results for a process
+	+ Process: key is processId: Process summary used for searching
+	+ ProcessIndex: key is index+value+endBlock+processId: Secondary indexes for searching
+	+ Stats: key is processId: Participation statistics of a process
+	+ Document: key is processId: Results document of a process
+	+ FinalHeader: key is processId: Height and AppHash of the final block of a process
+	+ Snapshot: key is processId+height: Live results snapshot of a process at a height
 */
 
 import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"go.vocdoni.io/dvote/db"
 	"go.vocdoni.io/dvote/log"
@@ -47,14 +54,26 @@ type Scrutinizer struct {
 	votePool       []*models.Vote
 	processPool    []*types.ScrutinizerOnProcessData
 	resultsPool    []*types.ScrutinizerOnProcessData
+	statusPool     [][]byte
 	entityCount    int64
 	eventListeners []EventListener
+	// SnapshotInterval is the minimum number of blocks between two live
+	// results snapshots of a process
+	SnapshotInterval uint32
+	snapshotLock     sync.Mutex
+	snapshotPending  map[string]bool
+	lastSnapshot     map[string]int64
 }
 
 // NewScrutinizer returns an instance of the Scrutinizer
 // using the local storage database of dbPath and integrated into the state vochain instance
 func NewScrutinizer(dbPath string, state *vochain.State) (*Scrutinizer, error) {
-	s := &Scrutinizer{VochainState: state}
+	s := &Scrutinizer{
+		VochainState:     state,
+		SnapshotInterval: DefaultSnapshotInterval,
+		snapshotPending:  make(map[string]bool),
+		lastSnapshot:     make(map[string]int64),
+	}
 	var err error
 	s.Storage, err = db.NewBadgerDB(dbPath)
 	if err != nil {
@@ -77,6 +96,7 @@ func (s *Scrutinizer) Commit(height int64) {
 	var nvotes int64
 	for _, p := range s.processPool {
 		s.addEntity(p.EntityID, p.ProcessID)
+		s.indexProcess(p.ProcessID)
 		if isLive, err = s.isLiveResultsProcess(p.ProcessID); err != nil {
 			log.Errorf("cannot check if process is live results: (%s)", err)
 			continue
@@ -86,18 +106,56 @@ func (s *Scrutinizer) Commit(height int64) {
 		}
 	}
 
+	// Update the status of the indexed processes
+	for _, pid := range s.statusPool {
+		s.indexProcess(pid)
+	}
+	s.recordFinalHeaders(height, s.statusPool)
+
 	for i, p := range s.resultsPool {
 		s.registerPendingProcess(p.ProcessID, height+int64(i+1))
 	}
 
-	// Add votes collected by onVote (live results)
+	// Add votes collected by onVote (statistics and live results)
+	stats := make(map[string]*ProcessStats)
+	live := make(map[string]bool)
+	snapshot := make(map[string]bool)
 	for _, v := range s.votePool {
+		pid := string(v.ProcessId)
+		st, ok := stats[pid]
+		if !ok {
+			if st, err = s.loadStats(v.ProcessId); err != nil {
+				log.Errorf("cannot load stats for process %x: (%s)", v.ProcessId, err)
+				continue
+			}
+			stats[pid] = st
+			if live[pid], err = s.isLiveResultsProcess(v.ProcessId); err != nil {
+				log.Errorf("cannot check if process is live results: (%s)", err)
+			}
+		}
+		st.addEnvelope(v)
+		if !live[pid] {
+			continue
+		}
 		if err = s.addLiveResultsVote(v); err != nil {
+			st.InvalidEnvelopes++
 			log.Errorf("cannot add live vote: (%s)", err)
 			continue
 		}
+		snapshot[pid] = true
 		nvotes++
 	}
+	s.addSnapshotVotes(height, snapshot)
+	for pid, st := range stats {
+		if err := s.storeStats([]byte(pid), st); err != nil {
+			log.Errorf("cannot store stats for process %x: (%s)", pid, err)
+		}
+		// the stats are loaded without the votes per block, so they only
+		// have the ones of this block
+		if err := s.addBlockVotes([]byte(pid), st.VotesPerBlock); err != nil {
+			log.Errorf("cannot store votes per block for process %x: (%s)", pid, err)
+		}
+	}
 	if nvotes > 0 {
 		log.Infof("added %d live votes from block %d", nvotes, height)
 	}
@@ -108,6 +166,7 @@ func (s *Scrutinizer) Rollback() {
 	s.votePool = []*models.Vote{}
 	s.processPool = []*types.ScrutinizerOnProcessData{}
 	s.resultsPool = []*types.ScrutinizerOnProcessData{}
+	s.statusPool = [][]byte{}
 }
 
 // OnProcess scrutinizer stores the processID and entityID
@@ -116,16 +175,9 @@ func (s *Scrutinizer) OnProcess(pid, eid []byte, censusRoot, censusURI string) {
 	s.processPool = append(s.processPool, data)
 }
 
-// OnVote scrutinizer stores the votes if liveResults enabled
+// OnVote scrutinizer stores the votes for the statistics and the live results
 func (s *Scrutinizer) OnVote(v *models.Vote) {
-	isLive, err := s.isLiveResultsProcess(v.ProcessId)
-	if err != nil {
-		log.Errorf("cannot check if process is live results: (%s)", err)
-		return
-	}
-	if isLive {
-		s.votePool = append(s.votePool, v)
-	}
+	s.votePool = append(s.votePool, v)
 }
 
 // OnCancel scrutinizer stores the processID and entityID
@@ -138,8 +190,9 @@ func (s *Scrutinizer) OnProcessKeys(pid []byte, pub, com string) {
 	// do nothing
 }
 
+// OnProcessStatusChange scrutinizer stores the processID for updating its summary
 func (s *Scrutinizer) OnProcessStatusChange(pid []byte, status models.ProcessStatus) {
-	// do nothing
+	s.statusPool = append(s.statusPool, pid)
 }
 
 // OnRevealKeys checks if all keys have been revealed and in such case add the process to the results queue
@@ -1,6 +1,8 @@
 package scrutinizer
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -14,6 +16,7 @@ import (
 	"go.vocdoni.io/dvote/util"
 	"go.vocdoni.io/dvote/vochain"
 	"go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestEntityList(t *testing.T) {
@@ -286,3 +289,466 @@ func TestLiveResults(t *testing.T) {
 		}
 	}
 }
+
+func TestReindex(t *testing.T) {
+	log.Init("info", "stdout")
+	state, err := vochain.NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewScrutinizer(t.TempDir(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eid := util.RandomBytes(20)
+	pid := util.RandomBytes(32)
+	if err := state.AddProcess(&models.Process{
+		ProcessId:    pid,
+		EntityId:     eid,
+		EnvelopeType: &models.EnvelopeType{EncryptedVotes: false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	vp, err := json.Marshal(types.VotePackage{Votes: []int{1, 0, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := state.AddVote(&models.Vote{
+			ProcessId:   pid,
+			VotePackage: vp,
+			Nullifier:   util.RandomBytes(32),
+			Weight:      big.NewInt(1).Bytes(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc.Rollback()
+
+	// The scrutinizer database is empty, so verify must report differences
+	report, err := sc.Reindex(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Differences) != 5 {
+		t.Fatalf("expected 5 differences, got %v", report.Differences)
+	}
+	if _, err := sc.ProcessList(eid, nil, 10); err == nil {
+		t.Fatalf("verify must not write to the database")
+	}
+
+	// A scheduled results computation must survive the reindex
+	sc.registerPendingProcess(pid, 100)
+	report, err = sc.Reindex(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entities != 1 || report.Processes != 1 || report.LiveResults != 1 {
+		t.Fatalf("unexpected reindex report: %+v", report)
+	}
+	if _, err := sc.Storage.Get(sc.Encode("processEnding", []byte("100"))); err != nil {
+		t.Fatalf("scheduled results computation lost after reindex: %v", err)
+	}
+	if report, err = sc.Reindex(true); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Differences) != 0 {
+		t.Fatalf("expected no differences after reindex, got %v", report.Differences)
+	}
+
+	list, err := sc.ProcessList(eid, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || string(list[0]) != string(pid) {
+		t.Fatalf("unexpected process list %x", list)
+	}
+	result, err := sc.VoteResult(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := PrintResults(result); got != " [0,10] [10] [0,0,10]" {
+		t.Fatalf("unexpected results:%s", got)
+	}
+	stats, err := sc.ProcessStats(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Envelopes != 10 || stats.TotalWeight.Int64() != 10 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessStats(t *testing.T) {
+	log.Init("info", "stdout")
+	state, err := vochain.NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewScrutinizer(t.TempDir(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := util.RandomBytes(32)
+	if err := state.AddProcess(&models.Process{
+		ProcessId:    pid,
+		EntityId:     util.RandomBytes(20),
+		EnvelopeType: &models.EnvelopeType{EncryptedVotes: false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sc.Commit(1)
+	sc.Rollback()
+
+	vp, err := json.Marshal(types.VotePackage{Votes: []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := state.AddVote(&models.Vote{
+			ProcessId:   pid,
+			VotePackage: vp,
+			Nullifier:   util.RandomBytes(32),
+			Weight:      big.NewInt(3).Bytes(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A vote package which cannot be decoded
+	if err := state.AddVote(&models.Vote{
+		ProcessId:   pid,
+		VotePackage: []byte("invalid"),
+		Nullifier:   util.RandomBytes(32),
+		Weight:      big.NewInt(1).Bytes(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sc.Commit(2)
+
+	stats, err := sc.ProcessStats(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Envelopes != 6 || stats.InvalidEnvelopes != 1 || stats.TotalWeight.Int64() != 16 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.Turnout(12) != 50 {
+		t.Fatalf("unexpected turnout %f", stats.Turnout(12))
+	}
+	// The votes per block are added to the stored ones on each commit, and
+	// are not kept in the stats entry
+	sc.Rollback()
+	if err := state.AddVote(&models.Vote{
+		ProcessId:   pid,
+		VotePackage: vp,
+		Nullifier:   util.RandomBytes(32),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sc.Commit(3)
+	if stats, err = sc.ProcessStats(pid); err != nil {
+		t.Fatal(err)
+	}
+	var votes uint64
+	for _, n := range stats.VotesPerBlock {
+		votes += n
+	}
+	if votes != 7 {
+		t.Fatalf("expected 7 votes per block, got %v", stats.VotesPerBlock)
+	}
+	stored, err := sc.Storage.Get(sc.Encode("stats", pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored, []byte("votesPerBlock")) {
+		t.Errorf("the votes per block are stored in the stats entry: %s", stored)
+	}
+	// The stats computed by the reindex must match
+	report, err := sc.Reindex(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Differences) != 0 {
+		t.Fatalf("expected no differences, got %v", report.Differences)
+	}
+}
+
+func TestSearchProcesses(t *testing.T) {
+	log.Init("info", "stdout")
+	state, err := vochain.NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewScrutinizer(t.TempDir(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eid := util.RandomBytes(20)
+	for i := 0; i < 30; i++ {
+		p := &models.Process{
+			ProcessId:    util.RandomBytes(32),
+			EntityId:     eid,
+			StartBlock:   uint32(i * 10),
+			BlockCount:   100,
+			Status:       models.ProcessStatus_READY,
+			EnvelopeType: &models.EnvelopeType{EncryptedVotes: i%2 == 0},
+			Mode:         &models.ProcessMode{Interruptible: true},
+		}
+		if i%3 == 0 {
+			p.Status = models.ProcessStatus_ENDED
+		}
+		if err := state.AddProcess(p); err != nil {
+			t.Fatal(err)
+		}
+		sc.indexProcess(p.ProcessId)
+	}
+
+	encrypted := true
+	filter := &ProcessFilter{
+		EntityID:       eid,
+		Status:         models.ProcessStatus_READY,
+		EncryptedVotes: &encrypted,
+		EndBlockFrom:   150,
+		EndBlockTo:     350,
+	}
+	// Expected: i in {8,10,14,16,20,22} (even, not multiple of 3, 50 <= i*10 <= 250)
+	var found []*models.Process
+	var last []byte
+	for {
+		list, err := sc.SearchProcesses(filter, last, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		found = append(found, list...)
+		last = list[len(list)-1].ProcessId
+	}
+	if len(found) != 6 {
+		t.Fatalf("expected 6 processes, got %d", len(found))
+	}
+	for i, p := range found {
+		if !filter.Match(p) {
+			t.Fatalf("process %x does not match the filter", p.ProcessId)
+		}
+		if i > 0 && endBlock(p) < endBlock(found[i-1]) {
+			t.Fatalf("processes are not sorted by end block")
+		}
+	}
+	if list, _ := sc.SearchProcesses(nil, nil, 100); len(list) != 30 {
+		t.Fatalf("expected 30 processes, got %d", len(list))
+	}
+
+	// A status change must move the process to the index of the new status
+	if err := state.SetProcessStatus(found[0].ProcessId, models.ProcessStatus_ENDED, true); err != nil {
+		t.Fatal(err)
+	}
+	sc.indexProcess(found[0].ProcessId)
+	list, err := sc.SearchProcesses(filter, nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 5 {
+		t.Fatalf("expected 5 processes after the status change, got %d", len(list))
+	}
+	end := endBlock(found[0])
+	ended := &ProcessFilter{Status: models.ProcessStatus_ENDED, EndBlockFrom: end, EndBlockTo: end}
+	if list, _ := sc.SearchProcesses(ended, nil, 100); len(list) != 1 ||
+		!bytes.Equal(list[0].ProcessId, found[0].ProcessId) {
+		t.Fatalf("expected the ended process ending at block %d, got %v", end, list)
+	}
+}
+
+func TestResultsDocument(t *testing.T) {
+	log.Init("info", "stdout")
+	state, err := vochain.NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewScrutinizer(t.TempDir(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := util.RandomBytes(32)
+	keyIndex := uint32(0)
+	if err := state.AddProcess(&models.Process{
+		ProcessId:    pid,
+		EntityId:     util.RandomBytes(20),
+		CensusRoot:   util.RandomBytes(32),
+		StartBlock:   1,
+		BlockCount:   10,
+		KeyIndex:     &keyIndex,
+		EnvelopeType: &models.EnvelopeType{EncryptedVotes: false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sc.Commit(1)
+	sc.Rollback()
+
+	if _, err := sc.ResultsDocument(pid); err != ErrNoResultsYet {
+		t.Fatalf("expected ErrNoResultsYet, got %v", err)
+	}
+
+	vp, err := json.Marshal(types.VotePackage{Votes: []int{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := state.AddVote(&models.Vote{
+			ProcessId:   pid,
+			VotePackage: vp,
+			Nullifier:   util.RandomBytes(32),
+			Weight:      big.NewInt(1).Bytes(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Save commits the scrutinizer too, at the header height
+	header, err := proto.Marshal(&models.TendermintHeader{Height: 11, AppHash: []byte{1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Store.Tree(vochain.AppTree).Add([]byte("header"), header); err != nil {
+		t.Fatal(err)
+	}
+	state.Save()
+	sc.Rollback()
+
+	// The document refers to the end block, not to the height where the
+	// results are computed
+	header, err = proto.Marshal(&models.TendermintHeader{Height: 12, AppHash: []byte{4, 5, 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Store.Tree(vochain.AppTree).Add([]byte("header"), header); err != nil {
+		t.Fatal(err)
+	}
+	state.Save()
+
+	if err := sc.ComputeResult(pid); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := sc.ResultsDocument(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.VoteCount != 3 || doc.EndBlock != 11 || doc.Height != 11 ||
+		!bytes.Equal(doc.AppHash, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	// and it is not modified by a reindex
+	if _, err := sc.Reindex(false); err != nil {
+		t.Fatal(err)
+	}
+	reindexed, err := sc.ResultsDocument(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reindexed.Height != doc.Height || !bytes.Equal(reindexed.AppHash, doc.AppHash) {
+		t.Fatalf("document changed after reindex: %+v", reindexed)
+	}
+	if doc.Results[0][1] != "3" || doc.Results[1][2] != "3" {
+		t.Fatalf("unexpected results: %v", doc.Results)
+	}
+
+	signer := ethereum.NewSignKeys()
+	if err := signer.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{DocumentJSON, DocumentCSV} {
+		content, signature, err := doc.Sign(signer, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := ethereum.AddrFromSignature(content, signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != signer.Address() {
+			t.Fatalf("%s: signature address mismatch", format)
+		}
+	}
+	content, err := doc.Encode(DocumentCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := records[len(records)-1]; got[0] != "votes" || got[1] != "1" || got[2] != "2" || got[3] != "3" {
+		t.Fatalf("unexpected last CSV record: %v", got)
+	}
+	if _, err := doc.Encode("xml"); err == nil {
+		t.Fatal("expected error on unknown format")
+	}
+}
+
+func TestResultsSeries(t *testing.T) {
+	log.Init("info", "stdout")
+	state, err := vochain.NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewScrutinizer(t.TempDir(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.SnapshotInterval = 2
+	pid := util.RandomBytes(32)
+	if err := state.AddProcess(&models.Process{
+		ProcessId:    pid,
+		EntityId:     util.RandomBytes(20),
+		EnvelopeType: &models.EnvelopeType{EncryptedVotes: false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sc.Commit(1)
+	sc.Rollback()
+
+	vp, err := json.Marshal(types.VotePackage{Votes: []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One vote on each block from 2 to 6
+	for height := int64(2); height <= 6; height++ {
+		if err := state.AddVote(&models.Vote{
+			ProcessId:   pid,
+			VotePackage: vp,
+			Nullifier:   util.RandomBytes(32),
+			Weight:      big.NewInt(1).Bytes(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		sc.Commit(height)
+		sc.Rollback()
+	}
+
+	series, err := sc.ResultsSeries(pid, 0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Snapshots every 2 blocks
+	expected := map[uint32]string{2: "1", 4: "3", 6: "5"}
+	if len(series) != len(expected) {
+		t.Fatalf("expected %d snapshots, got %d", len(expected), len(series))
+	}
+	for _, snapshot := range series {
+		if votes := sc.GetFriendlyResults(snapshot.Results)[0][1]; votes != expected[snapshot.Height] {
+			t.Fatalf("snapshot at height %d: expected %s votes, got %s",
+				snapshot.Height, expected[snapshot.Height], votes)
+		}
+	}
+	if series, err = sc.ResultsSeries(pid, 3, 5, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Height != 4 {
+		t.Fatalf("unexpected series between heights 3 and 5: %+v", series)
+	}
+}
@@ -0,0 +1,244 @@
+package scrutinizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+
+	"go.vocdoni.io/dvote/db"
+	"go.vocdoni.io/dvote/log"
+)
+
+// ProcessFilter holds the criteria for searching processes.
+// Zero values (or nil) match any process.
+type ProcessFilter struct {
+	EntityID     []byte
+	Status       models.ProcessStatus
+	CensusOrigin models.CensusOrigin
+	// Envelope type flags
+	Serial         *bool
+	Anonymous      *bool
+	EncryptedVotes *bool
+	UniqueValues   *bool
+	// Block ranges (inclusive)
+	StartBlockFrom uint32
+	StartBlockTo   uint32
+	EndBlockFrom   uint32
+	EndBlockTo     uint32
+}
+
+// Match returns true if the process summary matches all the filter criteria
+func (f *ProcessFilter) Match(p *models.Process) bool {
+	if len(f.EntityID) > 0 && !bytes.Equal(f.EntityID, p.EntityId) {
+		return false
+	}
+	if f.Status != models.ProcessStatus_PROCESS_UNKNOWN && f.Status != p.Status {
+		return false
+	}
+	if f.CensusOrigin != models.CensusOrigin_CENSUS_UNKNOWN && f.CensusOrigin != p.CensusOrigin {
+		return false
+	}
+	et := p.GetEnvelopeType()
+	if !matchFlag(f.Serial, et.GetSerial()) || !matchFlag(f.Anonymous, et.GetAnonymous()) ||
+		!matchFlag(f.EncryptedVotes, et.GetEncryptedVotes()) || !matchFlag(f.UniqueValues, et.GetUniqueValues()) {
+		return false
+	}
+	endBlock := p.StartBlock + p.BlockCount
+	return inRange(p.StartBlock, f.StartBlockFrom, f.StartBlockTo) &&
+		inRange(endBlock, f.EndBlockFrom, f.EndBlockTo)
+}
+
+func matchFlag(want *bool, have bool) bool {
+	return want == nil || *want == have
+}
+
+func inRange(n, from, to uint32) bool {
+	return n >= from && (to == 0 || n <= to)
+}
+
+// Secondary index kinds, each key of the process index is the kind followed
+// by the indexed value, the end block and the process ID, so the processes
+// of an index are sorted by end block.
+const (
+	indexAll    = byte('a')
+	indexStatus = byte('s')
+	indexOrigin = byte('o')
+	indexEntity = byte('e')
+)
+
+// endBlock returns the block where the process ends
+func endBlock(p *models.Process) uint32 {
+	return p.StartBlock + p.BlockCount
+}
+
+// indexPrefix returns the prefix of the index kind for value
+func indexPrefix(kind byte, value []byte) []byte {
+	prefix := []byte{kind}
+	if kind == indexEntity {
+		prefix = append(prefix, byte(len(value)))
+	}
+	return append(prefix, value...)
+}
+
+// indexEntry returns the index key under prefix for the process pid ending
+// at end
+func indexEntry(prefix []byte, end uint32, pid []byte) []byte {
+	key := append(append([]byte{}, prefix...), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(key[len(prefix):], end)
+	return append(key, pid...)
+}
+
+// processIndexKeys returns the keys of all the secondary indexes for the
+// process summary p
+func processIndexKeys(p *models.Process) [][]byte {
+	end := endBlock(p)
+	return [][]byte{
+		indexEntry(indexPrefix(indexAll, nil), end, p.ProcessId),
+		indexEntry(indexPrefix(indexStatus, []byte{byte(p.Status)}), end, p.ProcessId),
+		indexEntry(indexPrefix(indexOrigin, []byte{byte(p.CensusOrigin)}), end, p.ProcessId),
+		indexEntry(indexPrefix(indexEntity, p.EntityId), end, p.ProcessId),
+	}
+}
+
+// index returns the prefix of the most selective secondary index for the
+// filter
+func (f *ProcessFilter) index() []byte {
+	switch {
+	case f == nil:
+		return indexPrefix(indexAll, nil)
+	case len(f.EntityID) > 0:
+		return indexPrefix(indexEntity, f.EntityID)
+	case f.Status != models.ProcessStatus_PROCESS_UNKNOWN:
+		return indexPrefix(indexStatus, []byte{byte(f.Status)})
+	case f.CensusOrigin != models.CensusOrigin_CENSUS_UNKNOWN:
+		return indexPrefix(indexOrigin, []byte{byte(f.CensusOrigin)})
+	}
+	return indexPrefix(indexAll, nil)
+}
+
+// SearchProcesses returns up to max summaries of the processes matching the
+// filter, ordered by end block and process ID. If fromID is specified the
+// search starts after it, so the last process ID returned can be used as a
+// cursor. The search seeks on the secondary index of the entity, status or
+// census origin and the end block range, the rest of the criteria are checked
+// on the summaries found. Summaries contain only the fields needed for
+// searching.
+func (s *Scrutinizer) SearchProcesses(filter *ProcessFilter, fromID []byte, max int64) ([]*models.Process, error) {
+	prefix := s.Encode("processIndex", filter.index())
+	seek := prefix
+	var endFrom, endTo uint32
+	if filter != nil {
+		endFrom, endTo = filter.EndBlockFrom, filter.EndBlockTo
+	}
+	if endFrom > 0 {
+		seek = indexEntry(prefix, endFrom, nil)
+	}
+	var from []byte
+	if len(fromID) > 0 {
+		cursor, err := s.processSummary(fromID)
+		if err != nil {
+			return nil, fmt.Errorf("cursor process %x not found: %w", fromID, err)
+		}
+		from = indexEntry(prefix, endBlock(cursor), fromID)
+		if bytes.Compare(from, seek) > 0 {
+			seek = from
+		}
+	}
+
+	iter := s.Storage.NewIterator().(*db.BadgerIterator) // TODO(mvdan): don't type assert
+	defer iter.Release()
+	list := []*models.Process{}
+	for iter.Iter.Seek(seek); iter.Iter.ValidForPrefix(prefix) && max > 0; iter.Iter.Next() {
+		key := iter.Key()
+		if bytes.Equal(key, from) {
+			// We don't include "from" in the result.
+			continue
+		}
+		if len(key) < len(prefix)+4 {
+			return nil, fmt.Errorf("malformed process index key %x", key)
+		}
+		if endTo > 0 && binary.BigEndian.Uint32(key[len(prefix):]) > endTo {
+			break
+		}
+		p, err := s.processSummary(key[len(prefix)+4:])
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil && !filter.Match(p) {
+			continue
+		}
+		list = append(list, p)
+		max--
+	}
+	return list, nil
+}
+
+// processSummary returns the indexed summary of the process pid
+func (s *Scrutinizer) processSummary(pid []byte) (*models.Process, error) {
+	value, err := s.Storage.Get(s.Encode("process", pid))
+	if err != nil {
+		return nil, err
+	}
+	p := new(models.Process)
+	if err := proto.Unmarshal(value, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// newProcessSummary returns a copy of the process fields kept in the search index
+func newProcessSummary(p *models.Process) *models.Process {
+	summary := &models.Process{
+		ProcessId:    p.ProcessId,
+		EntityId:     p.EntityId,
+		StartBlock:   p.StartBlock,
+		BlockCount:   p.BlockCount,
+		Status:       p.Status,
+		CensusOrigin: p.CensusOrigin,
+	}
+	if p.EnvelopeType != nil {
+		summary.EnvelopeType = proto.Clone(p.EnvelopeType).(*models.EnvelopeType)
+	}
+	return summary
+}
+
+// indexProcess stores or updates the process summary in the search index
+func (s *Scrutinizer) indexProcess(pid []byte) {
+	p, err := s.ProcessInfo(pid)
+	if err != nil {
+		log.Errorf("cannot index process %x: (%s)", pid, err)
+		return
+	}
+	summary := newProcessSummary(p)
+	value, err := proto.Marshal(summary)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	batch := s.Storage.NewBatch()
+	// the index keys change with the status or the end block
+	if old, err := s.processSummary(pid); err == nil {
+		for _, key := range processIndexKeys(old) {
+			if err := batch.Del(s.Encode("processIndex", key)); err != nil {
+				log.Error(err)
+				return
+			}
+		}
+	}
+	if err := batch.Put(s.Encode("process", pid), value); err != nil {
+		log.Error(err)
+		return
+	}
+	for _, key := range processIndexKeys(summary) {
+		if err := batch.Put(s.Encode("processIndex", key), nil); err != nil {
+			log.Error(err)
+			return
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Error(err)
+	}
+}
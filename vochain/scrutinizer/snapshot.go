@@ -0,0 +1,108 @@
+package scrutinizer
+
+import (
+	"encoding/binary"
+
+	"go.vocdoni.io/proto/build/go/models"
+	"google.golang.org/protobuf/proto"
+
+	"go.vocdoni.io/dvote/db"
+	"go.vocdoni.io/dvote/log"
+)
+
+// DefaultSnapshotInterval is the default minimum number of blocks between two
+// live results snapshots of a process
+const DefaultSnapshotInterval = 1
+
+// ResultsSnapshot is the running tally of a live results process at a height
+type ResultsSnapshot struct {
+	Height  uint32
+	Results *models.ProcessResult
+}
+
+// snapshotKey returns the storage key of a snapshot. The height is encoded in
+// big endian so the snapshots of a process are sorted by height.
+func (s *Scrutinizer) snapshotKey(pid []byte, height uint32) []byte {
+	key := make([]byte, len(pid)+4)
+	copy(key, pid)
+	binary.BigEndian.PutUint32(key[len(pid):], height)
+	return s.Encode("snapshot", key)
+}
+
+// addSnapshotVotes marks the processes which received live votes on the
+// block, and stores a snapshot of the ones whose last snapshot is at least
+// SnapshotInterval blocks old. Processes not stored are kept pending.
+func (s *Scrutinizer) addSnapshotVotes(height int64, pids map[string]bool) {
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+	for pid := range pids {
+		s.snapshotPending[pid] = true
+	}
+	for pid := range s.snapshotPending {
+		if last, ok := s.lastSnapshot[pid]; ok && height-last < int64(s.SnapshotInterval) {
+			continue
+		}
+		pv, err := s.computeLiveResults([]byte(pid))
+		if err != nil {
+			log.Errorf("cannot get live results of process %x for snapshot: (%s)", pid, err)
+			continue
+		}
+		if err := s.storeSnapshot([]byte(pid), uint32(height), pv); err != nil {
+			log.Errorf("cannot store results snapshot of process %x: (%s)", pid, err)
+			continue
+		}
+		s.lastSnapshot[pid] = height
+		delete(s.snapshotPending, pid)
+	}
+}
+
+// finalSnapshot stores the last snapshot of a live results process once its
+// results are computed, so the series always ends with the final results
+func (s *Scrutinizer) finalSnapshot(pid []byte, pv *models.ProcessResult) {
+	s.snapshotLock.Lock()
+	defer s.snapshotLock.Unlock()
+	delete(s.snapshotPending, string(pid))
+	delete(s.lastSnapshot, string(pid))
+	header := s.VochainState.Header(false)
+	if header == nil {
+		log.Errorf("cannot get vochain header for the final snapshot of process %x", pid)
+		return
+	}
+	if err := s.storeSnapshot(pid, uint32(header.Height), pv); err != nil {
+		log.Errorf("cannot store results snapshot of process %x: (%s)", pid, err)
+	}
+}
+
+func (s *Scrutinizer) storeSnapshot(pid []byte, height uint32, pv *models.ProcessResult) error {
+	value, err := proto.Marshal(pv)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Put(s.snapshotKey(pid, height), value)
+}
+
+// ResultsSeries returns up to max live results snapshots of a process between
+// the heights from and to (inclusive), sorted by height. If to is zero there
+// is no upper limit.
+func (s *Scrutinizer) ResultsSeries(pid []byte, from, to uint32, max int) ([]*ResultsSnapshot, error) {
+	// Check if process exist
+	if _, err := s.VochainState.Process(pid, false); err != nil {
+		return nil, err
+	}
+	prefix := s.Encode("snapshot", pid)
+	iter := s.Storage.NewIterator().(*db.BadgerIterator) // TODO(mvdan): don't type assert
+	defer iter.Release()
+	series := []*ResultsSnapshot{}
+	for iter.Iter.Seek(s.snapshotKey(pid, from)); iter.Iter.ValidForPrefix(prefix) && len(series) < max; iter.Iter.Next() {
+		height := binary.BigEndian.Uint32(iter.Key()[len(prefix):])
+		if to > 0 && height > to {
+			break
+		}
+		pv := new(models.ProcessResult)
+		if err := proto.Unmarshal(iter.Value(), pv); err != nil {
+			return nil, err
+		}
+		series = append(series, &ResultsSnapshot{Height: height, Results: pv})
+	}
+	return series, nil
+}
@@ -0,0 +1,137 @@
+package scrutinizer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/dgraph-io/badger/v2"
+	"go.vocdoni.io/proto/build/go/models"
+
+	"go.vocdoni.io/dvote/db"
+)
+
+// ProcessStats holds the participation statistics of a process
+type ProcessStats struct {
+	// Envelopes is the number of vote envelopes received
+	Envelopes uint64 `json:"envelopes"`
+	// InvalidEnvelopes is the number of envelopes which could not be decrypted
+	// or decoded. For encrypted processes it is only known once the results
+	// have been computed.
+	InvalidEnvelopes uint64 `json:"invalidEnvelopes"`
+	// TotalWeight is the sum of the weight of all the envelopes
+	TotalWeight *big.Int `json:"totalWeight"`
+	// VotesPerBlock is the number of envelopes included on each block height.
+	// Each block count is stored under its own key, so storing the stats does
+	// not get slower as the process receives votes on more blocks.
+	VotesPerBlock map[uint32]uint64 `json:"-"`
+}
+
+func newProcessStats() *ProcessStats {
+	return &ProcessStats{
+		TotalWeight:   new(big.Int),
+		VotesPerBlock: make(map[uint32]uint64),
+	}
+}
+
+func (st *ProcessStats) addEnvelope(v *models.Vote) {
+	st.Envelopes++
+	st.TotalWeight.Add(st.TotalWeight, new(big.Int).SetBytes(v.GetWeight()))
+	st.VotesPerBlock[v.Height]++
+}
+
+// Turnout returns the percentage of the census which has voted, given the
+// census size. If the census size is unknown (zero) it returns zero.
+func (st *ProcessStats) Turnout(censusSize int64) float64 {
+	if censusSize <= 0 {
+		return 0
+	}
+	return 100 * float64(st.Envelopes) / float64(censusSize)
+}
+
+// ProcessStats returns the participation statistics of a process
+func (s *Scrutinizer) ProcessStats(pid []byte) (*ProcessStats, error) {
+	// Check if process exist
+	if _, err := s.VochainState.Process(pid, false); err != nil {
+		return nil, err
+	}
+	st, err := s.loadStats(pid)
+	if err != nil {
+		return nil, err
+	}
+	prefix := s.Encode("blockVotes", pid)
+	iter := s.Storage.NewIterator().(*db.BadgerIterator) // TODO(mvdan): don't type assert
+	defer iter.Release()
+	for iter.Iter.Seek(prefix); iter.Iter.ValidForPrefix(prefix); iter.Iter.Next() {
+		height := binary.BigEndian.Uint32(iter.Key()[len(prefix):])
+		st.VotesPerBlock[height] = binary.BigEndian.Uint64(iter.Value())
+	}
+	return st, nil
+}
+
+// blockVotesKey returns the storage key of the number of votes of a process on
+// a block. The height is encoded in big endian so the counts of a process are
+// sorted by height.
+func (s *Scrutinizer) blockVotesKey(pid []byte, height uint32) []byte {
+	key := make([]byte, len(pid)+4)
+	copy(key, pid)
+	binary.BigEndian.PutUint32(key[len(pid):], height)
+	return s.Encode("blockVotes", key)
+}
+
+// addBlockVotes adds the number of votes of a process on each block height to
+// the stored ones
+func (s *Scrutinizer) addBlockVotes(pid []byte, votes map[uint32]uint64) error {
+	for height, n := range votes {
+		key := s.blockVotesKey(pid, height)
+		value, err := s.Storage.Get(key)
+		if err != nil && err != badger.ErrKeyNotFound {
+			return err
+		}
+		if len(value) == 8 {
+			n += binary.BigEndian.Uint64(value)
+		}
+		value = make([]byte, 8)
+		binary.BigEndian.PutUint64(value, n)
+		if err := s.Storage.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadStats returns the stored statistics of a process, or empty ones if the
+// process has not received any envelope yet. The votes per block are not
+// loaded.
+func (s *Scrutinizer) loadStats(pid []byte) (*ProcessStats, error) {
+	st := newProcessStats()
+	data, err := s.Storage.Get(s.Encode("stats", pid))
+	if err == badger.ErrKeyNotFound {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (s *Scrutinizer) storeStats(pid []byte, st *ProcessStats) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Put(s.Encode("stats", pid), data)
+}
+
+// setInvalidEnvelopes updates the number of invalid envelopes of a process
+func (s *Scrutinizer) setInvalidEnvelopes(pid []byte, invalid uint64) error {
+	st, err := s.loadStats(pid)
+	if err != nil {
+		return err
+	}
+	st.InvalidEnvelopes = invalid
+	return s.storeStats(pid, st)
+}
@@ -46,14 +46,19 @@ func (s *Scrutinizer) ComputeResult(processID []byte) error {
 		if pv, err = s.computeLiveResults(processID); err != nil {
 			return err
 		}
+		s.finalSnapshot(processID, pv)
 		// Delete liveResults temporary storage
 		if err = s.Storage.Del(s.Encode("liveProcess", processID)); err != nil {
 			return err
 		}
 	} else {
-		if pv, err = s.computeNonLiveResults(p); err != nil {
+		var invalid uint64
+		if pv, invalid, err = s.computeNonLiveResults(p); err != nil {
 			return err
 		}
+		if err := s.setInvalidEnvelopes(processID, invalid); err != nil {
+			log.Errorf("cannot update stats for process %x: (%s)", processID, err)
+		}
 	}
 
 	// add results if process is not live or isLive and status is ended
@@ -70,7 +75,13 @@ func (s *Scrutinizer) ComputeResult(processID []byte) error {
 		return err
 	}
 
-	return s.Storage.Put(s.Encode("results", processID), result)
+	if err := s.Storage.Put(s.Encode("results", processID), result); err != nil {
+		return err
+	}
+	if _, err := s.createResultsDocument(processID); err != nil {
+		log.Errorf("cannot create results document for process %x: (%s)", processID, err)
+	}
+	return nil
 }
 
 // VoteResult returns the current result for a processId summarized in a two dimension int slice
@@ -107,6 +118,17 @@ func (s *Scrutinizer) VoteResult(processID []byte) (*models.ProcessResult, error
 	return s.computeLiveResults(processID)
 }
 
+// ResultsAvailable returns whether VoteResult has results for a process,
+// either the final ones or the live results of a non encrypted process
+func (s *Scrutinizer) ResultsAvailable(processID []byte) (bool, error) {
+	if _, err := s.Storage.Get(s.Encode("results", processID)); err == nil {
+		return true, nil
+	} else if err != badger.ErrKeyNotFound {
+		return false, err
+	}
+	return s.isLiveResultsProcess(processID)
+}
+
 // PrintResults returns a human friendly interpretation of the results.
 func PrintResults(r *models.ProcessResult) (results string) {
 	value := new(big.Int)
@@ -197,47 +219,51 @@ func (s *Scrutinizer) computeLiveResults(processID []byte) (*models.ProcessResul
 	return pruneVoteResult(pv), nil
 }
 
-func (s *Scrutinizer) computeNonLiveResults(p *models.Process) (*models.ProcessResult, error) {
+// computeNonLiveResults computes the results of a process iterating over all
+// its envelopes. It returns the results and the number of invalid envelopes.
+func (s *Scrutinizer) computeNonLiveResults(p *models.Process) (*models.ProcessResult, uint64, error) {
 	pv := emptyProcess(0, 0)
 	var nvotes int
+	var invalid uint64
 	for _, e := range s.VochainState.EnvelopeList(p.ProcessId, 0, 32<<18, false) { // 8.3M seems enough for now
 		vote, err := s.VochainState.Envelope(p.ProcessId, e, false)
 		if err != nil {
 			log.Warn(err)
 			continue
 		}
-		var vp *types.VotePackage
-		err = nil
-		if p.EnvelopeType.EncryptedVotes {
-			if len(p.EncryptionPrivateKeys) < len(vote.EncryptionKeyIndexes) {
-				err = fmt.Errorf("encryptionKeyIndexes has too many fields")
-			} else {
-				keys := []string{}
-				for _, k := range vote.EncryptionKeyIndexes {
-					if k >= types.KeyKeeperMaxKeyIndex {
-						err = fmt.Errorf("key index overflow")
-						break
-					}
-					keys = append(keys, p.EncryptionPrivateKeys[k])
-				}
-				if len(keys) == 0 || err != nil {
-					err = fmt.Errorf("no keys provided or wrong index")
-				} else {
-					vp, err = unmarshalVote(vote.VotePackage, keys)
-				}
-			}
-		} else {
-			vp, err = unmarshalVote(vote.VotePackage, []string{})
-		}
+		vp, err := decodeVote(p, vote)
 		if err != nil {
 			log.Warn(err)
+			invalid++
 			continue
 		}
 		addVote(pv.Votes, vp.Votes, vote.GetWeight())
 		nvotes++
 	}
-	log.Infof("computed results for process %x with %d votes", p.ProcessId, nvotes)
-	return pruneVoteResult(pv), nil
+	log.Infof("computed results for process %x with %d votes (%d invalid)", p.ProcessId, nvotes, invalid)
+	return pruneVoteResult(pv), invalid, nil
+}
+
+// decodeVote decodes the vote package of an envelope, decrypting it with the
+// revealed process keys if the process has encrypted votes.
+func decodeVote(p *models.Process, vote *models.Vote) (*types.VotePackage, error) {
+	if !p.GetEnvelopeType().GetEncryptedVotes() {
+		return unmarshalVote(vote.VotePackage, []string{})
+	}
+	if len(p.EncryptionPrivateKeys) < len(vote.EncryptionKeyIndexes) {
+		return nil, fmt.Errorf("encryptionKeyIndexes has too many fields")
+	}
+	keys := []string{}
+	for _, k := range vote.EncryptionKeyIndexes {
+		if k >= types.KeyKeeperMaxKeyIndex {
+			return nil, fmt.Errorf("key index overflow")
+		}
+		keys = append(keys, p.EncryptionPrivateKeys[k])
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys provided or wrong index")
+	}
+	return unmarshalVote(vote.VotePackage, keys)
 }
 
 func addVote(currentResults []*models.QuestionResult, voteValues []int, weight []byte) {
@@ -2,6 +2,7 @@ package vochain
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -29,9 +30,10 @@ const (
 
 var (
 	// keys; not constants because of []byte
-	headerKey    = []byte("header")
-	oracleKey    = []byte("oracle")
-	validatorKey = []byte("validator")
+	headerKey               = []byte("header")
+	oracleKey               = []byte("oracle")
+	validatorKey            = []byte("validator")
+	weightedCensusHeightKey = []byte("weightedCensusHeight")
 )
 
 var (
@@ -77,6 +79,18 @@ type ImmutableState struct {
 
 // NewState creates a new State
 func NewState(dataDir string) (*State, error) {
+	// Must be -1 in order to get the last committed block state, if not block replay will fail
+	return newState(dataDir, -1)
+}
+
+// NewStateLastVersion opens the State at its last committed version, without
+// discarding it for the block replay as NewState does. It is meant for offline
+// tools working over the data of a stopped node.
+func NewStateLastVersion(dataDir string) (*State, error) {
+	return newState(dataDir, 0)
+}
+
+func newState(dataDir string, version int64) (*State, error) {
 	var err error
 	vs := &State{}
 	//vs.Store = new(gravitonstate.GravitonState)
@@ -96,8 +110,7 @@ func NewState(dataDir string) (*State, error) {
 		return nil, err
 	}
 
-	// Must be -1 in order to get the last committed block state, if not block replay will fail
-	if err = vs.Store.LoadVersion(-1); err != nil {
+	if err = vs.Store.LoadVersion(version); err != nil {
 		return nil, err
 	}
 
@@ -471,6 +484,44 @@ func (v *State) EnvelopeList(processID []byte, from, listSize int64, isQuery boo
 	return nullifiers
 }
 
+// SetWeightedCensusHeight sets the height from which the votes of the
+// OFF_CHAIN_TREE_WEIGHTED processes are accepted, zero disables them
+func (v *State) SetWeightedCensusHeight(height uint32) error {
+	v.Lock()
+	defer v.Unlock()
+	heightBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(heightBytes, height)
+	return v.Store.Tree(AppTree).Add(weightedCensusHeightKey, heightBytes)
+}
+
+// WeightedCensusHeight returns the height from which the votes of the
+// OFF_CHAIN_TREE_WEIGHTED processes are accepted, zero if they are disabled
+func (v *State) WeightedCensusHeight(isQuery bool) uint32 {
+	var heightBytes []byte
+	v.RLock()
+	if isQuery {
+		heightBytes = v.Store.ImmutableTree(AppTree).Get(weightedCensusHeightKey)
+	} else {
+		heightBytes = v.Store.Tree(AppTree).Get(weightedCensusHeightKey)
+	}
+	v.RUnlock()
+	if len(heightBytes) != 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(heightBytes)
+}
+
+// weightedCensusEnabled returns true if the weighted off-chain censuses are
+// enabled at the current height
+func (v *State) weightedCensusEnabled() bool {
+	height := v.WeightedCensusHeight(false)
+	if height == 0 {
+		return false
+	}
+	header := v.Header(false)
+	return header != nil && header.Height >= int64(height)
+}
+
 // Header returns the blockchain last block committed height
 func (v *State) Header(isQuery bool) *models.TendermintHeader {
 	var headerBytes []byte
@@ -543,3 +594,57 @@ func (v *State) WorkingHash() []byte {
 	defer v.RUnlock()
 	return v.Store.Hash()
 }
+
+// StateProof is a merkle proof of a key in one of the committed state trees.
+// It includes the roots of all the state trees, so the proof can be linked to
+// the AppHash of the block header at Height+1.
+type StateProof struct {
+	Height int64
+	Tree   string
+	Key    []byte
+	Value  []byte
+	Proof  []byte
+	Roots  map[string][]byte
+}
+
+// ProcessProof returns a proof of the process in the last committed state
+func (v *State) ProcessProof(pid []byte) (*StateProof, error) {
+	return v.proof(ProcessTree, pid)
+}
+
+// EnvelopeProof returns a proof of the vote envelope in the last committed state
+func (v *State) EnvelopeProof(processID, nullifier []byte) (*StateProof, error) {
+	vid, err := v.voteID(processID, nullifier)
+	if err != nil {
+		return nil, err
+	}
+	return v.proof(VoteTree, vid)
+}
+
+func (v *State) proof(tree string, key []byte) (_ *StateProof, err error) {
+	// TODO(mvdan): remove the recover once
+	// https://github.com/tendermint/iavl/issues/212 is fixed
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered panic: %v", r)
+		}
+	}()
+	v.RLock()
+	defer v.RUnlock()
+	sp := &StateProof{Tree: tree, Key: key, Roots: make(map[string][]byte, 3)}
+	if sp.Value = v.Store.ImmutableTree(tree).Get(key); sp.Value == nil {
+		return nil, fmt.Errorf("key %x not found in tree %s", key, tree)
+	}
+	if sp.Proof, err = v.Store.ImmutableTree(tree).Proof(key); err != nil {
+		return nil, err
+	}
+	for _, t := range []string{AppTree, ProcessTree, VoteTree} {
+		sp.Roots[t] = v.Store.ImmutableTree(t).Hash()
+	}
+	var header models.TendermintHeader
+	if err := proto.Unmarshal(v.Store.ImmutableTree(AppTree).Get(headerKey), &header); err != nil {
+		return nil, fmt.Errorf("cannot get vochain height: %w", err)
+	}
+	sp.Height = header.Height
+	return sp, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/statedb/iavlstate"
 	"go.vocdoni.io/dvote/util"
 	models "go.vocdoni.io/proto/build/go/models"
 )
@@ -63,3 +64,40 @@ func TestState(t *testing.T) {
 	}
 
 }
+
+func TestStateProof(t *testing.T) {
+	s, err := NewState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := util.RandomBytes(32)
+	censusURI := "ipfs://foobar"
+	if err := s.AddProcess(&models.Process{EntityId: util.RandomBytes(32), CensusURI: &censusURI, ProcessId: pid}); err != nil {
+		t.Fatal(err)
+	}
+	nullifier := util.RandomBytes(32)
+	if err := s.AddVote(&models.Vote{ProcessId: pid, Nullifier: nullifier}); err != nil {
+		t.Fatal(err)
+	}
+	hash := s.Save()
+
+	for _, getProof := range []func() (*StateProof, error){
+		func() (*StateProof, error) { return s.ProcessProof(pid) },
+		func() (*StateProof, error) { return s.EnvelopeProof(pid, nullifier) },
+	} {
+		sp, err := getProof()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := iavlstate.VerifyProof(sp.Key, sp.Value, sp.Proof, sp.Roots[sp.Tree]); err != nil {
+			t.Errorf("invalid %s proof: %v", sp.Tree, err)
+		}
+		if string(iavlstate.RootsHash(sp.Roots)) != string(hash) {
+			t.Errorf("state roots do not match the state hash")
+		}
+	}
+
+	if _, err := s.ProcessProof(util.RandomBytes(32)); err == nil {
+		t.Errorf("proof of a non existing process must fail")
+	}
+}
@@ -210,8 +210,12 @@ func VoteTxCheck(vtx *models.Tx, state *State, txID [32]byte, forCommit bool) (*
 				}
 
 				// check census origin and compute vote digest identifier
+				if process.CensusOrigin == models.CensusOrigin_OFF_CHAIN_TREE_WEIGHTED &&
+					!state.weightedCensusEnabled() {
+					return nil, fmt.Errorf("census origin not compatible")
+				}
 				switch process.CensusOrigin {
-				case models.CensusOrigin_OFF_CHAIN_TREE:
+				case models.CensusOrigin_OFF_CHAIN_TREE, models.CensusOrigin_OFF_CHAIN_TREE_WEIGHTED:
 					vp.PubKeyDigest = snarks.Poseidon.Hash(vp.PubKey)
 				case models.CensusOrigin_OFF_CHAIN_CA:
 					vp.PubKeyDigest = addr.Bytes()